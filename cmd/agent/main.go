@@ -66,6 +66,7 @@ var (
 	enableBatcher = flag.Bool("enable-batcher", false, "Enable request batcher")
 	maxBatchSize  = flag.String("max-batchsize", "32", "Max Batch Size")
 	maxLatency    = flag.String("max-latency", "5000", "Max Latency in milliseconds")
+	targetLatency = flag.String("target-latency", "0", "Target p99 latency in milliseconds, the batch window adapts to meet it when set")
 	// probing flags
 	readinessProbeTimeout = flag.Duration("probe-period", -1, "run readiness probe with given timeout")
 	// This creates an abstract socket instead of an actual file.
@@ -108,8 +109,9 @@ type loggerArgs struct {
 }
 
 type batcherArgs struct {
-	maxBatchSize int
-	maxLatency   int
+	maxBatchSize  int
+	maxLatency    int
+	targetLatency int
 }
 
 func main() {
@@ -230,9 +232,16 @@ func startBatcher(logger *zap.SugaredLogger) *batcherArgs {
 		os.Exit(1)
 	}
 
+	targetLatencyInt, err := strconv.Atoi(*targetLatency)
+	if err != nil || targetLatencyInt < 0 {
+		logger.Error(errors.New("Invalid target latency"), *targetLatency)
+		os.Exit(1)
+	}
+
 	return &batcherArgs{
-		maxLatency:   maxLatencyInt,
-		maxBatchSize: maxBatchSizeInt,
+		maxLatency:    maxLatencyInt,
+		maxBatchSize:  maxBatchSizeInt,
+		targetLatency: targetLatencyInt,
 	}
 }
 
@@ -320,7 +329,8 @@ func buildServer(ctx context.Context, port string, userPort string, loggerArgs *
 	var composedHandler http.Handler = httpProxy
 
 	if batcherArgs != nil {
-		composedHandler = batcher.New(batcherArgs.maxBatchSize, batcherArgs.maxLatency, composedHandler, logging)
+		composedHandler = batcher.NewAdaptive(batcherArgs.maxBatchSize, batcherArgs.maxLatency, batcherArgs.targetLatency,
+			composedHandler, logging)
 	}
 	if loggerArgs != nil {
 		composedHandler = kfslogger.New(loggerArgs.logUrl, loggerArgs.sourceUrl, loggerArgs.loggerType,
@@ -40,6 +40,7 @@ import (
 	"k8s.io/client-go/tools/record"
 	knservingv1 "knative.dev/serving/pkg/apis/serving/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -112,6 +113,15 @@ func main() {
 		Port:               options.webhookPort,
 		LeaderElection:     options.enableLeaderElection,
 		LeaderElectionID:   LeaderLockName,
+		Cache: cache.Options{
+			ByObject: map[client.Object]cache.ByObject{
+				&v1.ConfigMap{}: {Label: graphcontroller.GraphConfigMapSelector()},
+			},
+		},
+		// the ConfigMaps which are not cached, like the inferenceservice config, are read from the api server
+		Client: client.Options{
+			Cache: &client.CacheOptions{DisableFor: []client.Object{&v1.ConfigMap{}}},
+		},
 	})
 	if err != nil {
 		log.Error(err, "unable to set up overall controller manager")
@@ -0,0 +1,98 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	"github.com/kserve/kserve/pkg/constants"
+)
+
+const authorizationHeader = "Authorization"
+
+// authTokensDir and authSecretsDir are where the reconciler mounts the ServiceAccount tokens and secrets of the steps
+var (
+	authTokensDir  = constants.InferenceGraphAuthTokensDir
+	authSecretsDir = constants.InferenceGraphAuthSecretsDir
+)
+
+// authHeader is the credentials header sent to a step service
+type authHeader struct {
+	name  string
+	value string
+}
+
+type authContextKey struct{}
+
+func withAuthHeader(ctx context.Context, header *authHeader) context.Context {
+	return context.WithValue(ctx, authContextKey{}, header)
+}
+
+func authHeaderFromContext(ctx context.Context) *authHeader {
+	header, _ := ctx.Value(authContextKey{}).(*authHeader)
+	return header
+}
+
+// stepAuthHeader returns the credentials header of the step, nil when the step has no auth or the caller sent no
+// token to forward. The mounted tokens and secrets are read on every call so that rotated credentials are picked up.
+func stepAuthHeader(step *v1alpha1.InferenceStep, headers http.Header) (*authHeader, error) {
+	auth := step.Auth
+	if auth == nil {
+		return nil, nil
+	}
+	switch auth.Type {
+	case v1alpha1.ForwardToken:
+		if value := headers.Get(authorizationHeader); value != "" {
+			return &authHeader{name: authorizationHeader, value: value}, nil
+		}
+		return nil, nil
+	case v1alpha1.ServiceAccountToken:
+		token, err := readCredential(filepath.Join(authTokensDir, constants.InferenceGraphAuthTokenFileName(auth.Audience)))
+		if err != nil {
+			return nil, err
+		}
+		return &authHeader{name: authorizationHeader, value: "Bearer " + token}, nil
+	case v1alpha1.SecretHeader:
+		if auth.SecretKeyRef == nil {
+			return nil, fmt.Errorf("step %s has SecretHeader auth without a secretKeyRef", step.StepName)
+		}
+		value, err := readCredential(filepath.Join(authSecretsDir, auth.SecretKeyRef.Name, auth.SecretKeyRef.Key))
+		if err != nil {
+			return nil, err
+		}
+		name := auth.Header
+		if name == "" {
+			name = authorizationHeader
+		}
+		return &authHeader{name: name, value: value}, nil
+	}
+	return nil, fmt.Errorf("step %s has unsupported auth type %s", step.StepName, auth.Type)
+}
+
+func readCredential(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the step credentials: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
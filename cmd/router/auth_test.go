@@ -0,0 +1,104 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	"github.com/kserve/kserve/pkg/constants"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestStepAuth(t *testing.T) {
+	var received http.Header
+	model := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = io.ReadAll(req.Body)
+		received = req.Header.Clone()
+		_, _ = rw.Write([]byte(`{"predictions": [1]}`))
+	}))
+	defer model.Close()
+
+	defer func(tokensDir string, secretsDir string) {
+		authTokensDir, authSecretsDir = tokensDir, secretsDir
+	}(authTokensDir, authSecretsDir)
+	authTokensDir, authSecretsDir = t.TempDir(), t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(authTokensDir, constants.InferenceGraphAuthTokenFileName("models")), []byte("sa-token\n"), 0o600))
+	assert.Nil(t, os.Mkdir(filepath.Join(authSecretsDir, "model-credentials"), 0o700))
+	assert.Nil(t, os.WriteFile(filepath.Join(authSecretsDir, "model-credentials", "apiKey"), []byte("secret-key"), 0o600))
+
+	headers := http.Header{"Authorization": {"Bearer caller-token"}}
+	scenarios := map[string]struct {
+		auth           *v1alpha1.StepAuth
+		expectedHeader string
+		expectedValue  string
+	}{
+		"no auth": {
+			expectedHeader: "Authorization",
+		},
+		"forward token": {
+			auth:           &v1alpha1.StepAuth{Type: v1alpha1.ForwardToken},
+			expectedHeader: "Authorization",
+			expectedValue:  "Bearer caller-token",
+		},
+		"service account token": {
+			auth:           &v1alpha1.StepAuth{Type: v1alpha1.ServiceAccountToken, Audience: "models"},
+			expectedHeader: "Authorization",
+			expectedValue:  "Bearer sa-token",
+		},
+		"secret header": {
+			auth: &v1alpha1.StepAuth{
+				Type:   v1alpha1.SecretHeader,
+				Header: "X-Api-Key",
+				SecretKeyRef: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: "model-credentials"},
+					Key:                  "apiKey",
+				},
+			},
+			expectedHeader: "X-Api-Key",
+			expectedValue:  "secret-key",
+		},
+	}
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			step := &v1alpha1.InferenceStep{
+				InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: model.URL},
+				Auth:            scenario.auth,
+			}
+			_, statusCode, err := callStepService(context.Background(), step, []byte("{}"), headers)
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusOK, statusCode)
+			assert.Equal(t, scenario.expectedValue, received.Get(scenario.expectedHeader))
+		})
+	}
+
+	// missing credentials fail the step
+	step := &v1alpha1.InferenceStep{
+		InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: model.URL},
+		Auth:            &v1alpha1.StepAuth{Type: v1alpha1.ServiceAccountToken, Audience: "other"},
+	}
+	_, statusCode, err := callStepService(context.Background(), step, []byte("{}"), headers)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusInternalServerError, statusCode)
+}
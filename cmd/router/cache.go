@@ -0,0 +1,166 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+)
+
+const (
+	defaultCacheTTL        = 60 * time.Second
+	defaultCacheMaxEntries = 1000
+)
+
+type cacheEntry struct {
+	key        string
+	response   []byte
+	statusCode int
+	expiresAt  time.Time
+}
+
+// responseCache is a size bounded LRU cache of step responses whose entries expire after the ttl
+type responseCache struct {
+	mu         sync.Mutex
+	spec       v1alpha1.StepCache
+	target     v1alpha1.InferenceTarget
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]*list.Element
+	lru        *list.List
+}
+
+func newResponseCache(spec *v1alpha1.StepCache) *responseCache {
+	c := &responseCache{
+		spec:       *spec,
+		ttl:        defaultCacheTTL,
+		maxEntries: defaultCacheMaxEntries,
+		entries:    map[string]*list.Element{},
+		lru:        list.New(),
+	}
+	if spec.TTLSeconds != nil {
+		c.ttl = time.Duration(*spec.TTLSeconds) * time.Second
+	}
+	if spec.MaxEntries != nil {
+		c.maxEntries = int(*spec.MaxEntries)
+	}
+	return c
+}
+
+// key hashes the request body with the values of the cache headers
+func (c *responseCache) key(input []byte, headers http.Header) string {
+	hash := sha256.New()
+	hash.Write(input)
+	for _, h := range c.spec.Headers {
+		hash.Write([]byte{0})
+		hash.Write([]byte(http.CanonicalHeaderKey(h)))
+		for _, v := range headers.Values(h) {
+			hash.Write([]byte{0})
+			hash.Write([]byte(v))
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func (c *responseCache) get(key string) (*cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*cacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.lru.Remove(element)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.lru.MoveToFront(element)
+	return entry, true
+}
+
+func (c *responseCache) add(key string, response []byte, statusCode int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := &cacheEntry{
+		key:        key,
+		response:   response,
+		statusCode: statusCode,
+		expiresAt:  time.Now().Add(c.ttl),
+	}
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.lru.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.maxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+var (
+	responseCachesMu sync.Mutex
+	responseCaches   = map[string]*responseCache{}
+)
+
+// getResponseCache returns the cache of the step, a reloaded graph which changes the cache or the target of the
+// step starts with an empty cache so the responses of the previous target are never served
+func getResponseCache(ctx context.Context, step *v1alpha1.InferenceStep) *responseCache {
+	if step.Cache == nil {
+		return nil
+	}
+	id := nodeFromContext(ctx) + "/" + stepMetricsLabel(step)
+	responseCachesMu.Lock()
+	defer responseCachesMu.Unlock()
+	c, ok := responseCaches[id]
+	if !ok || !reflect.DeepEqual(c.spec, *step.Cache) || c.target != step.InferenceTarget {
+		c = newResponseCache(step.Cache)
+		c.target = step.InferenceTarget
+		responseCaches[id] = c
+	}
+	return c
+}
+
+// executeCachedStep serves the step response from the cache and caches the successful responses of the step
+func executeCachedStep(ctx context.Context, c *responseCache, step *v1alpha1.InferenceStep, input []byte, headers http.Header,
+	execute func(ctx context.Context) ([]byte, int, error)) ([]byte, int, error) {
+	nodeName, stepName := nodeFromContext(ctx), stepMetricsLabel(step)
+	key := c.key(input, headers)
+	if entry, ok := c.get(key); ok {
+		stepCacheHitsTotal.WithLabelValues(graphName, nodeName, stepName).Inc()
+		log.Info("Serving the step response from the cache", "stepName", step.StepName)
+		return entry.response, entry.statusCode, nil
+	}
+	stepCacheMissesTotal.WithLabelValues(graphName, nodeName, stepName).Inc()
+	// the response is cached so it is never streamed to the client
+	responseBytes, statusCode, err := execute(withoutStream(ctx))
+	if err == nil && isSuccessFul(statusCode) {
+		c.add(key, responseBytes, statusCode)
+	}
+	return responseBytes, statusCode, err
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseCache(t *testing.T) {
+	c := newResponseCache(&v1alpha1.StepCache{MaxEntries: proto.Int32(2), Headers: []string{"x-tenant"}})
+
+	headers := http.Header{}
+	headers.Set("X-Tenant", "gold")
+	assert.Equal(t, c.key([]byte(`{"id": 1}`), headers), c.key([]byte(`{"id": 1}`), headers.Clone()))
+	assert.NotEqual(t, c.key([]byte(`{"id": 1}`), headers), c.key([]byte(`{"id": 2}`), headers))
+	assert.NotEqual(t, c.key([]byte(`{"id": 1}`), headers), c.key([]byte(`{"id": 1}`), http.Header{}))
+	headers.Set("X-Request-Id", "1")
+	assert.Equal(t, c.key([]byte(`{"id": 1}`), headers), c.key([]byte(`{"id": 1}`), http.Header{"X-Tenant": {"gold"}}))
+
+	c.add("a", []byte("a"), 200)
+	c.add("b", []byte("b"), 200)
+	_, ok := c.get("a")
+	assert.True(t, ok)
+	// b is the least recently used entry
+	c.add("c", []byte("c"), 200)
+	_, ok = c.get("b")
+	assert.False(t, ok)
+	entry, ok := c.get("a")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("a"), entry.response)
+
+	c.ttl = time.Millisecond
+	c.add("d", []byte("d"), 200)
+	time.Sleep(5 * time.Millisecond)
+	_, ok = c.get("d")
+	assert.False(t, ok)
+	assert.Equal(t, 1, c.lru.Len())
+}
+
+func TestCachedStep(t *testing.T) {
+	var calls int32
+	features := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		atomic.AddInt32(&calls, 1)
+		if strings.Contains(string(body), "fail") {
+			rw.WriteHeader(http.StatusInternalServerError)
+		}
+		_, _ = rw.Write([]byte(`{"features": [1, 2]}`))
+	}))
+	defer features.Close()
+
+	storeGraph(t, &v1alpha1.InferenceGraphSpec{
+		Nodes: map[string]v1alpha1.InferenceRouter{
+			"root": {
+				RouterType: v1alpha1.Sequence,
+				Steps: []v1alpha1.InferenceStep{
+					{
+						StepName:        "cached-features",
+						InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: features.URL},
+						Cache:           &v1alpha1.StepCache{Headers: []string{"X-Tenant"}},
+					},
+				},
+			},
+		},
+	})
+	send := func(body string, tenant string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+		req.Header.Set("X-Tenant", tenant)
+		rec := httptest.NewRecorder()
+		graphHandler(rec, req)
+		return rec
+	}
+
+	for i := 0; i < 3; i++ {
+		rec := send(`{"instances": [1]}`, "gold")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, `{"features": [1, 2]}`, rec.Body.String())
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	send(`{"instances": [1]}`, "silver")
+	send(`{"instances": [2]}`, "gold")
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	// failed responses are not cached
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, http.StatusInternalServerError, send(`{"instances": ["fail"]}`, "gold").Code)
+	}
+	assert.Equal(t, int32(5), atomic.LoadInt32(&calls))
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(stepCacheHitsTotal.WithLabelValues(graphName, "root", "cached-features")))
+	assert.Equal(t, 5.0, testutil.ToFloat64(stepCacheMissesTotal.WithLabelValues(graphName, "root", "cached-features")))
+
+	// a reloaded graph which moves the step to another service does not serve the responses of the previous one
+	var movedCalls int32
+	moved := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&movedCalls, 1)
+		_, _ = rw.Write([]byte(`{"features": [3, 4]}`))
+	}))
+	defer moved.Close()
+	graph := *inferenceGraph.Load().spec
+	root := graph.Nodes["root"]
+	root.Steps = []v1alpha1.InferenceStep{*root.Steps[0].DeepCopy()}
+	root.Steps[0].ServiceURL = moved.URL
+	graph.Nodes = map[string]v1alpha1.InferenceRouter{"root": root}
+	storeGraph(t, &graph)
+	for i := 0; i < 2; i++ {
+		rec := send(`{"instances": [1]}`, "gold")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, `{"features": [3, 4]}`, rec.Body.String())
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&movedCalls))
+	assert.Equal(t, int32(5), atomic.LoadInt32(&calls))
+}
@@ -0,0 +1,332 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	"github.com/tidwall/gjson"
+)
+
+type EnsembleStepOutput struct {
+	StepResponse   []byte
+	StepStatusCode int
+	StepError      error
+	stepIndex      int
+	attempts       int
+}
+
+// EnsembleStepError reports a failed step in the `errors` section of the Ensemble node response
+type EnsembleStepError struct {
+	StatusCode int    `json:"statusCode"`
+	Error      string `json:"error"`
+}
+
+func (output *EnsembleStepOutput) succeeded() bool {
+	return output.StepError == nil && isSuccessFul(output.StepStatusCode)
+}
+
+// failureStatusCode is the status code of the node when the step fails it, errors without a failed status are 500
+func (output *EnsembleStepOutput) failureStatusCode() int {
+	if output.StepStatusCode == 0 || isSuccessFul(output.StepStatusCode) {
+		return http.StatusInternalServerError
+	}
+	return output.StepStatusCode
+}
+
+// hardStepError reports the failure of a Hard step which fails its node, the responses the node could not use are
+// invalid upstream responses
+func (output *EnsembleStepOutput) hardStepError(ctx context.Context, step *v1alpha1.InferenceStep) *StepError {
+	if output.StepError == nil {
+		return newUpstreamStepError(nodeFromContext(ctx), stepMetricsLabel(step), output.StepStatusCode, output.StepResponse, output.attempts)
+	}
+	statusCode := output.failureStatusCode()
+	if isSuccessFul(output.StepStatusCode) {
+		statusCode = http.StatusBadGateway
+	}
+	return newStepError(ctx, stepMetricsLabel(step), statusCode, output.attempts, output.StepError)
+}
+
+func (output *EnsembleStepOutput) stepError() EnsembleStepError {
+	message := string(output.StepResponse)
+	if output.StepError != nil {
+		message = output.StepError.Error()
+	}
+	return EnsembleStepError{
+		StatusCode: output.failureStatusCode(),
+		Error:      message,
+	}
+}
+
+func ensembleStepKey(node v1alpha1.InferenceRouter, i int) string {
+	if key := node.Steps[i].StepName; key != "" {
+		return key
+	}
+	return strconv.Itoa(i) // Use index if no step name
+}
+
+// ensembleQuorum returns the number of steps which must succeed for the node to succeed, by default at least one step
+// must succeed while BestEffort nodes succeed even when all their steps fail
+func ensembleQuorum(node v1alpha1.InferenceRouter) int {
+	if node.FailurePolicy == nil {
+		return 1
+	}
+	switch node.FailurePolicy.Type {
+	case v1alpha1.All:
+		return len(node.Steps)
+	case v1alpha1.Quorum:
+		if node.FailurePolicy.Quorum != nil {
+			return int(*node.FailurePolicy.Quorum)
+		}
+	case v1alpha1.BestEffort:
+		return 0
+	}
+	return 1
+}
+
+// fanOut makes n calls with at most parallelism calls in flight, the calls are started in order and their outputs are
+// sent in completion order. The channel is buffered so the calls never block on sending after the node has returned,
+// the calls which are not started when the context is cancelled fail with the context error.
+func fanOut(ctx context.Context, n int, parallelism int, call func(ctx context.Context, i int) ([]byte, int, error)) <-chan EnsembleStepOutput {
+	results := make(chan EnsembleStepOutput, n)
+	inflight := ensembleInflightSteps.WithLabelValues(graphName, nodeFromContext(ctx))
+	slots := make(chan struct{}, parallelism)
+	go func() {
+		for i := 0; i < n; i++ {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				for ; i < n; i++ {
+					results <- EnsembleStepOutput{StepStatusCode: 500, StepError: ctx.Err(), stepIndex: i}
+				}
+				return
+			}
+			inflight.Inc()
+			go func(i int) {
+				callCtx, attempts := withAttempts(ctx)
+				output, statusCode, err := call(callCtx, i)
+				inflight.Dec()
+				<-slots
+				results <- EnsembleStepOutput{
+					StepResponse:   output,
+					StepStatusCode: statusCode,
+					StepError:      err,
+					stepIndex:      i,
+					attempts:       *attempts,
+				}
+			}(i)
+		}
+	}()
+	return results
+}
+
+// handleEnsembleNode runs all the steps of the node in parallel and merges or combines their responses.
+// Failed steps are reported in the `errors` section of the response, the node fails when a Hard step fails
+// or when fewer steps than required by the failure policy can succeed.
+func handleEnsembleNode(ctx context.Context, node v1alpha1.InferenceRouter, graph v1alpha1.InferenceGraphSpec, input []byte, headers http.Header) ([]byte, int, error) {
+	// cancel the sibling steps when the node returns early
+	// the step responses are merged by the node and never streamed to the client
+	stepsCtx, cancel := context.WithCancel(withoutStream(ctx))
+	defer cancel()
+	results := fanOut(stepsCtx, len(node.Steps), len(node.Steps), func(ctx context.Context, i int) ([]byte, int, error) {
+		step := &node.Steps[i]
+		stepType := "serviceUrl"
+		if step.NodeName != "" {
+			stepType = "node"
+		}
+		log.Info("Starting execution of step", "type", stepType, "stepName", step.StepName)
+		return executeStep(ctx, step, graph, input, headers)
+	})
+
+	firstSuccess := node.Combiner != nil && node.Combiner.Type == v1alpha1.FirstSuccess
+	quorum := ensembleQuorum(node)
+	outputs := make([]EnsembleStepOutput, len(node.Steps))
+	responses := map[string]interface{}{}
+	stepErrors := map[string]EnsembleStepError{}
+	succeeded, failed := 0, 0
+	for range node.Steps {
+		output := <-results
+		if err := ctx.Err(); err != nil {
+			return nil, 500, err
+		}
+		step := &node.Steps[output.stepIndex]
+		key := ensembleStepKey(node, output.stepIndex)
+		if output.succeeded() && node.Combiner == nil {
+			var res map[string]interface{}
+			if err := json.Unmarshal(output.StepResponse, &res); err != nil {
+				output.StepError = fmt.Errorf("step response is not a json object: %w", err)
+			} else {
+				responses[key] = res
+			}
+		}
+		if output.succeeded() && node.Combiner != nil && !firstSuccess {
+			// a response without the predictions to combine fails the step under the failure policy of the node
+			if !gjson.GetBytes(output.StepResponse, node.Combiner.Path).Exists() {
+				output.StepError = fmt.Errorf("path %s not found in the step response", node.Combiner.Path)
+			}
+		}
+		outputs[output.stepIndex] = output
+		if output.succeeded() {
+			succeeded++
+			if firstSuccess {
+				log.Info("Returning the first successful step response", "stepName", step.StepName)
+				return output.StepResponse, output.StepStatusCode, nil
+			}
+			continue
+		}
+
+		failed++
+		stepErrors[key] = output.stepError()
+		log.Info("Ensemble step failed", "stepName", step.StepName, "statusCode", output.StepStatusCode, "error", output.StepError)
+		if step.Dependency == v1alpha1.Hard {
+			log.Info("This step is a hard dependency and it is unsuccessful", "stepName", step.StepName, "statusCode", output.StepStatusCode)
+			// First failed hard dependency will decide the response code for ensemble node
+			stepErr := output.hardStepError(ctx, step)
+			return nil, stepErr.StatusCode, stepErr
+		}
+		if len(node.Steps)-failed < quorum {
+			log.Info("Ensemble node can not reach the quorum of successful steps", "quorum", quorum, "failed", failed)
+			return ensembleResponse(responses, stepErrors, output.failureStatusCode())
+		}
+	}
+	if node.Combiner != nil {
+		if succeeded == 0 {
+			// only BestEffort nodes get here, like without a combiner they succeed and report the failed steps
+			log.Info("Ensemble node has no successful step response to combine")
+			return ensembleResponse(map[string]interface{}{"predictions": nil}, stepErrors, 200)
+		}
+		return combineEnsembleResponses(node, outputs, stepErrors)
+	}
+	return ensembleResponse(responses, stepErrors, 200)
+}
+
+// ensembleResponse merges the responses of the successful steps keyed by step name with the errors of the failed steps
+func ensembleResponse(response map[string]interface{}, stepErrors map[string]EnsembleStepError, statusCode int) ([]byte, int, error) {
+	if len(stepErrors) > 0 {
+		response["errors"] = stepErrors
+	}
+	responseBytes, err := json.Marshal(response)
+	if err != nil {
+		return nil, 500, err
+	}
+	return responseBytes, statusCode, nil
+}
+
+// combineEnsembleResponses combines the predictions selected by the combiner path in the successful step responses,
+// the steps whose response does not have the path have already been failed by the node
+func combineEnsembleResponses(node v1alpha1.InferenceRouter, outputs []EnsembleStepOutput, stepErrors map[string]EnsembleStepError) ([]byte, int, error) {
+	combiner := node.Combiner
+	var predictions []gjson.Result
+	var weights []float64
+	for i, output := range outputs {
+		if !output.succeeded() {
+			continue
+		}
+		step := &node.Steps[i]
+		predictions = append(predictions, gjson.GetBytes(output.StepResponse, combiner.Path))
+		weight := 1.0
+		if step.Weight != nil {
+			weight = float64(*step.Weight)
+		}
+		weights = append(weights, weight)
+	}
+	combined, err := combinePredictions(combiner.Type, predictions, weights)
+	if err != nil {
+		return nil, 500, err
+	}
+	return ensembleResponse(map[string]interface{}{"predictions": combined}, stepErrors, 200)
+}
+
+// combinePredictions combines the predictions of the steps, arrays are combined element wise
+func combinePredictions(combinerType v1alpha1.EnsembleCombinerType, predictions []gjson.Result, weights []float64) (interface{}, error) {
+	if len(predictions) == 0 {
+		return nil, fmt.Errorf("no predictions to combine")
+	}
+	if predictions[0].IsArray() {
+		columns := make([][]gjson.Result, len(predictions))
+		for i, prediction := range predictions {
+			columns[i] = prediction.Array()
+			if !prediction.IsArray() || len(columns[i]) != len(columns[0]) {
+				return nil, fmt.Errorf("predictions to combine must have the same shape")
+			}
+		}
+		combined := make([]interface{}, len(columns[0]))
+		for j := range combined {
+			elements := make([]gjson.Result, len(columns))
+			for i := range columns {
+				elements[i] = columns[i][j]
+			}
+			var err error
+			if combined[j], err = combinePredictions(combinerType, elements, weights); err != nil {
+				return nil, err
+			}
+		}
+		return combined, nil
+	}
+
+	if combinerType == v1alpha1.MajorityVote {
+		votes := map[string]int{}
+		for _, prediction := range predictions {
+			votes[prediction.Raw]++
+		}
+		winner := predictions[0]
+		for _, prediction := range predictions {
+			if votes[prediction.Raw] > votes[winner.Raw] {
+				winner = prediction
+			}
+		}
+		return winner.Value(), nil
+	}
+
+	numbers := make([]float64, len(predictions))
+	for i, prediction := range predictions {
+		if prediction.Type != gjson.Number {
+			return nil, fmt.Errorf("%s combiner requires numeric predictions but got %s", combinerType, prediction.Raw)
+		}
+		numbers[i] = prediction.Float()
+	}
+	switch combinerType {
+	case v1alpha1.Mean, v1alpha1.WeightedMean:
+		sum, totalWeight := 0.0, 0.0
+		for i, number := range numbers {
+			weight := 1.0
+			if combinerType == v1alpha1.WeightedMean {
+				weight = weights[i]
+			}
+			sum += number * weight
+			totalWeight += weight
+		}
+		if totalWeight == 0 {
+			return nil, fmt.Errorf("the sum of the step weights must be greater than 0")
+		}
+		return sum / totalWeight, nil
+	case v1alpha1.Max, v1alpha1.Min:
+		result := numbers[0]
+		for _, number := range numbers[1:] {
+			if (combinerType == v1alpha1.Max && number > result) || (combinerType == v1alpha1.Min && number < result) {
+				result = number
+			}
+		}
+		return result, nil
+	}
+	return nil, fmt.Errorf("unsupported combiner type %s", combinerType)
+}
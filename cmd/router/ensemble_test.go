@@ -0,0 +1,369 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func newPredictionServer(t *testing.T, statusCode int, predictions string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = io.ReadAll(req.Body)
+		rw.WriteHeader(statusCode)
+		_, _ = rw.Write([]byte(`{"predictions": ` + predictions + `}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCombinePredictions(t *testing.T) {
+	scenarios := map[string]struct {
+		combinerType v1alpha1.EnsembleCombinerType
+		predictions  []string
+		weights      []float64
+		expected     string
+		hasError     bool
+	}{
+		"majority vote": {
+			combinerType: v1alpha1.MajorityVote,
+			predictions:  []string{`"cat"`, `"dog"`, `"dog"`},
+			expected:     `"dog"`,
+		},
+		"majority vote tie is broken by step order": {
+			combinerType: v1alpha1.MajorityVote,
+			predictions:  []string{`1`, `0`},
+			expected:     `1`,
+		},
+		"majority vote element wise": {
+			combinerType: v1alpha1.MajorityVote,
+			predictions:  []string{`[1, 0]`, `[1, 1]`, `[0, 1]`},
+			expected:     `[1, 1]`,
+		},
+		"mean": {
+			combinerType: v1alpha1.Mean,
+			predictions:  []string{`1`, `2`, `6`},
+			expected:     `3`,
+		},
+		"weighted mean": {
+			combinerType: v1alpha1.WeightedMean,
+			predictions:  []string{`[[0.25, 0.75]]`, `[[0.75, 0.25]]`},
+			weights:      []float64{3, 1},
+			expected:     `[[0.375, 0.625]]`,
+		},
+		"max": {
+			combinerType: v1alpha1.Max,
+			predictions:  []string{`[1, 5]`, `[3, 2]`},
+			expected:     `[3, 5]`,
+		},
+		"min": {
+			combinerType: v1alpha1.Min,
+			predictions:  []string{`[1, 5]`, `[3, 2]`},
+			expected:     `[1, 2]`,
+		},
+		"mean of strings": {
+			combinerType: v1alpha1.Mean,
+			predictions:  []string{`"cat"`, `"dog"`},
+			hasError:     true,
+		},
+		"different shapes": {
+			combinerType: v1alpha1.Max,
+			predictions:  []string{`[1, 5]`, `[3]`},
+			hasError:     true,
+		},
+		"no predictions": {
+			combinerType: v1alpha1.Mean,
+			predictions:  []string{},
+			hasError:     true,
+		},
+	}
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			predictions := make([]gjson.Result, len(scenario.predictions))
+			for i, prediction := range scenario.predictions {
+				predictions[i] = gjson.Parse(prediction)
+			}
+			combined, err := combinePredictions(scenario.combinerType, predictions, scenario.weights)
+			if scenario.hasError {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			combinedBytes, _ := json.Marshal(combined)
+			assert.JSONEq(t, scenario.expected, string(combinedBytes))
+		})
+	}
+}
+
+func TestEnsembleWithCombiner(t *testing.T) {
+	model1 := newPredictionServer(t, http.StatusOK, `[0.2]`)
+	model2 := newPredictionServer(t, http.StatusOK, `[0.6]`)
+	failing := newPredictionServer(t, http.StatusInternalServerError, `[100]`)
+
+	graphSpec := v1alpha1.InferenceGraphSpec{
+		Nodes: map[string]v1alpha1.InferenceRouter{
+			"root": {
+				RouterType: v1alpha1.Ensemble,
+				Combiner: &v1alpha1.EnsembleCombiner{
+					Type: v1alpha1.WeightedMean,
+					Path: "predictions",
+				},
+				Steps: []v1alpha1.InferenceStep{
+					{
+						StepName:        "model1",
+						InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: model1.URL},
+						Weight:          proto.Int64(1),
+					},
+					{
+						StepName:        "model2",
+						InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: model2.URL},
+						Weight:          proto.Int64(3),
+					},
+					{
+						// unsuccessful soft steps are not combined
+						StepName:        "failing",
+						InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: failing.URL},
+						Weight:          proto.Int64(1),
+					},
+				},
+			},
+		},
+	}
+	res, statusCode, err := routeStep(context.Background(), "root", graphSpec, []byte(`{"instances": [1]}`), http.Header{})
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.InDelta(t, 0.5, gjson.GetBytes(res, "predictions.0").Float(), 1e-9)
+
+	graphSpec.Nodes["root"].Combiner.Path = "outputs"
+	res, statusCode, err = routeStep(context.Background(), "root", graphSpec, []byte(`{"instances": [1]}`), http.Header{})
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusInternalServerError, statusCode)
+	assert.Contains(t, gjson.GetBytes(res, "errors.model1.error").String(), "path outputs not found")
+}
+
+func TestEnsembleCombinerPathMissing(t *testing.T) {
+	model1 := newPredictionServer(t, http.StatusOK, `[0.2]`)
+	model2 := newPredictionServer(t, http.StatusOK, `[0.6]`)
+	other := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = io.ReadAll(req.Body)
+		_, _ = rw.Write([]byte(`{"outputs": [1.0]}`))
+	}))
+	defer other.Close()
+
+	graphSpec := v1alpha1.InferenceGraphSpec{
+		Nodes: map[string]v1alpha1.InferenceRouter{
+			"root": {
+				RouterType:    v1alpha1.Ensemble,
+				Combiner:      &v1alpha1.EnsembleCombiner{Type: v1alpha1.Mean, Path: "predictions"},
+				FailurePolicy: &v1alpha1.EnsembleFailurePolicy{Type: v1alpha1.BestEffort},
+				Steps: []v1alpha1.InferenceStep{
+					{StepName: "model1", InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: model1.URL}},
+					{StepName: "model2", InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: model2.URL}},
+					{StepName: "other", InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: other.URL}},
+				},
+			},
+		},
+	}
+	res, statusCode, err := routeStep(context.Background(), "root", graphSpec, []byte(`{"instances": [1]}`), http.Header{})
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.InDelta(t, 0.4, gjson.GetBytes(res, "predictions.0").Float(), 1e-9)
+	assert.Contains(t, gjson.GetBytes(res, "errors.other.error").String(), "path predictions not found")
+}
+
+func TestEnsembleFirstSuccess(t *testing.T) {
+	slowCancelled := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = io.ReadAll(req.Body)
+		select {
+		case <-req.Context().Done():
+			close(slowCancelled)
+		case <-time.After(10 * time.Second):
+		}
+	}))
+	defer slow.Close()
+	failing := newPredictionServer(t, http.StatusServiceUnavailable, `[]`)
+	fast := newPredictionServer(t, http.StatusOK, `["fast"]`)
+
+	graphSpec := v1alpha1.InferenceGraphSpec{
+		Nodes: map[string]v1alpha1.InferenceRouter{
+			"root": {
+				RouterType: v1alpha1.Ensemble,
+				Combiner:   &v1alpha1.EnsembleCombiner{Type: v1alpha1.FirstSuccess},
+				Steps: []v1alpha1.InferenceStep{
+					{StepName: "slow", InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: slow.URL}},
+					{StepName: "failing", InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: failing.URL}},
+					{StepName: "fast", InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: fast.URL}},
+				},
+			},
+		},
+	}
+	res, statusCode, err := routeStep(context.Background(), "root", graphSpec, []byte(`{"instances": [1]}`), http.Header{})
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.JSONEq(t, `{"predictions": ["fast"]}`, string(res))
+
+	select {
+	case <-slowCancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("slow ensemble step was not cancelled")
+	}
+}
+
+func TestEnsembleFailurePolicies(t *testing.T) {
+	model1 := newPredictionServer(t, http.StatusOK, `["a"]`)
+	model2 := newPredictionServer(t, http.StatusOK, `["b"]`)
+	failing := newPredictionServer(t, http.StatusServiceUnavailable, `[]`)
+	steps := []v1alpha1.InferenceStep{
+		{StepName: "model1", InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: model1.URL}},
+		{StepName: "model2", InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: model2.URL}},
+		{StepName: "failing", InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: failing.URL}},
+	}
+	scenarios := map[string]struct {
+		policy             *v1alpha1.EnsembleFailurePolicy
+		expectedStatusCode int
+	}{
+		"default with a successful step": {
+			expectedStatusCode: http.StatusOK,
+		},
+		"best effort": {
+			policy:             &v1alpha1.EnsembleFailurePolicy{Type: v1alpha1.BestEffort},
+			expectedStatusCode: http.StatusOK,
+		},
+		"all": {
+			policy:             &v1alpha1.EnsembleFailurePolicy{Type: v1alpha1.All},
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+		"quorum reached": {
+			policy:             &v1alpha1.EnsembleFailurePolicy{Type: v1alpha1.Quorum, Quorum: proto.Int32(2)},
+			expectedStatusCode: http.StatusOK,
+		},
+		"quorum not reached": {
+			policy:             &v1alpha1.EnsembleFailurePolicy{Type: v1alpha1.Quorum, Quorum: proto.Int32(3)},
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+	}
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			graphSpec := v1alpha1.InferenceGraphSpec{
+				Nodes: map[string]v1alpha1.InferenceRouter{
+					"root": {
+						RouterType:    v1alpha1.Ensemble,
+						FailurePolicy: scenario.policy,
+						Steps:         steps,
+					},
+				},
+			}
+			res, statusCode, err := routeStep(context.Background(), "root", graphSpec, []byte(`{"instances": [1]}`), http.Header{})
+			assert.Nil(t, err)
+			assert.Equal(t, scenario.expectedStatusCode, statusCode)
+			assert.Equal(t, int64(http.StatusServiceUnavailable), gjson.GetBytes(res, "errors.failing.statusCode").Int())
+			if statusCode == http.StatusOK {
+				assert.Equal(t, "a", gjson.GetBytes(res, "model1.predictions.0").String())
+				assert.Equal(t, "b", gjson.GetBytes(res, "model2.predictions.0").String())
+			}
+		})
+	}
+}
+
+func TestEnsembleWithoutSuccessfulSteps(t *testing.T) {
+	failing := newPredictionServer(t, http.StatusBadGateway, `[]`)
+	scenarios := map[string]struct {
+		failurePolicy *v1alpha1.EnsembleFailurePolicy
+		combiner      *v1alpha1.EnsembleCombiner
+		statusCode    int
+	}{
+		"default fails": {
+			statusCode: http.StatusBadGateway,
+		},
+		"best effort succeeds": {
+			failurePolicy: &v1alpha1.EnsembleFailurePolicy{Type: v1alpha1.BestEffort},
+			statusCode:    http.StatusOK,
+		},
+		"default with combiner fails": {
+			combiner:   &v1alpha1.EnsembleCombiner{Type: v1alpha1.Mean, Path: "predictions"},
+			statusCode: http.StatusBadGateway,
+		},
+		"best effort with combiner succeeds": {
+			failurePolicy: &v1alpha1.EnsembleFailurePolicy{Type: v1alpha1.BestEffort},
+			combiner:      &v1alpha1.EnsembleCombiner{Type: v1alpha1.Mean, Path: "predictions"},
+			statusCode:    http.StatusOK,
+		},
+	}
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			graphSpec := v1alpha1.InferenceGraphSpec{
+				Nodes: map[string]v1alpha1.InferenceRouter{
+					"root": {
+						RouterType:    v1alpha1.Ensemble,
+						Combiner:      scenario.combiner,
+						FailurePolicy: scenario.failurePolicy,
+						Steps: []v1alpha1.InferenceStep{
+							{StepName: "failing1", InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: failing.URL}},
+							{StepName: "failing2", InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: failing.URL}},
+						},
+					},
+				},
+			}
+			res, statusCode, err := routeStep(context.Background(), "root", graphSpec, []byte(`{"instances": [1]}`), http.Header{})
+			assert.Nil(t, err)
+			assert.Equal(t, scenario.statusCode, statusCode)
+			assert.True(t, gjson.GetBytes(res, "errors.failing1").Exists())
+			assert.True(t, gjson.GetBytes(res, "errors.failing2").Exists())
+			if scenario.combiner != nil && statusCode == http.StatusOK {
+				assert.True(t, gjson.GetBytes(res, "predictions").Exists())
+				assert.Equal(t, gjson.Null, gjson.GetBytes(res, "predictions").Type)
+			}
+		})
+	}
+}
+
+func TestEnsembleHardStepFails(t *testing.T) {
+	model := newPredictionServer(t, http.StatusOK, `["a"]`)
+	failing := newPredictionServer(t, http.StatusServiceUnavailable, `[]`)
+	graphSpec := v1alpha1.InferenceGraphSpec{
+		Nodes: map[string]v1alpha1.InferenceRouter{
+			"root": {
+				RouterType:    v1alpha1.Ensemble,
+				FailurePolicy: &v1alpha1.EnsembleFailurePolicy{Type: v1alpha1.BestEffort},
+				Steps: []v1alpha1.InferenceStep{
+					{StepName: "model", InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: model.URL}},
+					{StepName: "failing", InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: failing.URL}, Dependency: v1alpha1.Hard},
+				},
+			},
+		},
+	}
+	_, statusCode, err := routeStep(context.Background(), "root", graphSpec, []byte(`{"instances": [1]}`), http.Header{})
+	assert.Equal(t, http.StatusBadGateway, statusCode)
+	var stepErr *StepError
+	if assert.ErrorAs(t, err, &stepErr) {
+		assert.Equal(t, "root", stepErr.NodeName)
+		assert.Equal(t, "failing", stepErr.StepName)
+		assert.Equal(t, http.StatusServiceUnavailable, stepErr.UpstreamStatusCode)
+		assert.Equal(t, 1, stepErr.Attempts)
+	}
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	"github.com/kserve/kserve/pkg/inferencegraph/mapping"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+// defaultForEachParallelism is the number of elements a ForEach node processes in parallel when it is not set
+const defaultForEachParallelism = 10
+
+// buildElementRequest builds the request of the step for an array element, `$response` refers to the element
+func buildElementRequest(ctx context.Context, step *v1alpha1.InferenceStep, input []byte, element []byte) ([]byte, error) {
+	if step.Data == "" {
+		return element, nil
+	}
+	m, err := getMapping(ctx, step.Data)
+	if err != nil {
+		return nil, err
+	}
+	return m.Apply(&mapping.Scope{
+		Request:  input,
+		Response: element,
+	})
+}
+
+// elementResult selects the result of an element from the step response
+func elementResult(options *v1alpha1.ForEachOptions, response []byte) (json.RawMessage, error) {
+	if !gjson.ValidBytes(response) {
+		return nil, fmt.Errorf("step response is not valid json")
+	}
+	if options.ResponsePath == "" {
+		return response, nil
+	}
+	result := gjson.GetBytes(response, options.ResponsePath)
+	if !result.Exists() {
+		return nil, fmt.Errorf("path %s not found in the step response", options.ResponsePath)
+	}
+	return json.RawMessage(result.Raw), nil
+}
+
+// handleForEachNode calls the step of the node once per element of the array selected in the request, with at most
+// the parallelism of the node in flight, and returns the results in the order of the elements. Failed elements are
+// null and reported by index in the `errors` section of the response, the node fails when the step is Hard.
+func handleForEachNode(ctx context.Context, nodeName string, node v1alpha1.InferenceRouter, graph v1alpha1.InferenceGraphSpec, input []byte, headers http.Header) ([]byte, int, error) {
+	options := node.ForEach
+	if options == nil || len(node.Steps) != 1 {
+		return nil, 500, fmt.Errorf("forEach node %s must have forEach options and exactly one step", nodeName)
+	}
+	array := gjson.GetBytes(input, options.Path)
+	if !array.IsArray() {
+		err := &StepError{NodeName: nodeName, StatusCode: http.StatusBadRequest,
+			Err: fmt.Errorf("path %s of the request is not an array", options.Path)}
+		log.Error(err, "ForEach node can not split the request")
+		return nil, http.StatusBadRequest, err
+	}
+	elements := array.Array()
+	step := &node.Steps[0]
+	parallelism := defaultForEachParallelism
+	if options.Parallelism != nil {
+		parallelism = int(*options.Parallelism)
+	}
+
+	// cancel the remaining elements when the node returns early
+	// the step responses are gathered by the node and never streamed to the client
+	stepsCtx, cancel := context.WithCancel(withoutStream(ctx))
+	defer cancel()
+	results := fanOut(stepsCtx, len(elements), parallelism, func(ctx context.Context, i int) ([]byte, int, error) {
+		request, err := buildElementRequest(ctx, step, input, []byte(elements[i].Raw))
+		if err != nil {
+			return nil, 500, errors.Wrapf(err, "failed to build the request for element %d", i)
+		}
+		log.Info("Starting execution of step", "stepName", step.StepName, "element", i)
+		return executeStep(ctx, step, graph, request, headers)
+	})
+
+	predictions := make([]interface{}, len(elements))
+	elementErrors := map[string]EnsembleStepError{}
+	for range elements {
+		output := <-results
+		if err := ctx.Err(); err != nil {
+			return nil, 500, err
+		}
+		if output.succeeded() {
+			result, err := elementResult(options, output.StepResponse)
+			if err == nil {
+				predictions[output.stepIndex] = result
+				continue
+			}
+			output.StepError = err
+		}
+
+		elementErrors[strconv.Itoa(output.stepIndex)] = output.stepError()
+		log.Info("ForEach element failed", "stepName", step.StepName, "element", output.stepIndex,
+			"statusCode", output.StepStatusCode, "error", output.StepError)
+		if step.Dependency == v1alpha1.Hard {
+			log.Info("This step is a hard dependency and it is unsuccessful", "stepName", step.StepName, "statusCode", output.StepStatusCode)
+			stepErr := output.hardStepError(ctx, step)
+			return nil, stepErr.StatusCode, stepErr
+		}
+	}
+	return ensembleResponse(map[string]interface{}{"predictions": predictions}, elementErrors, 200)
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+// newDoublingServer doubles the first instance of the request, the smaller instances are answered last and the
+// instance 3 fails
+func newDoublingServer(t *testing.T, inflight *int32, maxInflight *int32) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		current := atomic.AddInt32(inflight, 1)
+		defer atomic.AddInt32(inflight, -1)
+		for {
+			max := atomic.LoadInt32(maxInflight)
+			if current <= max || atomic.CompareAndSwapInt32(maxInflight, max, current) {
+				break
+			}
+		}
+		instance := gjson.GetBytes(body, "instances.0").Int()
+		time.Sleep(time.Duration(10-instance) * 5 * time.Millisecond)
+		if instance == 3 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = rw.Write([]byte(fmt.Sprintf(`{"predictions": [%d]}`, instance*2)))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestForEachNode(t *testing.T) {
+	var inflight, maxInflight int32
+	model := newDoublingServer(t, &inflight, &maxInflight)
+	scenarios := map[string]struct {
+		input              string
+		dependency         v1alpha1.InferenceStepDependencyType
+		expectedStatusCode int
+		expected           string
+	}{
+		"results are returned in order": {
+			input:              `{"instances": [1, 2, 4, 5, 6]}`,
+			expectedStatusCode: http.StatusOK,
+			expected:           `{"predictions": [2, 4, 8, 10, 12]}`,
+		},
+		"empty array": {
+			input:              `{"instances": []}`,
+			expectedStatusCode: http.StatusOK,
+			expected:           `{"predictions": []}`,
+		},
+		"failed soft element": {
+			input:              `{"instances": [1, 2, 3, 4]}`,
+			expectedStatusCode: http.StatusOK,
+			expected:           `{"predictions": [2, 4, null, 8], "errors": {"2": {"statusCode": 503, "error": ""}}}`,
+		},
+		"failed hard element": {
+			input:              `{"instances": [3]}`,
+			dependency:         v1alpha1.Hard,
+			expectedStatusCode: http.StatusBadGateway,
+		},
+	}
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			graphSpec := v1alpha1.InferenceGraphSpec{
+				Nodes: map[string]v1alpha1.InferenceRouter{
+					"root": {
+						RouterType: v1alpha1.ForEach,
+						ForEach: &v1alpha1.ForEachOptions{
+							Path:         "instances",
+							Parallelism:  proto.Int32(2),
+							ResponsePath: "predictions.0",
+						},
+						Steps: []v1alpha1.InferenceStep{
+							{
+								StepName:        "model",
+								InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: model.URL},
+								Data:            `{"instances": ["$response"]}`,
+								Dependency:      scenario.dependency,
+							},
+						},
+					},
+				},
+			}
+			res, statusCode, err := routeStep(context.Background(), "root", graphSpec, []byte(scenario.input), http.Header{})
+			assert.Equal(t, scenario.expectedStatusCode, statusCode)
+			if scenario.dependency == v1alpha1.Hard {
+				var stepErr *StepError
+				if assert.ErrorAs(t, err, &stepErr) {
+					assert.Equal(t, "model", stepErr.StepName)
+					assert.Equal(t, http.StatusServiceUnavailable, stepErr.UpstreamStatusCode)
+				}
+				return
+			}
+			assert.Nil(t, err)
+			assert.JSONEq(t, scenario.expected, string(res))
+		})
+	}
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxInflight), int32(2))
+}
+
+func TestForEachNodeWithoutArray(t *testing.T) {
+	model := newPredictionServer(t, http.StatusOK, `[1]`)
+	graphSpec := v1alpha1.InferenceGraphSpec{
+		Nodes: map[string]v1alpha1.InferenceRouter{
+			"root": {
+				RouterType: v1alpha1.ForEach,
+				ForEach:    &v1alpha1.ForEachOptions{Path: "instances"},
+				Steps: []v1alpha1.InferenceStep{
+					{StepName: "model", InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: model.URL}},
+				},
+			},
+		},
+	}
+	_, statusCode, err := routeStep(context.Background(), "root", graphSpec, []byte(`{"instances": 1}`), http.Header{})
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+}
@@ -0,0 +1,278 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+)
+
+// graphTracePath serves the execution trace of a request, `?dryRun=true` resolves the routing without calling services
+const graphTracePath = "/_graph/trace"
+
+// GraphTrace is the execution trace of a request returned by the trace endpoint. Requests and responses are
+// truncated and timings are in milliseconds, the start of a node or step is relative to the start of the request.
+type GraphTrace struct {
+	DryRun     bool       `json:"dryRun"`
+	StatusCode int        `json:"statusCode"`
+	Response   string     `json:"response,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	DurationMs float64    `json:"durationMs"`
+	Root       *NodeTrace `json:"root,omitempty"`
+}
+
+// NodeTrace records a visited node, the route picked by a Splitter or Switch node and the step conditions evaluated
+// by a Sequence node
+type NodeTrace struct {
+	NodeName   string           `json:"nodeName"`
+	RouterType string           `json:"routerType"`
+	Route      *RouteTrace      `json:"route,omitempty"`
+	Conditions []ConditionTrace `json:"conditions,omitempty"`
+	Steps      []*StepTrace     `json:"steps,omitempty"`
+	StatusCode int              `json:"statusCode"`
+	Error      string           `json:"error,omitempty"`
+	StartMs    float64          `json:"startMs"`
+	DurationMs float64          `json:"durationMs"`
+}
+
+// RouteTrace is the step picked by a Splitter node or whose condition matched in a Switch node
+type RouteTrace struct {
+	StepName  string `json:"stepName"`
+	Condition string `json:"condition,omitempty"`
+}
+
+// ConditionTrace is the outcome of the condition of a Sequence step
+type ConditionTrace struct {
+	StepName  string `json:"stepName"`
+	Condition string `json:"condition"`
+	Matched   bool   `json:"matched"`
+}
+
+// StepTrace records an executed step, the steps targeting a node record the trace of the node.
+// In a dry run the services are not called and the step responds with its request.
+type StepTrace struct {
+	StepName   string     `json:"stepName"`
+	Target     string     `json:"target"`
+	DryRun     bool       `json:"dryRun,omitempty"`
+	Request    string     `json:"request"`
+	Response   string     `json:"response,omitempty"`
+	StatusCode int        `json:"statusCode"`
+	Error      string     `json:"error,omitempty"`
+	StartMs    float64    `json:"startMs"`
+	DurationMs float64    `json:"durationMs"`
+	Node       *NodeTrace `json:"node,omitempty"`
+}
+
+// graphTracer records the execution trace of a request, the steps of Ensemble and ForEach nodes record concurrently
+// and may still record after the node returned so all the records are guarded by the mutex
+type graphTracer struct {
+	mu     sync.Mutex
+	dryRun bool
+	start  time.Time
+	root   *NodeTrace
+}
+
+type (
+	graphTracerContextKey struct{}
+	nodeTraceContextKey   struct{}
+	stepTraceContextKey   struct{}
+)
+
+func withGraphTracer(ctx context.Context, tracer *graphTracer) context.Context {
+	return context.WithValue(ctx, graphTracerContextKey{}, tracer)
+}
+
+func graphTracerFromContext(ctx context.Context) *graphTracer {
+	tracer, _ := ctx.Value(graphTracerContextKey{}).(*graphTracer)
+	return tracer
+}
+
+// dryRunFromContext reports whether the services must not be called
+func dryRunFromContext(ctx context.Context) bool {
+	tracer := graphTracerFromContext(ctx)
+	return tracer != nil && tracer.dryRun
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// traceNodeStart records the node in the trace of the step calling it, or as the root of the trace
+func traceNodeStart(ctx context.Context, nodeName string, node v1alpha1.InferenceRouter) context.Context {
+	tracer := graphTracerFromContext(ctx)
+	if tracer == nil {
+		return ctx
+	}
+	nodeTrace := &NodeTrace{
+		NodeName:   nodeName,
+		RouterType: string(node.RouterType),
+		StartMs:    milliseconds(time.Since(tracer.start)),
+	}
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+	if stepTrace, ok := ctx.Value(stepTraceContextKey{}).(*StepTrace); ok {
+		stepTrace.Node = nodeTrace
+	} else {
+		tracer.root = nodeTrace
+	}
+	return context.WithValue(ctx, nodeTraceContextKey{}, nodeTrace)
+}
+
+func traceNodeEnd(ctx context.Context, statusCode int, err error) {
+	tracer := graphTracerFromContext(ctx)
+	nodeTrace, ok := ctx.Value(nodeTraceContextKey{}).(*NodeTrace)
+	if tracer == nil || !ok {
+		return
+	}
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+	nodeTrace.StatusCode = statusCode
+	nodeTrace.Error = errorString(err)
+	nodeTrace.DurationMs = milliseconds(time.Since(tracer.start)) - nodeTrace.StartMs
+}
+
+// traceRoute records the step picked by a Splitter or Switch node
+func traceRoute(ctx context.Context, step *v1alpha1.InferenceStep, condition string) {
+	tracer := graphTracerFromContext(ctx)
+	nodeTrace, ok := ctx.Value(nodeTraceContextKey{}).(*NodeTrace)
+	if tracer == nil || !ok {
+		return
+	}
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+	nodeTrace.Route = &RouteTrace{StepName: stepMetricsLabel(step), Condition: condition}
+}
+
+// traceCondition records the outcome of the condition of a Sequence step
+func traceCondition(ctx context.Context, step *v1alpha1.InferenceStep, matched bool) {
+	tracer := graphTracerFromContext(ctx)
+	nodeTrace, ok := ctx.Value(nodeTraceContextKey{}).(*NodeTrace)
+	if tracer == nil || !ok {
+		return
+	}
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+	nodeTrace.Conditions = append(nodeTrace.Conditions, ConditionTrace{
+		StepName:  stepMetricsLabel(step),
+		Condition: step.Condition,
+		Matched:   matched,
+	})
+}
+
+// traceStepStart records the step in the trace of its node
+func traceStepStart(ctx context.Context, step *v1alpha1.InferenceStep, input []byte) context.Context {
+	tracer := graphTracerFromContext(ctx)
+	nodeTrace, ok := ctx.Value(nodeTraceContextKey{}).(*NodeTrace)
+	if tracer == nil || !ok {
+		return ctx
+	}
+	target := step.NodeName
+	if target == "" {
+		target = step.ServiceURL
+	}
+	stepTrace := &StepTrace{
+		StepName: stepMetricsLabel(step),
+		Target:   target,
+		DryRun:   tracer.dryRun && step.NodeName == "" && step.ServiceURL != "",
+		Request:  bodyExcerpt(input),
+		StartMs:  milliseconds(time.Since(tracer.start)),
+	}
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+	nodeTrace.Steps = append(nodeTrace.Steps, stepTrace)
+	return context.WithValue(ctx, stepTraceContextKey{}, stepTrace)
+}
+
+func traceStepEnd(ctx context.Context, response []byte, statusCode int, err error) {
+	tracer := graphTracerFromContext(ctx)
+	stepTrace, ok := ctx.Value(stepTraceContextKey{}).(*StepTrace)
+	if tracer == nil || !ok {
+		return
+	}
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+	stepTrace.Response = bodyExcerpt(response)
+	stepTrace.StatusCode = statusCode
+	stepTrace.Error = errorString(err)
+	stepTrace.DurationMs = milliseconds(time.Since(tracer.start)) - stepTrace.StartMs
+}
+
+// traceHandler runs the request through the graph and responds with its execution trace, the status of the graph
+// response is reported in the trace
+func traceHandler(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "the trace endpoint only supports POST", http.StatusMethodNotAllowed)
+		return
+	}
+	dryRun, err := strconv.ParseBool(req.URL.Query().Get("dryRun"))
+	if err != nil && req.URL.Query().Get("dryRun") != "" {
+		http.Error(w, "dryRun must be a boolean", http.StatusBadRequest)
+		return
+	}
+	inputBytes, err := readBody(http.MaxBytesReader(w, req.Body, *maxBodySize))
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			statusCode = http.StatusRequestEntityTooLarge
+			err = &BodyTooLargeError{MaxBodySize: maxBytesErr.Limit}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statusCode)
+		w.Write(prepareErrorResponse(err, "Failed to read the request"))
+		return
+	}
+
+	tracer := &graphTracer{dryRun: dryRun, start: time.Now()}
+	ctx, span := startGraphSpan(req.Context(), req.Header)
+	response, statusCode, err := routeGraph(withGraphTracer(ctx, tracer), inputBytes, req.Header)
+	endSpan(span, statusCode, err)
+
+	graphTrace := &GraphTrace{
+		DryRun:     dryRun,
+		StatusCode: statusCode,
+		Response:   bodyExcerpt(response),
+		Error:      errorString(err),
+		DurationMs: milliseconds(time.Since(tracer.start)),
+	}
+	tracer.mu.Lock()
+	graphTrace.Root = tracer.root
+	traceBytes, err := json.Marshal(graphTrace)
+	tracer.mu.Unlock()
+	if err != nil {
+		log.Error(err, "failed to marshal the execution trace")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(traceBytes)
+}
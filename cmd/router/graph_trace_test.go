@@ -0,0 +1,186 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTraceHandler(t *testing.T) {
+	var calls int32
+	classifier := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = io.ReadAll(req.Body)
+		atomic.AddInt32(&calls, 1)
+		_, _ = rw.Write([]byte(`{"predictions": {"class": "dog"}}`))
+	}))
+	defer classifier.Close()
+	breed := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = io.ReadAll(req.Body)
+		atomic.AddInt32(&calls, 1)
+		_, _ = rw.Write([]byte(`{"predictions": ["husky"]}`))
+	}))
+	defer breed.Close()
+
+	storeGraph(t, &v1alpha1.InferenceGraphSpec{
+		Nodes: map[string]v1alpha1.InferenceRouter{
+			"root": {
+				RouterType: v1alpha1.Sequence,
+				Steps: []v1alpha1.InferenceStep{
+					{
+						StepName:        "classifier",
+						InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: classifier.URL},
+					},
+					{
+						StepName:        "breed",
+						InferenceTarget: v1alpha1.InferenceTarget{NodeName: "breed-classifier"},
+						Condition:       `body.predictions.class == "dog"`,
+					},
+				},
+			},
+			"breed-classifier": {
+				RouterType: v1alpha1.Splitter,
+				Steps: []v1alpha1.InferenceStep{
+					{
+						StepName:        "dog-breed",
+						InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: breed.URL},
+						Weight:          proto.Int64(100),
+					},
+				},
+			},
+		},
+	})
+	defer inferenceGraph.Store(nil)
+	send := func(method string, target string) (*httptest.ResponseRecorder, *GraphTrace) {
+		req := httptest.NewRequest(method, target, strings.NewReader(`{"instances": [1]}`))
+		rec := httptest.NewRecorder()
+		traceHandler(rec, req)
+		graphTrace := &GraphTrace{}
+		if rec.Code == http.StatusOK {
+			assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), graphTrace))
+		}
+		return rec, graphTrace
+	}
+
+	rec, graphTrace := send(http.MethodPost, graphTracePath)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	assert.Equal(t, http.StatusOK, graphTrace.StatusCode)
+	assert.JSONEq(t, `{"predictions": ["husky"]}`, graphTrace.Response)
+	root := graphTrace.Root
+	assert.Equal(t, "root", root.NodeName)
+	assert.Equal(t, []ConditionTrace{{StepName: "breed", Condition: `body.predictions.class == "dog"`, Matched: true}}, root.Conditions)
+	assert.Len(t, root.Steps, 2)
+	assert.Equal(t, classifier.URL, root.Steps[0].Target)
+	assert.Equal(t, `{"instances": [1]}`, root.Steps[0].Request)
+	assert.JSONEq(t, `{"predictions": {"class": "dog"}}`, root.Steps[0].Response)
+	assert.Equal(t, http.StatusOK, root.Steps[0].StatusCode)
+	assert.False(t, root.Steps[0].DryRun)
+	node := root.Steps[1].Node
+	assert.Equal(t, "breed-classifier", node.NodeName)
+	assert.Equal(t, &RouteTrace{StepName: "dog-breed"}, node.Route)
+	assert.Len(t, node.Steps, 1)
+	assert.Equal(t, breed.URL, node.Steps[0].Target)
+	assert.GreaterOrEqual(t, node.StartMs, root.Steps[0].StartMs)
+
+	// in a dry run the steps respond with their request so the condition of the breed step does not match
+	rec, graphTrace = send(http.MethodPost, graphTracePath+"?dryRun=true")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	assert.True(t, graphTrace.DryRun)
+	root = graphTrace.Root
+	assert.Len(t, root.Steps, 1)
+	assert.True(t, root.Steps[0].DryRun)
+	assert.Equal(t, `{"instances": [1]}`, root.Steps[0].Response)
+	assert.Equal(t, []ConditionTrace{{StepName: "breed", Condition: `body.predictions.class == "dog"`, Matched: false}}, root.Conditions)
+
+	rec, _ = send(http.MethodGet, graphTracePath)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	rec, _ = send(http.MethodPost, graphTracePath+"?dryRun=maybe")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestTraceDryRunIsolation(t *testing.T) {
+	var calls int32
+	countingServer := func() *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			_, _ = io.ReadAll(req.Body)
+			atomic.AddInt32(&calls, 1)
+			_, _ = rw.Write([]byte(`{"predictions": [1]}`))
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+	model, shadow := countingServer(), countingServer()
+
+	storeGraph(t, &v1alpha1.InferenceGraphSpec{
+		Nodes: map[string]v1alpha1.InferenceRouter{
+			"root": {
+				RouterType: v1alpha1.Splitter,
+				Steps: []v1alpha1.InferenceStep{
+					{
+						StepName:        "dry-run-cached",
+						InferenceTarget: v1alpha1.InferenceTarget{NodeName: "dry-run-model"},
+						Weight:          proto.Int64(100),
+						Cache:           &v1alpha1.StepCache{},
+						Mirror: &v1alpha1.StepMirror{
+							InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: shadow.URL},
+						},
+					},
+				},
+			},
+			"dry-run-model": {
+				RouterType: v1alpha1.Sequence,
+				Steps: []v1alpha1.InferenceStep{
+					{StepName: "model", InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: model.URL}},
+				},
+			},
+		},
+	})
+	defer inferenceGraph.Store(nil)
+
+	rec := httptest.NewRecorder()
+	traceHandler(rec, httptest.NewRequest(http.MethodPost, graphTracePath+"?dryRun=true", strings.NewReader(`{"instances": [1]}`)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	// the mirrored call would run detached from the request
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls))
+	responseCachesMu.Lock()
+	_, cached := responseCaches["root/dry-run-cached"]
+	responseCachesMu.Unlock()
+	assert.False(t, cached)
+	assert.Equal(t, 0.0, testutil.ToFloat64(stepCacheMissesTotal.WithLabelValues(graphName, "root", "dry-run-cached")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(splitterRoutesTotal.WithLabelValues(graphName, "root", "dry-run-cached")))
+
+	// a live request is not served from a response cached by the dry run
+	rec = httptest.NewRecorder()
+	graphHandler(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"instances": [1]}`)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `{"predictions": [1]}`, rec.Body.String())
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&calls) == 2 }, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,279 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	"github.com/kserve/kserve/pkg/constants"
+	"github.com/kserve/kserve/pkg/protocol/grpc/inference"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// isGRPCStep reports whether the step service is called over the v2 gRPC inference protocol
+func isGRPCStep(step *v1alpha1.InferenceStep) bool {
+	if step.ProtocolVersion != nil && *step.ProtocolVersion == constants.ProtocolGRPCV2 {
+		return true
+	}
+	return strings.HasPrefix(step.ServiceURL, "grpc://") || strings.HasPrefix(step.ServiceURL, "grpcs://")
+}
+
+// grpcTarget returns the dial target, model name and transport security for the service url of a gRPC step.
+// The model name is taken from the v1 or v2 model path, a `grpc://host:port/<model>` path, or the step serviceName.
+func grpcTarget(step *v1alpha1.InferenceStep) (string, string, bool, error) {
+	u, err := url.Parse(step.ServiceURL)
+	if err != nil {
+		return "", "", false, err
+	}
+	secure := u.Scheme == "https" || u.Scheme == "grpcs"
+	target := u.Host
+	if u.Port() == "" {
+		if secure {
+			target += ":443"
+		} else {
+			target += ":80"
+		}
+	}
+	modelName := step.ServiceName
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	switch {
+	case len(segments) >= 3 && (segments[0] == "v1" || segments[0] == "v2") && segments[1] == "models":
+		modelName = strings.SplitN(segments[2], ":", 2)[0]
+	case len(segments) == 1 && segments[0] != "":
+		modelName = segments[0]
+	}
+	if modelName == "" {
+		return "", "", false, fmt.Errorf("unable to determine the model name from service url %s", step.ServiceURL)
+	}
+	return target, modelName, secure, nil
+}
+
+var (
+	grpcConnsMu sync.Mutex
+	grpcConns   = map[string]*grpc.ClientConn{}
+)
+
+// getGRPCConn returns the cached client connection for the target, connections are shared by all the steps
+func getGRPCConn(target string, secure bool) (*grpc.ClientConn, error) {
+	grpcConnsMu.Lock()
+	defer grpcConnsMu.Unlock()
+	if conn, ok := grpcConns[target]; ok {
+		return conn, nil
+	}
+	transportCredentials := insecure.NewCredentials()
+	if secure {
+		transportCredentials = credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+	}
+	conn, err := grpc.Dial(target, grpc.WithTransportCredentials(transportCredentials))
+	if err != nil {
+		return nil, err
+	}
+	grpcConns[target] = conn
+	return conn, nil
+}
+
+// httpStatusFromCode maps the gRPC status code of a step response to the http status code of the step
+func httpStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusInternalServerError
+}
+
+// grpcCodeFromHTTPStatus maps the http status code of a graph response to the gRPC status code of the router
+func grpcCodeFromHTTPStatus(statusCode int) codes.Code {
+	switch statusCode {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+	if isSuccessFul(statusCode) {
+		return codes.OK
+	}
+	return codes.Internal
+}
+
+// callGRPCService calls the step service over the v2 gRPC inference protocol. The json v2 request is converted to
+// a ModelInferRequest and the ModelInferResponse back to a json v2 response so that the rest of the graph is
+// unaware of the transport. gRPC errors of the service are returned as a json error body with the mapped status code.
+func callGRPCService(ctx context.Context, step *v1alpha1.InferenceStep, input []byte, headers http.Header) ([]byte, int, error) {
+	defer timeTrack(time.Now(), "step", step.ServiceURL)
+	log.Info("Entering callGRPCService", "url", step.ServiceURL)
+	target, modelName, secure, err := grpcTarget(step)
+	if err != nil {
+		return nil, 500, err
+	}
+	inferRequest := InferRequest{}
+	if err := json.Unmarshal(input, &inferRequest); err != nil {
+		return nil, 400, fmt.Errorf("request to gRPC step %s is not a valid v2 inference request: %w", step.StepName, err)
+	}
+	request, err := jsonRequestToGRPC(modelName, &inferRequest)
+	if err != nil {
+		return nil, 400, err
+	}
+	conn, err := getGRPCConn(target, secure)
+	if err != nil {
+		log.Error(err, "An error has occurred while connecting to service", "service", step.ServiceURL)
+		return nil, 500, err
+	}
+	md := metadata.MD{}
+	for _, h := range headersToPropagate {
+		if values, ok := headers[h]; ok {
+			md.Append(h, values...)
+		}
+	}
+	if auth := authHeaderFromContext(ctx); auth != nil {
+		md.Set(auth.name, auth.value)
+	}
+	propagator.Inject(ctx, metadataCarrier(md))
+	response, err := inference.NewGRPCInferenceServiceClient(conn).ModelInfer(metadata.NewOutgoingContext(ctx, md), request)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, 500, ctx.Err()
+		}
+		st := status.Convert(err)
+		log.Error(err, "An error has occurred while calling service", "service", step.ServiceURL, "code", st.Code())
+		errorBody, _ := json.Marshal(map[string]string{"error": st.Message()})
+		return errorBody, httpStatusFromCode(st.Code()), nil
+	}
+	inferResponse, err := grpcResponseToJSON(response)
+	if err != nil {
+		return nil, 500, err
+	}
+	responseBytes, err := json.Marshal(inferResponse)
+	if err != nil {
+		return nil, 500, err
+	}
+	return responseBytes, http.StatusOK, nil
+}
+
+// graphGRPCServer serves the inference graph over the v2 gRPC inference protocol, the whole graph is exposed as a
+// single model and every ModelInfer call is routed through the root node as a json v2 request.
+type graphGRPCServer struct {
+	inference.UnimplementedGRPCInferenceServiceServer
+}
+
+func (s *graphGRPCServer) ServerLive(ctx context.Context, req *inference.ServerLiveRequest) (*inference.ServerLiveResponse, error) {
+	return &inference.ServerLiveResponse{Live: true}, nil
+}
+
+func (s *graphGRPCServer) ServerReady(ctx context.Context, req *inference.ServerReadyRequest) (*inference.ServerReadyResponse, error) {
+	return &inference.ServerReadyResponse{Ready: true}, nil
+}
+
+func (s *graphGRPCServer) ModelReady(ctx context.Context, req *inference.ModelReadyRequest) (*inference.ModelReadyResponse, error) {
+	return &inference.ModelReadyResponse{Ready: true}, nil
+}
+
+func (s *graphGRPCServer) ModelInfer(ctx context.Context, req *inference.ModelInferRequest) (*inference.ModelInferResponse, error) {
+	inferRequest, err := grpcRequestToJSON(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	input, err := json.Marshal(inferRequest)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	headers := http.Header{}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for key, values := range md {
+			for _, value := range values {
+				headers.Add(key, value)
+			}
+		}
+	}
+	start := time.Now()
+	ctx, span := startGraphSpan(ctx, headers)
+	responseBytes, statusCode, err := routeGraph(ctx, input, headers)
+	endSpan(span, statusCode, err)
+	observeRequest(start, statusCode, err)
+	if err != nil {
+		log.Error(err, "failed to process gRPC request")
+		return nil, status.Error(grpcCodeFromHTTPStatus(statusCode), err.Error())
+	}
+	if !isSuccessFul(statusCode) {
+		return nil, status.Error(grpcCodeFromHTTPStatus(statusCode), string(responseBytes))
+	}
+	inferResponse := InferResponse{}
+	if err := json.Unmarshal(responseBytes, &inferResponse); err != nil {
+		return nil, status.Error(codes.Internal, "graph response is not a valid v2 inference response")
+	}
+	response, err := jsonResponseToGRPC(&inferResponse)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if response.ModelName == "" {
+		response.ModelName = req.ModelName
+	}
+	return response, nil
+}
+
+// grpcHandlerFunc dispatches gRPC requests to the gRPC server and all other requests to the http handler,
+// so that both protocols are served on the same port.
+func grpcHandlerFunc(grpcServer *grpc.Server, httpHandler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+			grpcServer.ServeHTTP(w, r)
+			return
+		}
+		httpHandler.ServeHTTP(w, r)
+	})
+}
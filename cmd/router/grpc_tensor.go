@@ -0,0 +1,441 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/kserve/kserve/pkg/protocol/grpc/inference"
+)
+
+// flattenData flattens the possibly nested json data of a tensor in row major order
+func flattenData(data interface{}) []interface{} {
+	values, ok := data.([]interface{})
+	if !ok {
+		return []interface{}{data}
+	}
+	flat := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		if nested, ok := value.([]interface{}); ok {
+			flat = append(flat, flattenData(nested)...)
+		} else {
+			flat = append(flat, value)
+		}
+	}
+	return flat
+}
+
+// decodeData decodes the json data of a tensor with the numbers kept as json.Number, so that 64 bit integers beyond the
+// float64 precision are converted exactly
+func decodeData(data json.RawMessage) (interface{}, error) {
+	var decoded interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("invalid tensor data: %w", err)
+	}
+	return decoded, nil
+}
+
+func toNumber(value interface{}) (json.Number, error) {
+	number, ok := value.(json.Number)
+	if !ok {
+		return "", fmt.Errorf("expected a number but got %v", value)
+	}
+	return number, nil
+}
+
+func toInt64(value interface{}) (int64, error) {
+	number, err := toNumber(value)
+	if err != nil {
+		return 0, err
+	}
+	if i, err := number.Int64(); err == nil {
+		return i, nil
+	}
+	f, err := number.Float64()
+	if err != nil {
+		return 0, fmt.Errorf("expected an integer but got %v", number)
+	}
+	return int64(f), nil
+}
+
+func toUint64(value interface{}) (uint64, error) {
+	number, err := toNumber(value)
+	if err != nil {
+		return 0, err
+	}
+	if u, err := strconv.ParseUint(number.String(), 10, 64); err == nil {
+		return u, nil
+	}
+	f, err := number.Float64()
+	if err != nil {
+		return 0, fmt.Errorf("expected an unsigned integer but got %v", number)
+	}
+	return uint64(f), nil
+}
+
+func toFloat64(value interface{}) (float64, error) {
+	number, err := toNumber(value)
+	if err != nil {
+		return 0, err
+	}
+	return number.Float64()
+}
+
+// dataToContents converts the json data of a tensor to the typed contents of a gRPC tensor
+func dataToContents(datatype string, data json.RawMessage) (*inference.InferTensorContents, error) {
+	contents := &inference.InferTensorContents{}
+	if len(data) == 0 {
+		return contents, nil
+	}
+	decoded, err := decodeData(data)
+	if err != nil {
+		return nil, err
+	}
+	if decoded == nil {
+		return contents, nil
+	}
+	for _, value := range flattenData(decoded) {
+		switch datatype {
+		case "BOOL":
+			b, ok := value.(bool)
+			if !ok {
+				return nil, fmt.Errorf("expected a bool but got %v", value)
+			}
+			contents.BoolContents = append(contents.BoolContents, b)
+		case "BYTES":
+			s, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected a string but got %v", value)
+			}
+			contents.BytesContents = append(contents.BytesContents, []byte(s))
+		case "INT8", "INT16", "INT32":
+			i, err := toInt64(value)
+			if err != nil {
+				return nil, err
+			}
+			contents.IntContents = append(contents.IntContents, int32(i))
+		case "INT64":
+			i, err := toInt64(value)
+			if err != nil {
+				return nil, err
+			}
+			contents.Int64Contents = append(contents.Int64Contents, i)
+		case "UINT8", "UINT16", "UINT32":
+			u, err := toUint64(value)
+			if err != nil {
+				return nil, err
+			}
+			contents.UintContents = append(contents.UintContents, uint32(u))
+		case "UINT64":
+			u, err := toUint64(value)
+			if err != nil {
+				return nil, err
+			}
+			contents.Uint64Contents = append(contents.Uint64Contents, u)
+		case "FP32":
+			f, err := toFloat64(value)
+			if err != nil {
+				return nil, err
+			}
+			contents.Fp32Contents = append(contents.Fp32Contents, float32(f))
+		case "FP64":
+			f, err := toFloat64(value)
+			if err != nil {
+				return nil, err
+			}
+			contents.Fp64Contents = append(contents.Fp64Contents, f)
+		default:
+			return nil, fmt.Errorf("unsupported datatype %s", datatype)
+		}
+	}
+	return contents, nil
+}
+
+// contentsToData converts the typed contents of a gRPC tensor to flattened json data
+func contentsToData(datatype string, contents *inference.InferTensorContents) ([]interface{}, error) {
+	data := []interface{}{}
+	if contents == nil {
+		return data, nil
+	}
+	switch datatype {
+	case "BOOL":
+		for _, v := range contents.BoolContents {
+			data = append(data, v)
+		}
+	case "INT8", "INT16", "INT32":
+		for _, v := range contents.IntContents {
+			data = append(data, v)
+		}
+	case "INT64":
+		for _, v := range contents.Int64Contents {
+			data = append(data, v)
+		}
+	case "UINT8", "UINT16", "UINT32":
+		for _, v := range contents.UintContents {
+			data = append(data, v)
+		}
+	case "UINT64":
+		for _, v := range contents.Uint64Contents {
+			data = append(data, v)
+		}
+	case "FP32":
+		for _, v := range contents.Fp32Contents {
+			data = append(data, v)
+		}
+	case "FP64":
+		for _, v := range contents.Fp64Contents {
+			data = append(data, v)
+		}
+	case "BYTES":
+		for _, v := range contents.BytesContents {
+			data = append(data, string(v))
+		}
+	default:
+		return nil, fmt.Errorf("unsupported datatype %s", datatype)
+	}
+	return data, nil
+}
+
+// rawToData converts the little endian raw contents of a gRPC tensor to flattened json data.
+// BYTES elements are prefixed by their 4 byte length.
+func rawToData(datatype string, raw []byte) ([]interface{}, error) {
+	data := []interface{}{}
+	if datatype == "BYTES" {
+		for len(raw) > 0 {
+			if len(raw) < 4 {
+				return nil, fmt.Errorf("invalid raw BYTES contents")
+			}
+			size := binary.LittleEndian.Uint32(raw)
+			raw = raw[4:]
+			if uint32(len(raw)) < size {
+				return nil, fmt.Errorf("invalid raw BYTES contents")
+			}
+			data = append(data, string(raw[:size]))
+			raw = raw[size:]
+		}
+		return data, nil
+	}
+	sizes := map[string]int{
+		"BOOL": 1, "INT8": 1, "UINT8": 1, "INT16": 2, "UINT16": 2, "INT32": 4, "UINT32": 4,
+		"INT64": 8, "UINT64": 8, "FP32": 4, "FP64": 8,
+	}
+	size, ok := sizes[datatype]
+	if !ok {
+		return nil, fmt.Errorf("unsupported datatype %s", datatype)
+	}
+	if len(raw)%size != 0 {
+		return nil, fmt.Errorf("raw contents size %d is not a multiple of the %s element size", len(raw), datatype)
+	}
+	for i := 0; i < len(raw); i += size {
+		element := raw[i : i+size]
+		switch datatype {
+		case "BOOL":
+			data = append(data, element[0] != 0)
+		case "INT8":
+			data = append(data, int8(element[0]))
+		case "UINT8":
+			data = append(data, element[0])
+		case "INT16":
+			data = append(data, int16(binary.LittleEndian.Uint16(element)))
+		case "UINT16":
+			data = append(data, binary.LittleEndian.Uint16(element))
+		case "INT32":
+			data = append(data, int32(binary.LittleEndian.Uint32(element)))
+		case "UINT32":
+			data = append(data, binary.LittleEndian.Uint32(element))
+		case "INT64":
+			data = append(data, int64(binary.LittleEndian.Uint64(element)))
+		case "UINT64":
+			data = append(data, binary.LittleEndian.Uint64(element))
+		case "FP32":
+			data = append(data, math.Float32frombits(binary.LittleEndian.Uint32(element)))
+		case "FP64":
+			data = append(data, math.Float64frombits(binary.LittleEndian.Uint64(element)))
+		}
+	}
+	return data, nil
+}
+
+func parametersToJSON(parameters map[string]*inference.InferParameter) map[string]interface{} {
+	if len(parameters) == 0 {
+		return nil
+	}
+	converted := make(map[string]interface{}, len(parameters))
+	for key, parameter := range parameters {
+		switch choice := parameter.GetParameterChoice().(type) {
+		case *inference.InferParameter_BoolParam:
+			converted[key] = choice.BoolParam
+		case *inference.InferParameter_Int64Param:
+			converted[key] = choice.Int64Param
+		case *inference.InferParameter_StringParam:
+			converted[key] = choice.StringParam
+		}
+	}
+	return converted
+}
+
+func parametersToGRPC(parameters map[string]interface{}) map[string]*inference.InferParameter {
+	if len(parameters) == 0 {
+		return nil
+	}
+	converted := make(map[string]*inference.InferParameter, len(parameters))
+	for key, value := range parameters {
+		switch v := value.(type) {
+		case bool:
+			converted[key] = &inference.InferParameter{ParameterChoice: &inference.InferParameter_BoolParam{BoolParam: v}}
+		case float64:
+			converted[key] = &inference.InferParameter{ParameterChoice: &inference.InferParameter_Int64Param{Int64Param: int64(v)}}
+		case string:
+			converted[key] = &inference.InferParameter{ParameterChoice: &inference.InferParameter_StringParam{StringParam: v}}
+		default:
+			converted[key] = &inference.InferParameter{ParameterChoice: &inference.InferParameter_StringParam{StringParam: fmt.Sprint(v)}}
+		}
+	}
+	return converted
+}
+
+// tensorData returns the json data of the i-th tensor, raw contents take precedence over typed contents
+func tensorData(datatype string, contents *inference.InferTensorContents, rawContents [][]byte, i int) (json.RawMessage, error) {
+	var data []interface{}
+	var err error
+	if len(rawContents) > 0 {
+		if i >= len(rawContents) {
+			return nil, fmt.Errorf("missing raw contents for tensor %d", i)
+		}
+		data, err = rawToData(datatype, rawContents[i])
+	} else {
+		data, err = contentsToData(datatype, contents)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(data)
+}
+
+// grpcRequestToJSON converts a gRPC v2 inference request to the json v2 inference request
+func grpcRequestToJSON(request *inference.ModelInferRequest) (*InferRequest, error) {
+	inferRequest := &InferRequest{
+		ID:         request.Id,
+		Parameters: parametersToJSON(request.Parameters),
+		Inputs:     make([]InferTensor, len(request.Inputs)),
+	}
+	for i, input := range request.Inputs {
+		data, err := tensorData(input.Datatype, input.Contents, request.RawInputContents, i)
+		if err != nil {
+			return nil, fmt.Errorf("invalid input %s: %w", input.Name, err)
+		}
+		inferRequest.Inputs[i] = InferTensor{
+			Name:       input.Name,
+			Shape:      input.Shape,
+			Datatype:   input.Datatype,
+			Parameters: parametersToJSON(input.Parameters),
+			Data:       data,
+		}
+	}
+	for _, output := range request.Outputs {
+		inferRequest.Outputs = append(inferRequest.Outputs, InferRequestedOutput{
+			Name:       output.Name,
+			Parameters: parametersToJSON(output.Parameters),
+		})
+	}
+	return inferRequest, nil
+}
+
+// jsonRequestToGRPC converts a json v2 inference request to the gRPC v2 inference request for the model
+func jsonRequestToGRPC(modelName string, inferRequest *InferRequest) (*inference.ModelInferRequest, error) {
+	request := &inference.ModelInferRequest{
+		ModelName:  modelName,
+		Id:         inferRequest.ID,
+		Parameters: parametersToGRPC(inferRequest.Parameters),
+	}
+	for _, input := range inferRequest.Inputs {
+		contents, err := dataToContents(input.Datatype, input.Data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid input %s: %w", input.Name, err)
+		}
+		request.Inputs = append(request.Inputs, &inference.ModelInferRequest_InferInputTensor{
+			Name:       input.Name,
+			Datatype:   input.Datatype,
+			Shape:      input.Shape,
+			Parameters: parametersToGRPC(input.Parameters),
+			Contents:   contents,
+		})
+	}
+	for _, output := range inferRequest.Outputs {
+		request.Outputs = append(request.Outputs, &inference.ModelInferRequest_InferRequestedOutputTensor{
+			Name:       output.Name,
+			Parameters: parametersToGRPC(output.Parameters),
+		})
+	}
+	return request, nil
+}
+
+// grpcResponseToJSON converts a gRPC v2 inference response to the json v2 inference response
+func grpcResponseToJSON(response *inference.ModelInferResponse) (*InferResponse, error) {
+	inferResponse := &InferResponse{
+		ModelName:    response.ModelName,
+		ModelVersion: response.ModelVersion,
+		ID:           response.Id,
+		Parameters:   parametersToJSON(response.Parameters),
+		Outputs:      make([]InferTensor, len(response.Outputs)),
+	}
+	for i, output := range response.Outputs {
+		data, err := tensorData(output.Datatype, output.Contents, response.RawOutputContents, i)
+		if err != nil {
+			return nil, fmt.Errorf("invalid output %s: %w", output.Name, err)
+		}
+		inferResponse.Outputs[i] = InferTensor{
+			Name:       output.Name,
+			Shape:      output.Shape,
+			Datatype:   output.Datatype,
+			Parameters: parametersToJSON(output.Parameters),
+			Data:       data,
+		}
+	}
+	return inferResponse, nil
+}
+
+// jsonResponseToGRPC converts a json v2 inference response to the gRPC v2 inference response
+func jsonResponseToGRPC(inferResponse *InferResponse) (*inference.ModelInferResponse, error) {
+	response := &inference.ModelInferResponse{
+		ModelName:    inferResponse.ModelName,
+		ModelVersion: inferResponse.ModelVersion,
+		Id:           inferResponse.ID,
+		Parameters:   parametersToGRPC(inferResponse.Parameters),
+	}
+	for _, output := range inferResponse.Outputs {
+		contents, err := dataToContents(output.Datatype, output.Data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid output %s: %w", output.Name, err)
+		}
+		response.Outputs = append(response.Outputs, &inference.ModelInferResponse_InferOutputTensor{
+			Name:       output.Name,
+			Datatype:   output.Datatype,
+			Shape:      output.Shape,
+			Parameters: parametersToGRPC(output.Parameters),
+			Contents:   contents,
+		})
+	}
+	return response, nil
+}
@@ -0,0 +1,272 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	"github.com/kserve/kserve/pkg/constants"
+	"github.com/kserve/kserve/pkg/protocol/grpc/inference"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeModelServer doubles the FP32 input tensor of the request
+type fakeModelServer struct {
+	inference.UnimplementedGRPCInferenceServiceServer
+	modelName string
+	metadata  metadata.MD
+}
+
+func (s *fakeModelServer) ModelInfer(ctx context.Context, req *inference.ModelInferRequest) (*inference.ModelInferResponse, error) {
+	s.modelName = req.ModelName
+	s.metadata, _ = metadata.FromIncomingContext(ctx)
+	if len(req.Inputs) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no inputs")
+	}
+	output := &inference.ModelInferResponse_InferOutputTensor{
+		Name:     "output-0",
+		Datatype: "FP32",
+		Shape:    req.Inputs[0].Shape,
+		Contents: &inference.InferTensorContents{},
+	}
+	for _, v := range req.Inputs[0].Contents.Fp32Contents {
+		output.Contents.Fp32Contents = append(output.Contents.Fp32Contents, v*2)
+	}
+	return &inference.ModelInferResponse{ModelName: req.ModelName, Id: req.Id, Outputs: []*inference.ModelInferResponse_InferOutputTensor{output}}, nil
+}
+
+func startFakeModelServer(t *testing.T) (*fakeModelServer, string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	model := &fakeModelServer{}
+	server := grpc.NewServer()
+	inference.RegisterGRPCInferenceServiceServer(server, model)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+	return model, listener.Addr().String()
+}
+
+func TestGRPCTarget(t *testing.T) {
+	grpcV2 := constants.ProtocolGRPCV2
+	scenarios := map[string]struct {
+		step      v1alpha1.InferenceStep
+		target    string
+		modelName string
+		secure    bool
+	}{
+		"grpc scheme": {
+			step:      v1alpha1.InferenceStep{InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: "grpc://model.default:8081/mnist"}},
+			target:    "model.default:8081",
+			modelName: "mnist",
+		},
+		"grpcs scheme": {
+			step:      v1alpha1.InferenceStep{InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: "grpcs://model.example.com/mnist"}},
+			target:    "model.example.com:443",
+			modelName: "mnist",
+			secure:    true,
+		},
+		"v2 path": {
+			step: v1alpha1.InferenceStep{
+				InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: "http://model.default.svc.cluster.local/v2/models/mnist/infer"},
+				ProtocolVersion: &grpcV2,
+			},
+			target:    "model.default.svc.cluster.local:80",
+			modelName: "mnist",
+		},
+		"service name": {
+			step: v1alpha1.InferenceStep{
+				InferenceTarget: v1alpha1.InferenceTarget{ServiceName: "mnist", ServiceURL: "http://mnist.default.svc.cluster.local"},
+				ProtocolVersion: &grpcV2,
+			},
+			target:    "mnist.default.svc.cluster.local:80",
+			modelName: "mnist",
+		},
+	}
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			assert.True(t, isGRPCStep(&scenario.step))
+			target, modelName, secure, err := grpcTarget(&scenario.step)
+			assert.Nil(t, err)
+			assert.Equal(t, scenario.target, target)
+			assert.Equal(t, scenario.modelName, modelName)
+			assert.Equal(t, scenario.secure, secure)
+		})
+	}
+	assert.False(t, isGRPCStep(&v1alpha1.InferenceStep{InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: "http://model"}}))
+}
+
+func TestRawToData(t *testing.T) {
+	fp32 := make([]byte, 8)
+	binary.LittleEndian.PutUint32(fp32, math.Float32bits(1.5))
+	binary.LittleEndian.PutUint32(fp32[4:], math.Float32bits(-2))
+	data, err := rawToData("FP32", fp32)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{float32(1.5), float32(-2)}, data)
+
+	bytesRaw := []byte{3, 0, 0, 0, 'd', 'o', 'g', 0, 0, 0, 0}
+	data, err = rawToData("BYTES", bytesRaw)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"dog", ""}, data)
+
+	_, err = rawToData("INT32", []byte{1, 2, 3})
+	assert.NotNil(t, err)
+	_, err = rawToData("BYTES", []byte{9, 0, 0, 0, 'a'})
+	assert.NotNil(t, err)
+}
+
+func TestGRPCRequestToJSONWithRawContents(t *testing.T) {
+	raw := make([]byte, 8)
+	binary.LittleEndian.PutUint64(raw, uint64(42))
+	request := &inference.ModelInferRequest{
+		Id: "1",
+		Inputs: []*inference.ModelInferRequest_InferInputTensor{
+			{Name: "input-0", Datatype: "INT64", Shape: []int64{1}},
+		},
+		RawInputContents: [][]byte{raw},
+	}
+	inferRequest, err := grpcRequestToJSON(request)
+	assert.Nil(t, err)
+	assert.Equal(t, "1", inferRequest.ID)
+	assert.JSONEq(t, `[42]`, string(inferRequest.Inputs[0].Data))
+}
+
+func TestJSONRequestToGRPCKeepsInt64Precision(t *testing.T) {
+	var inferRequest InferRequest
+	err := json.Unmarshal([]byte(`{"id":"1","inputs":[`+
+		`{"name":"ids","shape":[2,2],"datatype":"INT64","data":[[9007199254740993,-9007199254740993],[1,2]]},`+
+		`{"name":"counts","shape":[1],"datatype":"UINT64","data":[18446744073709551615]},`+
+		`{"name":"scores","shape":[2],"datatype":"FP32","data":[0.5,2]}]}`), &inferRequest)
+	assert.Nil(t, err)
+	request, err := jsonRequestToGRPC("model", &inferRequest)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{9007199254740993, -9007199254740993, 1, 2}, request.Inputs[0].Contents.Int64Contents)
+	assert.Equal(t, []uint64{18446744073709551615}, request.Inputs[1].Contents.Uint64Contents)
+	assert.Equal(t, []float32{0.5, 2}, request.Inputs[2].Contents.Fp32Contents)
+
+	inferRequest.Inputs[0].Data = json.RawMessage(`["a"]`)
+	_, err = jsonRequestToGRPC("model", &inferRequest)
+	assert.NotNil(t, err)
+}
+
+func TestCallGRPCService(t *testing.T) {
+	model, address := startFakeModelServer(t)
+	headersToPropagate = []string{"Authorization"}
+	defer func() { headersToPropagate = nil }()
+
+	step := &v1alpha1.InferenceStep{
+		StepName:        "doubler",
+		InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: "grpc://" + address + "/doubler"},
+	}
+	input := []byte(`{"id":"1","inputs":[{"name":"input-0","shape":[2,2],"datatype":"FP32","data":[[1,2],[3,4]]}]}`)
+	headers := http.Header{"Authorization": {"Bearer token"}}
+	res, statusCode, err := callStepService(context.Background(), step, input, headers)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.JSONEq(t, `{"model_name":"doubler","id":"1","outputs":[{"name":"output-0","shape":[2,2],"datatype":"FP32","data":[2,4,6,8]}]}`,
+		string(res))
+	assert.Equal(t, "doubler", model.modelName)
+	assert.Equal(t, []string{"Bearer token"}, model.metadata.Get("authorization"))
+
+	// gRPC errors of the service are mapped to http status codes
+	res, statusCode, err = callStepService(context.Background(), step, []byte(`{"inputs":[]}`), headers)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Equal(t, "no inputs", gjson.GetBytes(res, "error").String())
+}
+
+func TestGraphGRPCServer(t *testing.T) {
+	_, address := startFakeModelServer(t)
+	protocolV2 := constants.ProtocolV2
+	storeGraph(t, &v1alpha1.InferenceGraphSpec{
+		Nodes: map[string]v1alpha1.InferenceRouter{
+			v1alpha1.GraphRootNodeName: {
+				RouterType:      v1alpha1.Sequence,
+				ProtocolVersion: &protocolV2,
+				Steps: []v1alpha1.InferenceStep{
+					{
+						StepName:        "first",
+						InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: "grpc://" + address + "/doubler"},
+						Dependency:      v1alpha1.Hard,
+					},
+					{
+						StepName:        "second",
+						InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: "grpc://" + address + "/doubler"},
+						Data:            "$response",
+					},
+				},
+			},
+		},
+	})
+	defer inferenceGraph.Store(nil)
+
+	grpcServer := grpc.NewServer()
+	inference.RegisterGRPCInferenceServiceServer(grpcServer, &graphGRPCServer{})
+	router := httptest.NewServer(h2c.NewHandler(grpcHandlerFunc(grpcServer, http.HandlerFunc(graphHandler)), &http2.Server{}))
+	defer router.Close()
+
+	conn, err := grpc.Dial(router.Listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.Nil(t, err)
+	defer conn.Close()
+	client := inference.NewGRPCInferenceServiceClient(conn)
+
+	live, err := client.ServerLive(context.Background(), &inference.ServerLiveRequest{})
+	assert.Nil(t, err)
+	assert.True(t, live.Live)
+
+	response, err := client.ModelInfer(context.Background(), &inference.ModelInferRequest{
+		ModelName: "graph",
+		Inputs: []*inference.ModelInferRequest_InferInputTensor{
+			{
+				Name:     "input-0",
+				Datatype: "FP32",
+				Shape:    []int64{3},
+				Contents: &inference.InferTensorContents{Fp32Contents: []float32{1, 2, 3}},
+			},
+		},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []float32{4, 8, 12}, response.Outputs[0].Contents.Fp32Contents)
+
+	// the http data plane is still served on the same port
+	httpResponse, err := http.Post(router.URL, "application/json",
+		strings.NewReader(`{"inputs":[{"name":"input-0","shape":[1],"datatype":"FP32","data":[1]}]}`))
+	assert.Nil(t, err)
+	defer httpResponse.Body.Close()
+	assert.Equal(t, http.StatusOK, httpResponse.StatusCode)
+
+	_, err = client.ModelInfer(context.Background(), &inference.ModelInferRequest{ModelName: "graph"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -18,34 +18,45 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/pkg/errors"
 	"io"
 	"net/http"
 	"os"
-	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/kserve/kserve/pkg/constants"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/tidwall/gjson"
+	"go.opentelemetry.io/otel/propagation"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
-	"math/rand"
-
 	"github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	"github.com/kserve/kserve/pkg/inferencegraph/condition"
+	"github.com/kserve/kserve/pkg/inferencegraph/mapping"
+	"github.com/kserve/kserve/pkg/protocol/grpc/inference"
 	flag "github.com/spf13/pflag"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
 )
 
 var log = logf.Log.WithName("InferenceGraphRouter")
 
-func callService(serviceUrl string, input []byte, headers http.Header) ([]byte, int, error) {
+func callService(ctx context.Context, serviceUrl string, input []byte, headers http.Header) ([]byte, int, error) {
 	defer timeTrack(time.Now(), "step", serviceUrl)
 	log.Info("Entering callService", "url", serviceUrl)
-	req, err := http.NewRequest("POST", serviceUrl, bytes.NewBuffer(input))
+	req, err := http.NewRequestWithContext(ctx, "POST", serviceUrl, bytes.NewBuffer(input))
+	if err != nil {
+		log.Error(err, "An error has occurred while creating the request", "service", serviceUrl)
+		return nil, 500, err
+	}
 	for _, h := range headersToPropagate {
 		if values, ok := headers[h]; ok {
 			for _, v := range values {
@@ -53,6 +64,10 @@ func callService(serviceUrl string, input []byte, headers http.Header) ([]byte,
 			}
 		}
 	}
+	if auth := authHeaderFromContext(ctx); auth != nil {
+		req.Header.Set(auth.name, auth.value)
+	}
+	propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
 	req.Header.Add("Content-Type", "application/json")
 	resp, err := http.DefaultClient.Do(req)
 
@@ -61,51 +76,173 @@ func callService(serviceUrl string, input []byte, headers http.Header) ([]byte,
 		return nil, 500, err
 	}
 	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
+	// only successful responses are streamed, failed responses are buffered so that the step can be retried
+	if stream := streamFromContext(ctx); stream != nil && isSuccessFul(resp.StatusCode) {
+		if isStreamingResponse(resp) {
+			return streamResponse(ctx, stream, resp, serviceUrl)
+		}
+		if isChunkedResponse(resp) {
+			head, err := io.ReadAll(io.LimitReader(resp.Body, *maxBodySize+1))
+			if err != nil {
+				log.Error(err, "error while reading the response")
+				return nil, resp.StatusCode, err
+			}
+			if int64(len(head)) <= *maxBodySize {
+				return head, resp.StatusCode, nil
+			}
+			resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(head), resp.Body))
+			return streamResponse(ctx, stream, resp, serviceUrl)
+		}
+	}
+	body, err := readBody(resp.Body)
 	if err != nil {
 		log.Error(err, "error while reading the response")
+		if _, ok := err.(*BodyTooLargeError); ok {
+			return nil, http.StatusBadGateway, err
+		}
 	}
 	return body, resp.StatusCode, err
 }
 
-func pickupRoute(routes []v1alpha1.InferenceStep) *v1alpha1.InferenceStep {
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
-	//generate num [0,100)
-	point := r.Intn(99)
-	end := 0
-	for _, route := range routes {
-		end += int(*route.Weight)
-		if point < end {
-			return &route
-		}
+// streamResponse pipes the response to the client, the step timeout no longer applies once the response is streamed
+func streamResponse(ctx context.Context, stream *responseStream, resp *http.Response, serviceUrl string) ([]byte, int, error) {
+	if !stopStepTimeout(ctx) {
+		return nil, resp.StatusCode, ctx.Err()
 	}
-	return nil
+	log.Info("Streaming the response to the client", "service", serviceUrl)
+	if err := stream.pipe(resp); err != nil {
+		log.Error(err, "error while streaming the response", "service", serviceUrl)
+		return nil, resp.StatusCode, err
+	}
+	return nil, resp.StatusCode, nil
 }
 
-func pickupRouteByCondition(input []byte, routes []v1alpha1.InferenceStep) *v1alpha1.InferenceStep {
+func pickupRouteByCondition(ctx context.Context, input []byte, headers http.Header, routes []v1alpha1.InferenceStep) *v1alpha1.InferenceStep {
 	if !gjson.ValidBytes(input) {
 		return nil
 	}
 	for _, route := range routes {
-		if gjson.GetBytes(input, route.Condition).Exists() {
+		if matchCondition(ctx, route.Condition, input, headers) {
 			return &route
 		}
 	}
 	return nil
 }
 
+// compiledGraph is a loaded graph with the compiled conditions and data mappings of its steps. It is swapped as a
+// whole when the graph is reloaded so the compiled expressions of the previous graph are released with it.
+type compiledGraph struct {
+	spec       *v1alpha1.InferenceGraphSpec
+	conditions map[string]*condition.Condition
+	mappings   map[string]*mapping.Mapping
+}
+
+// compileGraph compiles the conditions and data mappings of all the graph steps so that they fail the router at startup
+func compileGraph(graph *v1alpha1.InferenceGraphSpec) (*compiledGraph, error) {
+	compiled := &compiledGraph{
+		spec:       graph,
+		conditions: map[string]*condition.Condition{},
+		mappings:   map[string]*mapping.Mapping{},
+	}
+	for nodeName, node := range graph.Nodes {
+		for _, step := range node.Steps {
+			if step.Condition != "" {
+				c, err := condition.Compile(step.Condition)
+				if err != nil {
+					return nil, errors.Wrapf(err, "invalid condition for step %s of node %s", step.StepName, nodeName)
+				}
+				compiled.conditions[step.Condition] = c
+			}
+			if step.Data != "" {
+				m, err := mapping.Compile(step.Data)
+				if err != nil {
+					return nil, errors.Wrapf(err, "invalid data for step %s of node %s", step.StepName, nodeName)
+				}
+				compiled.mappings[step.Data] = m
+			}
+		}
+	}
+	return compiled, nil
+}
+
+type compiledGraphContextKey struct{}
+
+// withCompiledGraph returns a context in which the steps use the conditions and data mappings compiled with the graph
+func withCompiledGraph(ctx context.Context, graph *compiledGraph) context.Context {
+	return context.WithValue(ctx, compiledGraphContextKey{}, graph)
+}
+
+func compiledGraphFromContext(ctx context.Context) *compiledGraph {
+	graph, _ := ctx.Value(compiledGraphContextKey{}).(*compiledGraph)
+	return graph
+}
+
+// getCondition returns the condition compiled with the graph, conditions of a graph which is not compiled are
+// compiled on every use
+func getCondition(ctx context.Context, expression string) (*condition.Condition, error) {
+	if graph := compiledGraphFromContext(ctx); graph != nil {
+		if c, ok := graph.conditions[expression]; ok {
+			return c, nil
+		}
+	}
+	return condition.Compile(expression)
+}
+
+// getMapping returns the data mapping compiled with the graph, mappings of a graph which is not compiled are
+// compiled on every use
+func getMapping(ctx context.Context, data string) (*mapping.Mapping, error) {
+	if graph := compiledGraphFromContext(ctx); graph != nil {
+		if m, ok := graph.mappings[data]; ok {
+			return m, nil
+		}
+	}
+	return mapping.Compile(data)
+}
+
+// buildStepRequest builds the request of the i-th step of a sequence node from the data of the step.
+// The first step has no previous response, `$response` refers to the request of the node.
+func buildStepRequest(ctx context.Context, node v1alpha1.InferenceRouter, step *v1alpha1.InferenceStep, i int, input []byte, previousResponse []byte,
+	stepResponses map[string][]byte) ([]byte, error) {
+	if i == 0 {
+		previousResponse = input
+	}
+	if isV2Node(node) && (step.Data == mapping.ResponseReference || strings.HasPrefix(step.Data, mapping.ResponseReference+".")) {
+		if i == 0 {
+			return input, nil
+		}
+		return v2ResponseToRequest(step.Data, previousResponse)
+	}
+	m, err := getMapping(ctx, step.Data)
+	if err != nil {
+		return nil, err
+	}
+	return m.Apply(&mapping.Scope{
+		Request:  input,
+		Response: previousResponse,
+		Steps:    stepResponses,
+	})
+}
+
+// matchCondition evaluates the step condition, conditions which fail to evaluate do not match
+func matchCondition(ctx context.Context, expression string, input []byte, headers http.Header) bool {
+	c, err := getCondition(ctx, expression)
+	if err == nil {
+		var matches bool
+		if matches, err = c.Matches(input, headers); err == nil {
+			return matches
+		}
+	}
+	log.Info("Condition could not be evaluated", "condition", expression, "error", err.Error())
+	return false
+}
+
 func timeTrack(start time.Time, nodeOrStep string, name string) {
 	elapsed := time.Since(start)
 	log.Info("elapsed time", nodeOrStep, name, "time", elapsed)
 }
 
-type EnsembleStepOutput struct {
-	StepResponse   map[string]interface{}
-	StepStatusCode int
-}
-
 // See if reviewer suggests a better name for this function
-func handleSplitterORSwitchNode(route *v1alpha1.InferenceStep, graph v1alpha1.InferenceGraphSpec, input []byte, headers http.Header) ([]byte, int, error) {
+func handleSplitterORSwitchNode(ctx context.Context, nodeName string, route *v1alpha1.InferenceStep, graph v1alpha1.InferenceGraphSpec, input []byte, headers http.Header) ([]byte, int, error) {
 	var statusCode int
 	var responseBytes []byte
 	var err error
@@ -114,115 +251,116 @@ func handleSplitterORSwitchNode(route *v1alpha1.InferenceStep, graph v1alpha1.In
 		stepType = "node"
 	}
 	log.Info("Starting execution of step", "type", stepType, "stepName", route.StepName)
-	if responseBytes, statusCode, err = executeStep(route, graph, input, headers); err != nil {
-		return nil, 500, err
+	ctx, attempts := withAttempts(ctx)
+	if responseBytes, statusCode, err = executeStep(ctx, route, graph, input, headers); err != nil {
+		return nil, statusCode, err
 	}
 
 	if route.Dependency == v1alpha1.Hard && !isSuccessFul(statusCode) {
 		log.Info("This step is a hard dependency and it is unsuccessful", "stepName", route.StepName, "statusCode", statusCode)
+		stepErr := newUpstreamStepError(nodeName, stepMetricsLabel(route), statusCode, responseBytes, *attempts)
+		return nil, stepErr.StatusCode, stepErr
 	}
 	return responseBytes, statusCode, nil
 }
 
-func routeStep(nodeName string, graph v1alpha1.InferenceGraphSpec, input []byte, headers http.Header) ([]byte, int, error) {
+func routeStep(ctx context.Context, nodeName string, graph v1alpha1.InferenceGraphSpec, input []byte, headers http.Header) (responseBytes []byte, statusCode int, err error) {
 	defer timeTrack(time.Now(), "node", nodeName)
+	start := time.Now()
 	currentNode := graph.Nodes[nodeName]
+	ctx, span := startNodeSpan(withNode(ctx, nodeName), nodeName, currentNode)
+	ctx = traceNodeStart(ctx, nodeName, currentNode)
+	defer func() {
+		endSpan(span, statusCode, err)
+		traceNodeEnd(ctx, statusCode, err)
+		if !dryRunFromContext(ctx) {
+			observeNode(start, nodeName, currentNode, statusCode)
+		}
+	}()
 
 	if currentNode.RouterType == v1alpha1.Splitter {
-		route := pickupRoute(currentNode.Steps)
-		return handleSplitterORSwitchNode(route, graph, input, headers)
+		route := pickupRoute(nodeName, currentNode, input, headers)
+		if route == nil {
+			return nil, 500, fmt.Errorf("splitter node %s did not pick a route, the step weights should sum to 100", nodeName)
+		}
+		if !dryRunFromContext(ctx) {
+			splitterRoutesTotal.WithLabelValues(graphName, nodeName, stepMetricsLabel(route)).Inc()
+		}
+		traceRoute(ctx, route, "")
+		return handleSplitterORSwitchNode(ctx, nodeName, route, graph, input, headers)
 	}
 	if currentNode.RouterType == v1alpha1.Switch {
 		var err error
-		route := pickupRouteByCondition(input, currentNode.Steps)
+		conditionInput := input
+		if isV2Node(currentNode) {
+			conditionInput = tensorView(input)
+		}
+		route := pickupRouteByCondition(ctx, conditionInput, headers, currentNode.Steps)
 		if route == nil {
 			errorMessage := "None of the routes matched with the switch condition"
-			err = errors.New(errorMessage)
+			err = &StepError{NodeName: nodeName, StatusCode: http.StatusUnprocessableEntity, Err: errors.New(errorMessage)}
 			log.Error(err, errorMessage)
-			return nil, 404, err
+			return nil, http.StatusUnprocessableEntity, err
 		}
-		return handleSplitterORSwitchNode(route, graph, input, headers)
+		traceRoute(ctx, route, route.Condition)
+		return handleSplitterORSwitchNode(ctx, nodeName, route, graph, input, headers)
 	}
 	if currentNode.RouterType == v1alpha1.Ensemble {
-		ensembleRes := make([]chan EnsembleStepOutput, len(currentNode.Steps))
-		errChan := make(chan error)
-		for i := range currentNode.Steps {
-			step := &currentNode.Steps[i]
-			stepType := "serviceUrl"
-			if step.NodeName != "" {
-				stepType = "node"
-			}
-			log.Info("Starting execution of step", "type", stepType, "stepName", step.StepName)
-			resultChan := make(chan EnsembleStepOutput)
-			ensembleRes[i] = resultChan
-			go func() {
-				output, statusCode, err := executeStep(step, graph, input, headers)
-				if err == nil {
-					var res map[string]interface{}
-					if err = json.Unmarshal(output, &res); err == nil {
-						resultChan <- EnsembleStepOutput{
-							StepResponse:   res,
-							StepStatusCode: statusCode,
-						}
-						return
-					}
-				}
-				errChan <- err
-			}()
-		}
-		// merge responses from parallel steps
-		response := map[string]interface{}{}
-		ensembleStepOutput := EnsembleStepOutput{}
-		for i, resultChan := range ensembleRes {
-			key := currentNode.Steps[i].StepName
-			if key == "" {
-				key = strconv.Itoa(i) // Use index if no step name
-			}
-			select {
-			case ensembleStepOutput = <-resultChan:
-				if !isSuccessFul(ensembleStepOutput.StepStatusCode) && currentNode.Steps[i].Dependency == v1alpha1.Hard {
-					log.Info("This step is a hard dependency and it is unsuccessful", "stepName", currentNode.Steps[i].StepName, "statusCode", ensembleStepOutput.StepStatusCode)
-					stepResponse, _ := json.Marshal(ensembleStepOutput.StepResponse) // TODO check if you need err handling for Marshalling
-					return stepResponse, ensembleStepOutput.StepStatusCode, nil      // First failed hard dependency will decide the response and response code for ensemble node
-				} else {
-					response[key] = ensembleStepOutput.StepResponse
-				}
-			case err := <-errChan:
-				return nil, 500, err
-			}
-		}
-		//return json.Marshal(response)
-		combinedResponse, _ := json.Marshal(response) // TODO check if you need err handling for Marshalling
-		return combinedResponse, 200, nil
+		return handleEnsembleNode(ctx, currentNode, graph, input, headers)
+	}
+	if currentNode.RouterType == v1alpha1.ForEach {
+		return handleForEachNode(ctx, nodeName, currentNode, graph, input, headers)
 	}
 	if currentNode.RouterType == v1alpha1.Sequence {
 		var statusCode int
 		var responseBytes []byte
 		var err error
+		stepResponses := map[string][]byte{}
 		for i := range currentNode.Steps {
 			step := &currentNode.Steps[i]
 			stepType := "serviceUrl"
 			if step.NodeName != "" {
 				stepType = "node"
 			}
+			if err := ctx.Err(); err != nil {
+				log.Info("Request is cancelled, stopping the sequence", "stepName", step.StepName, "error", err)
+				return nil, 500, err
+			}
 			log.Info("Starting execution of step", "type", stepType, "stepName", step.StepName)
 
 			request := input
-			if step.Data == "$response" && i > 0 {
-				request = responseBytes
+			if step.Data != "" {
+				if request, err = buildStepRequest(ctx, currentNode, step, i, input, responseBytes, stepResponses); err != nil {
+					return nil, 500, errors.Wrapf(err, "failed to build the request for step %s", step.StepName)
+				}
 			}
 
 			if step.Condition != "" {
 				if !gjson.ValidBytes(responseBytes) {
 					return nil, 500, fmt.Errorf("invalid response")
 				}
+				conditionInput := responseBytes
+				if isV2Node(currentNode) {
+					conditionInput = tensorView(responseBytes)
+				}
 				// if the condition does not match for the step in the sequence we stop and return the response
-				if !gjson.GetBytes(responseBytes, step.Condition).Exists() {
+				matched := matchCondition(ctx, step.Condition, conditionInput, headers)
+				traceCondition(ctx, step, matched)
+				if !matched {
 					return responseBytes, 500, nil
 				}
 			}
-			if responseBytes, statusCode, err = executeStep(step, graph, request, headers); err != nil {
-				return nil, 500, err
+			// only the response of the last step can be streamed to the client
+			stepCtx := ctx
+			if i < len(currentNode.Steps)-1 {
+				stepCtx = withoutStream(ctx)
+			}
+			stepCtx, attempts := withAttempts(stepCtx)
+			if responseBytes, statusCode, err = executeStep(stepCtx, step, graph, request, headers); err != nil {
+				return nil, statusCode, err
+			}
+			if step.StepName != "" {
+				stepResponses[step.StepName] = responseBytes
 			}
 			/*
 			   Only if a step is a hard dependency, we will check for its success.
@@ -231,7 +369,8 @@ func routeStep(nodeName string, graph v1alpha1.InferenceGraphSpec, input []byte,
 				if !isSuccessFul(statusCode) {
 					log.Info("This step is a hard dependency and it is unsuccessful", "stepName", step.StepName, "statusCode", statusCode)
 					// Stop the execution of sequence right away if step is a hard dependency and is unsuccessful
-					return responseBytes, statusCode, nil
+					stepErr := newUpstreamStepError(nodeName, stepMetricsLabel(step), statusCode, responseBytes, *attempts)
+					return nil, stepErr.StatusCode, stepErr
 				}
 			}
 		}
@@ -249,18 +388,59 @@ func isSuccessFul(statusCode int) bool {
 	return false
 }
 
-func executeStep(step *v1alpha1.InferenceStep, graph v1alpha1.InferenceGraphSpec, input []byte, headers http.Header) ([]byte, int, error) {
-	if step.NodeName != "" {
-		// when nodeName is specified make a recursive call for routing to next step
-		return routeStep(step.NodeName, graph, input, headers)
+func executeStep(ctx context.Context, step *v1alpha1.InferenceStep, graph v1alpha1.InferenceGraphSpec, input []byte, headers http.Header) (responseBytes []byte, statusCode int, err error) {
+	start := time.Now()
+	ctx, span := startStepSpan(ctx, step)
+	ctx = traceStepStart(ctx, step, input)
+	dryRun := dryRunFromContext(ctx)
+	defer func() {
+		endSpan(span, statusCode, err)
+		traceStepEnd(ctx, responseBytes, statusCode, err)
+		if !dryRun {
+			observeStep(ctx, start, step, statusCode, err)
+		}
+	}()
+	if dryRun && step.NodeName == "" && step.ServiceURL != "" {
+		// services are not called in a dry run, the step responds with its request
+		return input, http.StatusOK, nil
+	}
+	execute := func(ctx context.Context) ([]byte, int, error) {
+		if step.NodeName != "" {
+			if step.TimeoutSeconds != nil {
+				// the timeout of a step targeting a node bounds all the calls of the node
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, time.Duration(*step.TimeoutSeconds)*time.Second)
+				defer cancel()
+			}
+			// when nodeName is specified make a recursive call for routing to next step
+			return routeStep(withoutAttempts(ctx), step.NodeName, graph, input, headers)
+		}
+		return callStepService(ctx, step, input, headers)
+	}
+	if dryRun {
+		// a dry run neither mirrors the step to its shadow target nor reads or fills the response cache shared
+		// with the live requests
+		return execute(ctx)
+	}
+	mirrorStep(ctx, step, graph, input, headers)
+	if cache := getResponseCache(ctx, step); cache != nil {
+		return executeCachedStep(ctx, cache, step, input, headers, execute)
 	}
-	return callService(step.ServiceURL, input, headers)
+	return execute(ctx)
 }
 
 func prepareErrorResponse(err error, errorMessage string) []byte {
 	igRoutingErr := &InferenceGraphRoutingError{
-		errorMessage,
-		fmt.Sprintf("%v", err),
+		ErrorMessage: errorMessage,
+		Cause:        fmt.Sprintf("%v", err),
+	}
+	var stepErr *StepError
+	if errors.As(err, &stepErr) {
+		igRoutingErr.NodeName = stepErr.NodeName
+		igRoutingErr.StepName = stepErr.StepName
+		igRoutingErr.UpstreamStatusCode = stepErr.UpstreamStatusCode
+		igRoutingErr.UpstreamBody = bodyExcerpt(stepErr.UpstreamBody)
+		igRoutingErr.Attempts = stepErr.Attempts
 	}
 	errorResponseBytes, err := json.Marshal(igRoutingErr)
 	if err != nil {
@@ -269,11 +449,42 @@ func prepareErrorResponse(err error, errorMessage string) []byte {
 	return errorResponseBytes
 }
 
-var inferenceGraph *v1alpha1.InferenceGraphSpec
+// inferenceGraph is the current graph, it is swapped as a whole when the graph is reloaded
+var inferenceGraph atomic.Pointer[compiledGraph]
+
+// routeGraph routes a request through the root node of the current graph
+func routeGraph(ctx context.Context, input []byte, headers http.Header) ([]byte, int, error) {
+	graph := inferenceGraph.Load()
+	return routeStep(withCompiledGraph(ctx, graph), v1alpha1.GraphRootNodeName, *graph.spec, input, headers)
+}
 
 func graphHandler(w http.ResponseWriter, req *http.Request) {
-	inputBytes, _ := io.ReadAll(req.Body)
-	if response, statusCode, err := routeStep(v1alpha1.GraphRootNodeName, *inferenceGraph, inputBytes, req.Header); err != nil {
+	start := time.Now()
+	ctx, span := startGraphSpan(req.Context(), req.Header)
+	stream := &responseStream{w: w}
+	var response []byte
+	var statusCode int
+	inputBytes, err := readBody(http.MaxBytesReader(w, req.Body, *maxBodySize))
+	if err != nil {
+		statusCode = http.StatusBadRequest
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			statusCode = http.StatusRequestEntityTooLarge
+			err = &BodyTooLargeError{MaxBodySize: maxBytesErr.Limit}
+		}
+	} else {
+		response, statusCode, err = routeGraph(withStream(ctx, stream), inputBytes, req.Header)
+	}
+	endSpan(span, statusCode, err)
+	observeRequest(start, statusCode, err)
+	if stream.started {
+		// the status and the headers have been sent, a failure can only cut the response short
+		if err != nil {
+			log.Error(err, "failed to stream the response")
+		}
+		return
+	}
+	if err != nil {
 		log.Error(err, "failed to process request")
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(statusCode)
@@ -290,6 +501,9 @@ func graphHandler(w http.ResponseWriter, req *http.Request) {
 
 var (
 	jsonGraph          = flag.String("graph-json", "", "serialized json graph def")
+	graphConfigDir     = flag.String("graph-config-dir", "", "dir of the mounted ConfigMap with the graph spec, the graph is reloaded when it changes")
+	maxBodySize        = flag.Int64("max-body-size", defaultMaxBodySize, "max size in bytes of the request and of the step responses buffered by the router, streamed responses are not limited")
+	enableTrace        = flag.Bool("enable-trace", false, "serve the execution trace of a request on POST "+graphTracePath)
 	headersToPropagate []string
 )
 
@@ -300,18 +514,55 @@ func main() {
 		log.Info("These headers will be propagated by the router to all the steps.", "headersToPropagateEnvVar", headersToPropagateEnvVar)
 		headersToPropagate = strings.Split(headersToPropagateEnvVar, ",")
 	}
-	inferenceGraph = &v1alpha1.InferenceGraphSpec{}
-	err := json.Unmarshal([]byte(*jsonGraph), inferenceGraph)
+	graphName = os.Getenv(constants.RouterGraphNameEnvVar)
+	if *graphConfigDir != "" {
+		graphWatcher := NewGraphWatcher(*graphConfigDir)
+		if err := graphWatcher.Load(); err != nil {
+			log.Error(err, "failed to load the inference graph")
+			os.Exit(1)
+		}
+		go func() {
+			if err := graphWatcher.Start(make(chan struct{})); err != nil {
+				log.Error(err, "failed to watch the inference graph config dir")
+				os.Exit(1)
+			}
+		}()
+	} else {
+		graph := &v1alpha1.InferenceGraphSpec{}
+		if err := json.Unmarshal([]byte(*jsonGraph), graph); err != nil {
+			log.Error(err, "failed to unmarshall inference graph json")
+			os.Exit(1)
+		}
+		compiled, err := compileGraph(graph)
+		if err != nil {
+			log.Error(err, "failed to compile the inference graph")
+			os.Exit(1)
+		}
+		inferenceGraph.Store(compiled)
+	}
+	shutdownTracing, err := initTracing(context.Background())
 	if err != nil {
-		log.Error(err, "failed to unmarshall inference graph json")
+		log.Error(err, "failed to initialize tracing")
 		os.Exit(1)
 	}
 
 	http.HandleFunc("/", graphHandler)
+	if *enableTrace {
+		http.HandleFunc(graphTracePath, traceHandler)
+	}
+	http.Handle(constants.DefaultPrometheusPath, promhttp.Handler())
+
+	// the v2 gRPC inference protocol is served next to http on the same port over h2c
+	grpcServer := grpc.NewServer()
+	inference.RegisterGRPCInferenceServiceServer(grpcServer, &graphGRPCServer{})
+	handler := h2c.NewHandler(grpcHandlerFunc(grpcServer, http.DefaultServeMux), &http2.Server{})
 
-	err = http.ListenAndServe(":8080", nil)
+	err = http.ListenAndServe(":8080", handler)
 	if err != nil {
 		log.Error(err, "failed to listen on 8080")
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Error(err, "failed to flush the pending spans")
+		}
 		os.Exit(1)
 	}
 }
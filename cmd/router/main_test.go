@@ -17,23 +17,39 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
 	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
 	"io"
 	"knative.dev/pkg/apis"
 	"net/http"
 	"net/http/httptest"
+	"runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func init() {
 	logf.SetLogger(zap.New())
 }
 
+// storeGraph compiles the graph and makes it the current graph of the router
+func storeGraph(t *testing.T, spec *v1alpha1.InferenceGraphSpec) {
+	t.Helper()
+	graph, err := compileGraph(spec)
+	if err != nil {
+		t.Fatalf("Failed to compile the graph: %v", err)
+	}
+	inferenceGraph.Store(graph)
+}
+
 func TestSimpleModelChainer(t *testing.T) {
 	// Start a local HTTP server
 	model1 := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
@@ -98,7 +114,7 @@ func TestSimpleModelChainer(t *testing.T) {
 		"Authorization": {"Bearer Token"},
 	}
 
-	res, _, err := routeStep("root", graphSpec, jsonBytes, headers)
+	res, _, err := routeStep(context.Background(), "root", graphSpec, jsonBytes, headers)
 	var response map[string]interface{}
 	err = json.Unmarshal(res, &response)
 	expectedResponse := map[string]interface{}{
@@ -170,7 +186,7 @@ func TestSimpleModelEnsemble(t *testing.T) {
 	headers := http.Header{
 		"Authorization": {"Bearer Token"},
 	}
-	res, _, err := routeStep("root", graphSpec, jsonBytes, headers)
+	res, _, err := routeStep(context.Background(), "root", graphSpec, jsonBytes, headers)
 	var response map[string]interface{}
 	err = json.Unmarshal(res, &response)
 	expectedResponse := map[string]interface{}{
@@ -349,7 +365,7 @@ func TestInferenceGraphWithCondition(t *testing.T) {
 	headers := http.Header{
 		"Authorization": {"Bearer Token"},
 	}
-	res, _, err := routeStep("root", graphSpec, jsonBytes, headers)
+	res, _, err := routeStep(context.Background(), "root", graphSpec, jsonBytes, headers)
 	var response map[string]interface{}
 	err = json.Unmarshal(res, &response)
 	expectedModel3Response := map[string]interface{}{
@@ -413,7 +429,7 @@ func TestCallServiceWhenNoneHeadersToPropagateIsEmpty(t *testing.T) {
 	}
 	// Propagating no header
 	headersToPropagate = []string{}
-	res, _, err := callService(model1Url.String(), jsonBytes, headers)
+	res, _, err := callService(context.Background(), model1Url.String(), jsonBytes, headers)
 	var response map[string]interface{}
 	err = json.Unmarshal(res, &response)
 	expectedResponse := map[string]interface{}{
@@ -458,7 +474,7 @@ func TestCallServiceWhen1HeaderToPropagate(t *testing.T) {
 	}
 	// Propagating only 1 header "Test-Header-Key"
 	headersToPropagate = []string{"Test-Header-Key"}
-	res, _, err := callService(model1Url.String(), jsonBytes, headers)
+	res, _, err := callService(context.Background(), model1Url.String(), jsonBytes, headers)
 	var response map[string]interface{}
 	err = json.Unmarshal(res, &response)
 	expectedResponse := map[string]interface{}{
@@ -504,7 +520,7 @@ func TestCallServiceWhenMultipleHeadersToPropagate(t *testing.T) {
 	}
 	// Propagating multiple headers "Test-Header-Key"
 	headersToPropagate = []string{"Test-Header-Key", "Authorization"}
-	res, _, err := callService(model1Url.String(), jsonBytes, headers)
+	res, _, err := callService(context.Background(), model1Url.String(), jsonBytes, headers)
 	var response map[string]interface{}
 	err = json.Unmarshal(res, &response)
 	expectedResponse := map[string]interface{}{
@@ -515,3 +531,333 @@ func TestCallServiceWhenMultipleHeadersToPropagate(t *testing.T) {
 	fmt.Printf("final response:%v\n", response)
 	assert.Equal(t, expectedResponse, response)
 }
+
+func TestEnsembleCancelsSiblingStepsOnError(t *testing.T) {
+	siblingCancelled := make(chan struct{})
+	model1 := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = io.ReadAll(req.Body)
+		// invalid json fails the step and aborts the ensemble node which requires all steps to succeed
+		_, _ = rw.Write([]byte("not json"))
+	}))
+	defer model1.Close()
+	model2 := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = io.ReadAll(req.Body)
+		select {
+		case <-req.Context().Done():
+			close(siblingCancelled)
+		case <-time.After(10 * time.Second):
+		}
+	}))
+	defer model2.Close()
+
+	graphSpec := v1alpha1.InferenceGraphSpec{
+		Nodes: map[string]v1alpha1.InferenceRouter{
+			"root": {
+				RouterType:    v1alpha1.Ensemble,
+				FailurePolicy: &v1alpha1.EnsembleFailurePolicy{Type: v1alpha1.All},
+				Steps: []v1alpha1.InferenceStep{
+					{
+						StepName: "model1",
+						InferenceTarget: v1alpha1.InferenceTarget{
+							ServiceURL: model1.URL,
+						},
+					},
+					{
+						StepName: "model2",
+						InferenceTarget: v1alpha1.InferenceTarget{
+							ServiceURL: model2.URL,
+						},
+					},
+				},
+			},
+		},
+	}
+	goroutinesBefore := runtime.NumGoroutine()
+	start := time.Now()
+	res, statusCode, err := routeStep(context.Background(), "root", graphSpec, []byte(`{"instances": []}`), http.Header{})
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusInternalServerError, statusCode)
+	assert.True(t, gjson.GetBytes(res, "errors.model1").Exists())
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	select {
+	case <-siblingCancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("sibling ensemble step was not cancelled")
+	}
+	assertNoGoroutineLeak(t, goroutinesBefore)
+}
+
+func TestGraphHandlerCancelsStepsWhenClientDisconnects(t *testing.T) {
+	stepCancelled := make(chan struct{})
+	var calls int32
+	model1 := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		_, _ = io.ReadAll(req.Body)
+		select {
+		case <-req.Context().Done():
+			close(stepCancelled)
+		case <-time.After(10 * time.Second):
+		}
+	}))
+	defer model1.Close()
+	model2 := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		_, _ = rw.Write([]byte(`{"predictions": "2"}`))
+	}))
+	defer model2.Close()
+
+	storeGraph(t, &v1alpha1.InferenceGraphSpec{
+		Nodes: map[string]v1alpha1.InferenceRouter{
+			"root": {
+				RouterType: v1alpha1.Sequence,
+				Steps: []v1alpha1.InferenceStep{
+					{
+						StepName: "model1",
+						InferenceTarget: v1alpha1.InferenceTarget{
+							ServiceURL: model1.URL,
+						},
+					},
+					{
+						StepName: "model2",
+						InferenceTarget: v1alpha1.InferenceTarget{
+							ServiceURL: model2.URL,
+						},
+						Data: "$response",
+					},
+				},
+			},
+		},
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest("POST", "/", strings.NewReader(`{"instances": []}`)).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+	}()
+	start := time.Now()
+	graphHandler(rec, req)
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	select {
+	case <-stepCancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("in-flight step was not cancelled")
+	}
+	// the sequence stops after the cancelled step
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestRouteStepWithCancelledContext(t *testing.T) {
+	var calls int32
+	model1 := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		_, _ = rw.Write([]byte(`{"predictions": "1"}`))
+	}))
+	defer model1.Close()
+
+	graphSpec := v1alpha1.InferenceGraphSpec{
+		Nodes: map[string]v1alpha1.InferenceRouter{
+			"root": {
+				RouterType: v1alpha1.Sequence,
+				Steps: []v1alpha1.InferenceStep{
+					{
+						StepName: "model1",
+						InferenceTarget: v1alpha1.InferenceTarget{
+							ServiceURL: model1.URL,
+						},
+					},
+				},
+			},
+		},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err := routeStep(ctx, "root", graphSpec, []byte(`{"instances": []}`), http.Header{})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls))
+}
+
+func TestInferenceGraphWithConditionExpressions(t *testing.T) {
+	gold := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = io.ReadAll(req.Body)
+		_, _ = rw.Write([]byte(`{"predictions": ["gold"], "score": 0.9}`))
+	}))
+	defer gold.Close()
+	standard := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = io.ReadAll(req.Body)
+		_, _ = rw.Write([]byte(`{"predictions": ["standard"], "score": 0.2}`))
+	}))
+	defer standard.Close()
+	explainer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = io.ReadAll(req.Body)
+		_, _ = rw.Write([]byte(`{"explanation": "high score"}`))
+	}))
+	defer explainer.Close()
+
+	graphSpec := v1alpha1.InferenceGraphSpec{
+		Nodes: map[string]v1alpha1.InferenceRouter{
+			"root": {
+				RouterType: v1alpha1.Sequence,
+				Steps: []v1alpha1.InferenceStep{
+					{
+						StepName: "tier",
+						InferenceTarget: v1alpha1.InferenceTarget{
+							NodeName: "tier",
+						},
+					},
+					{
+						StepName: "explainer",
+						InferenceTarget: v1alpha1.InferenceTarget{
+							ServiceURL: explainer.URL,
+						},
+						Data:      "$response",
+						Condition: `body.score >= 0.5`,
+					},
+				},
+			},
+			"tier": {
+				RouterType: v1alpha1.Switch,
+				Steps: []v1alpha1.InferenceStep{
+					{
+						StepName: "gold",
+						InferenceTarget: v1alpha1.InferenceTarget{
+							ServiceURL: gold.URL,
+						},
+						Condition: `headers["x-tenant"] == "gold" || body.instances[0].amount > 1000`,
+					},
+					{
+						StepName: "standard",
+						InferenceTarget: v1alpha1.InferenceTarget{
+							ServiceURL: standard.URL,
+						},
+						Condition: `body.instances[0].amount <= 1000`,
+					},
+				},
+			},
+		},
+	}
+	_, err := compileGraph(&graphSpec)
+	assert.Nil(t, err)
+
+	res, statusCode, err := routeStep(context.Background(), "root", graphSpec, []byte(`{"instances": [{"amount": 10}]}`),
+		http.Header{"X-Tenant": {"gold"}})
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.JSONEq(t, `{"explanation": "high score"}`, string(res))
+
+	// the standard tier has a low score so the sequence stops before the explainer
+	res, _, err = routeStep(context.Background(), "root", graphSpec, []byte(`{"instances": [{"amount": 10}]}`), http.Header{})
+	assert.Nil(t, err)
+	assert.Equal(t, "standard", gjson.GetBytes(res, "predictions.0").String())
+
+	// a condition failing to evaluate does not match
+	_, statusCode, err = routeStep(context.Background(), "tier", graphSpec, []byte(`{"instances": []}`), http.Header{})
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, statusCode)
+
+	graphSpec.Nodes["tier"].Steps[0].Condition = `header["x-tenant"] == "gold"`
+	_, err = compileGraph(&graphSpec)
+	assert.NotNil(t, err)
+}
+
+func TestCompiledGraph(t *testing.T) {
+	step := v1alpha1.InferenceStep{
+		InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: "http://model"},
+		Condition:       `body.instances[0] == 1`,
+		Data:            `{"instances": "$request.instances"}`,
+	}
+	graph, err := compileGraph(&v1alpha1.InferenceGraphSpec{
+		Nodes: map[string]v1alpha1.InferenceRouter{
+			"root": {RouterType: v1alpha1.Switch, Steps: []v1alpha1.InferenceStep{step}},
+		},
+	})
+	assert.Nil(t, err)
+	ctx := withCompiledGraph(context.Background(), graph)
+	c, err := getCondition(ctx, step.Condition)
+	assert.Nil(t, err)
+	assert.Same(t, graph.conditions[step.Condition], c)
+	m, err := getMapping(ctx, step.Data)
+	assert.Nil(t, err)
+	assert.Same(t, graph.mappings[step.Data], m)
+
+	// expressions which are not part of the graph are compiled without being kept
+	_, err = getCondition(ctx, `body.instances[0] == 2`)
+	assert.Nil(t, err)
+	assert.Len(t, graph.conditions, 1)
+}
+
+func TestSequenceWithDataMappings(t *testing.T) {
+	preprocess := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = io.ReadAll(req.Body)
+		_, _ = rw.Write([]byte(`{"predictions": [[0.5, 1.5]]}`))
+	}))
+	defer preprocess.Close()
+	var modelRequest []byte
+	model := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		modelRequest, _ = io.ReadAll(req.Body)
+		_, _ = rw.Write([]byte(`{"predictions": [1]}`))
+	}))
+	defer model.Close()
+	var postprocessRequest []byte
+	postprocess := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		postprocessRequest, _ = io.ReadAll(req.Body)
+		_, _ = rw.Write([]byte(`{"label": "fraud"}`))
+	}))
+	defer postprocess.Close()
+
+	graphSpec := v1alpha1.InferenceGraphSpec{
+		Nodes: map[string]v1alpha1.InferenceRouter{
+			"root": {
+				RouterType: v1alpha1.Sequence,
+				Steps: []v1alpha1.InferenceStep{
+					{
+						StepName: "preprocess",
+						InferenceTarget: v1alpha1.InferenceTarget{
+							ServiceURL: preprocess.URL,
+						},
+						Data: "$request.raw",
+					},
+					{
+						StepName: "model",
+						InferenceTarget: v1alpha1.InferenceTarget{
+							ServiceURL: model.URL,
+						},
+						Data: `{"instances": "$response.predictions"}`,
+					},
+					{
+						StepName: "postprocess",
+						InferenceTarget: v1alpha1.InferenceTarget{
+							ServiceURL: postprocess.URL,
+						},
+						Data: `{"id": "$request.id", "features": "$steps.preprocess.predictions", "score": "$response.predictions.0", "threshold": 0.5}`,
+					},
+				},
+			},
+		},
+	}
+	_, err := compileGraph(&graphSpec)
+	assert.Nil(t, err)
+	res, statusCode, err := routeStep(context.Background(), "root", graphSpec, []byte(`{"id": "tx-1", "raw": {"amount": 10}}`), http.Header{})
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.JSONEq(t, `{"label": "fraud"}`, string(res))
+	assert.JSONEq(t, `{"instances": [[0.5, 1.5]]}`, string(modelRequest))
+	assert.JSONEq(t, `{"id": "tx-1", "features": [[0.5, 1.5]], "score": 1, "threshold": 0.5}`, string(postprocessRequest))
+
+	// a reference which can not be resolved fails the sequence
+	_, statusCode, err = routeStep(context.Background(), "root", graphSpec, []byte(`{"id": "tx-1"}`), http.Header{})
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusInternalServerError, statusCode)
+}
+
+// assertNoGoroutineLeak waits for the goroutines started by a test to exit
+func assertNoGoroutineLeak(t *testing.T, before int) {
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		http.DefaultClient.CloseIdleConnections()
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), before)
+}
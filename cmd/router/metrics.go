@@ -0,0 +1,188 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	metricsNamespace = "inferencegraph"
+
+	graphLabel      = "graph"
+	nodeLabel       = "node"
+	routerTypeLabel = "router_type"
+	stepLabel       = "step"
+	statusCodeLabel = "status_code"
+)
+
+var (
+	requestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "requests_total",
+		Help:      "Number of requests handled by the inference graph.",
+	}, []string{graphLabel, statusCodeLabel})
+	requestErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "request_errors_total",
+		Help:      "Number of requests the inference graph failed with an error or a 5xx status code.",
+	}, []string{graphLabel, statusCodeLabel})
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "request_duration_seconds",
+		Help:      "Latency of the requests handled by the inference graph.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{graphLabel, statusCodeLabel})
+	nodeDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "node_duration_seconds",
+		Help:      "Latency of the inference graph nodes.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{graphLabel, nodeLabel, routerTypeLabel, statusCodeLabel})
+	stepRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "step_requests_total",
+		Help:      "Number of executed inference graph steps.",
+	}, []string{graphLabel, nodeLabel, stepLabel, statusCodeLabel})
+	stepErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "step_errors_total",
+		Help:      "Number of inference graph steps which failed with an error or a 5xx status code.",
+	}, []string{graphLabel, nodeLabel, stepLabel, statusCodeLabel})
+	stepDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "step_duration_seconds",
+		Help:      "Latency of the inference graph steps.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{graphLabel, nodeLabel, stepLabel, statusCodeLabel})
+	ensembleInflightSteps = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "ensemble_inflight_steps",
+		Help:      "Number of ensemble steps and forEach elements currently in flight.",
+	}, []string{graphLabel, nodeLabel})
+	splitterRoutesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "splitter_routes_total",
+		Help:      "Number of times a splitter route was chosen.",
+	}, []string{graphLabel, nodeLabel, stepLabel})
+	stepCacheHitsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "step_cache_hits_total",
+		Help:      "Number of step responses served from the step cache.",
+	}, []string{graphLabel, nodeLabel, stepLabel})
+	stepCacheMissesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "step_cache_misses_total",
+		Help:      "Number of cached steps whose response was not found in the step cache.",
+	}, []string{graphLabel, nodeLabel, stepLabel})
+	mirrorRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "mirror_requests_total",
+		Help:      "Number of step requests mirrored to a shadow target.",
+	}, []string{graphLabel, nodeLabel, stepLabel, statusCodeLabel})
+	mirrorErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "mirror_errors_total",
+		Help:      "Number of mirrored step requests which failed with an error or a 5xx status code.",
+	}, []string{graphLabel, nodeLabel, stepLabel, statusCodeLabel})
+	mirrorDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "mirror_duration_seconds",
+		Help:      "Latency of the shadow targets of the mirrored steps.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{graphLabel, nodeLabel, stepLabel, statusCodeLabel})
+)
+
+func init() {
+	prometheus.MustRegister(
+		requestsTotal,
+		requestErrorsTotal,
+		requestDuration,
+		nodeDuration,
+		stepRequestsTotal,
+		stepErrorsTotal,
+		stepDuration,
+		ensembleInflightSteps,
+		splitterRoutesTotal,
+		stepCacheHitsTotal,
+		stepCacheMissesTotal,
+		mirrorRequestsTotal,
+		mirrorErrorsTotal,
+		mirrorDuration,
+	)
+}
+
+// graphName labels the metrics of the router, knative sets K_SERVICE to the name of the graph
+var graphName = ""
+
+type nodeContextKey struct{}
+
+// withNode records the node being routed in the context so that the metrics of its steps are labeled with it
+func withNode(ctx context.Context, nodeName string) context.Context {
+	return context.WithValue(ctx, nodeContextKey{}, nodeName)
+}
+
+func nodeFromContext(ctx context.Context) string {
+	nodeName, _ := ctx.Value(nodeContextKey{}).(string)
+	return nodeName
+}
+
+// stepMetricsLabel identifies a step in the metrics, steps without a name are identified by their target
+func stepMetricsLabel(step *v1alpha1.InferenceStep) string {
+	if step.StepName != "" {
+		return step.StepName
+	}
+	if step.NodeName != "" {
+		return step.NodeName
+	}
+	if step.ServiceURL == "" {
+		return step.ServiceName
+	}
+	return step.ServiceURL
+}
+
+func isErrorResponse(statusCode int, err error) bool {
+	return err != nil || statusCode >= 500
+}
+
+func observeRequest(start time.Time, statusCode int, err error) {
+	code := strconv.Itoa(statusCode)
+	requestsTotal.WithLabelValues(graphName, code).Inc()
+	requestDuration.WithLabelValues(graphName, code).Observe(time.Since(start).Seconds())
+	if isErrorResponse(statusCode, err) {
+		requestErrorsTotal.WithLabelValues(graphName, code).Inc()
+	}
+}
+
+func observeNode(start time.Time, nodeName string, node v1alpha1.InferenceRouter, statusCode int) {
+	nodeDuration.WithLabelValues(graphName, nodeName, string(node.RouterType), strconv.Itoa(statusCode)).
+		Observe(time.Since(start).Seconds())
+}
+
+func observeStep(ctx context.Context, start time.Time, step *v1alpha1.InferenceStep, statusCode int, err error) {
+	nodeName, stepName, code := nodeFromContext(ctx), stepMetricsLabel(step), strconv.Itoa(statusCode)
+	stepRequestsTotal.WithLabelValues(graphName, nodeName, stepName, code).Inc()
+	stepDuration.WithLabelValues(graphName, nodeName, stepName, code).Observe(time.Since(start).Seconds())
+	if isErrorResponse(statusCode, err) {
+		stepErrorsTotal.WithLabelValues(graphName, nodeName, stepName, code).Inc()
+	}
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphMetrics(t *testing.T) {
+	model1 := newPredictionServer(t, http.StatusOK, `[1]`)
+	failing := newPredictionServer(t, http.StatusServiceUnavailable, `[]`)
+
+	// the metrics are labeled with nodes and steps of this test only, the request counters are compared with their
+	// value before the test
+	requests := testutil.ToFloat64(requestsTotal.WithLabelValues(graphName, "200"))
+	storeGraph(t, &v1alpha1.InferenceGraphSpec{
+		Nodes: map[string]v1alpha1.InferenceRouter{
+			"root": {
+				RouterType: v1alpha1.Splitter,
+				Steps: []v1alpha1.InferenceStep{
+					{
+						StepName:        "metrics-ensemble",
+						InferenceTarget: v1alpha1.InferenceTarget{NodeName: "metrics-ensemble"},
+						Weight:          proto.Int64(100),
+					},
+				},
+			},
+			"metrics-ensemble": {
+				RouterType: v1alpha1.Ensemble,
+				Steps: []v1alpha1.InferenceStep{
+					{StepName: "metrics-model", InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: model1.URL}},
+					{StepName: "metrics-failing", InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: failing.URL}},
+				},
+			},
+		},
+	})
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(`{"instances": [1]}`))
+		rec := httptest.NewRecorder()
+		graphHandler(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+
+	assert.Equal(t, requests+2, testutil.ToFloat64(requestsTotal.WithLabelValues(graphName, "200")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(requestErrorsTotal.WithLabelValues(graphName, "200")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(splitterRoutesTotal.WithLabelValues(graphName, "root", "metrics-ensemble")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(stepRequestsTotal.WithLabelValues(graphName, "root", "metrics-ensemble", "200")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(stepRequestsTotal.WithLabelValues(graphName, "metrics-ensemble", "metrics-model", "200")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(stepErrorsTotal.WithLabelValues(graphName, "metrics-ensemble", "metrics-failing", "503")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(ensembleInflightSteps.WithLabelValues(graphName, "metrics-ensemble")))
+
+	rec := httptest.NewRecorder()
+	promhttp.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	assert.Contains(t, string(body), `inferencegraph_node_duration_seconds_count{graph="",node="metrics-ensemble",router_type="Ensemble",status_code="200"} 2`)
+	assert.Contains(t, string(body), `inferencegraph_step_duration_seconds_count{graph="",node="metrics-ensemble",status_code="503",step="metrics-failing"} 2`)
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// defaultMirrorTimeout bounds the mirrored calls of steps without a timeout, the client does not wait for them
+const defaultMirrorTimeout = 60 * time.Second
+
+// mirrorStep sends a copy of the step request to the shadow target of the step. The shadow call runs detached from
+// the request so it is neither cancelled with the request nor waited for, its outcome is only logged and recorded.
+func mirrorStep(ctx context.Context, step *v1alpha1.InferenceStep, graph v1alpha1.InferenceGraphSpec, input []byte, headers http.Header) {
+	mirror := step.Mirror
+	if mirror == nil {
+		return
+	}
+	if mirror.NodeName == "" && mirror.ServiceURL == "" {
+		log.Info("Mirror target is not resolved, not mirroring the step", "stepName", step.StepName, "serviceName", mirror.ServiceName)
+		return
+	}
+	percent := int64(100)
+	if mirror.Percent != nil {
+		percent = *mirror.Percent
+	}
+	if int64(rand.Intn(100)) >= percent {
+		return
+	}
+	nodeName, stepName := nodeFromContext(ctx), stepMetricsLabel(step)
+	shadowStep := &v1alpha1.InferenceStep{
+		StepName:        stepName + "-mirror",
+		InferenceTarget: mirror.InferenceTarget,
+		TimeoutSeconds:  step.TimeoutSeconds,
+		ProtocolVersion: step.ProtocolVersion,
+		// the credentials of the step are meant for the step service only
+		Auth: mirror.Auth,
+	}
+	timeout := defaultMirrorTimeout
+	if step.TimeoutSeconds != nil {
+		timeout = time.Duration(*step.TimeoutSeconds) * time.Second
+	}
+	// the shadow spans belong to the trace of the request
+	mirrorCtx := trace.ContextWithSpanContext(withNode(context.Background(), nodeName), trace.SpanContextFromContext(ctx))
+	headers = headers.Clone()
+	if mirror.Auth == nil || mirror.Auth.Type != v1alpha1.ForwardToken {
+		// the token of the caller is not propagated to the shadow target either
+		headers.Del(authorizationHeader)
+	}
+	go func() {
+		start := time.Now()
+		var statusCode int
+		var err error
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("mirrored call panicked: %v", r)
+			}
+			observeMirror(start, nodeName, stepName, statusCode, err)
+			log.Info("Mirrored step completed", "stepName", step.StepName, "statusCode", statusCode,
+				"time", time.Since(start), "error", err)
+		}()
+		callCtx, cancel := context.WithTimeout(mirrorCtx, timeout)
+		defer cancel()
+		_, statusCode, err = executeStep(callCtx, shadowStep, graph, input, headers)
+	}()
+}
+
+func observeMirror(start time.Time, nodeName string, stepName string, statusCode int, err error) {
+	code := strconv.Itoa(statusCode)
+	mirrorRequestsTotal.WithLabelValues(graphName, nodeName, stepName, code).Inc()
+	mirrorDuration.WithLabelValues(graphName, nodeName, stepName, code).Observe(time.Since(start).Seconds())
+	if isErrorResponse(statusCode, err) {
+		mirrorErrorsTotal.WithLabelValues(graphName, nodeName, stepName, code).Inc()
+	}
+}
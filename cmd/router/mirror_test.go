@@ -0,0 +1,150 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMirrorStep(t *testing.T) {
+	model := newPredictionServer(t, http.StatusOK, `[1]`)
+	release := make(chan struct{})
+	var mirrored int32
+	var mirroredBody atomic.Value
+	shadow := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		mirroredBody.Store(string(body))
+		<-release
+		atomic.AddInt32(&mirrored, 1)
+		rw.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer shadow.Close()
+
+	storeGraph(t, &v1alpha1.InferenceGraphSpec{
+		Nodes: map[string]v1alpha1.InferenceRouter{
+			"root": {
+				RouterType: v1alpha1.Sequence,
+				Steps: []v1alpha1.InferenceStep{
+					{
+						StepName:        "mirrored-model",
+						InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: model.URL},
+						Mirror: &v1alpha1.StepMirror{
+							InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: shadow.URL},
+						},
+					},
+				},
+			},
+		},
+	})
+	rec := httptest.NewRecorder()
+	graphHandler(rec, httptest.NewRequest("POST", "/", strings.NewReader(`{"instances": [1]}`)))
+	// the response does not wait for the shadow target and is not affected by its failure
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `{"predictions": [1]}`, rec.Body.String())
+	assert.Equal(t, int32(0), atomic.LoadInt32(&mirrored))
+
+	close(release)
+	assert.Eventually(t, func() bool {
+		return testutil.ToFloat64(mirrorErrorsTotal.WithLabelValues(graphName, "root", "mirrored-model", "500")) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 1.0, testutil.ToFloat64(mirrorRequestsTotal.WithLabelValues(graphName, "root", "mirrored-model", "500")))
+	assert.Equal(t, `{"instances": [1]}`, mirroredBody.Load())
+
+	// requests are not mirrored outside of the sampling percentage
+	inferenceGraph.Load().spec.Nodes["root"].Steps[0].Mirror.Percent = proto.Int64(0)
+	for i := 0; i < 10; i++ {
+		rec = httptest.NewRecorder()
+		graphHandler(rec, httptest.NewRequest("POST", "/", strings.NewReader(`{"instances": [1]}`)))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&mirrored))
+}
+
+func TestMirrorStepAuth(t *testing.T) {
+	modelAuth := make(chan string, 2)
+	model := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		modelAuth <- req.Header.Get("Authorization")
+		_, _ = rw.Write([]byte(`{"predictions": [1]}`))
+	}))
+	defer model.Close()
+	shadowAuth := make(chan string, 2)
+	shadow := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		shadowAuth <- req.Header.Get("Authorization")
+	}))
+	defer shadow.Close()
+
+	graph := v1alpha1.InferenceGraphSpec{
+		Nodes: map[string]v1alpha1.InferenceRouter{
+			"root": {
+				RouterType: v1alpha1.Sequence,
+				Steps: []v1alpha1.InferenceStep{
+					{
+						StepName:        "authenticated-model",
+						InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: model.URL},
+						Auth:            &v1alpha1.StepAuth{Type: v1alpha1.ForwardToken},
+						Mirror: &v1alpha1.StepMirror{
+							InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: shadow.URL},
+						},
+					},
+				},
+			},
+		},
+	}
+	send := func() {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(`{"instances": [1]}`))
+		req.Header.Set("Authorization", "Bearer caller-token")
+		rec := httptest.NewRecorder()
+		graphHandler(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+	receive := func(auth chan string) string {
+		select {
+		case value := <-auth:
+			return value
+		case <-time.After(5 * time.Second):
+			t.Fatal("the service was not called")
+			return ""
+		}
+	}
+
+	// the token of the caller is only forwarded to the step service, even when it is a propagated header
+	defer func(headers []string) { headersToPropagate = headers }(headersToPropagate)
+	headersToPropagate = []string{"Authorization"}
+	storeGraph(t, &graph)
+	send()
+	assert.Equal(t, "Bearer caller-token", receive(modelAuth))
+	assert.Equal(t, "", receive(shadowAuth))
+
+	// the shadow service gets the credentials of the mirror auth
+	graph.Nodes["root"].Steps[0].Mirror.Auth = &v1alpha1.StepAuth{Type: v1alpha1.ForwardToken}
+	storeGraph(t, &graph)
+	send()
+	assert.Equal(t, "Bearer caller-token", receive(modelAuth))
+	assert.Equal(t, "Bearer caller-token", receive(shadowAuth))
+}
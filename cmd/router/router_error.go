@@ -1,12 +1,140 @@
 package main
 
-import "fmt"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"unicode/utf8"
+)
+
+// upstreamBodyExcerptSize is the max number of bytes of a failed step response reported in the error response
+const upstreamBodyExcerptSize = 512
 
 type InferenceGraphRoutingError struct {
 	ErrorMessage string `json:"error"`
 	Cause        string `json:"cause"`
+	// NodeName and StepName identify the step which failed the request
+	NodeName string `json:"node,omitempty"`
+	StepName string `json:"step,omitempty"`
+	// UpstreamStatusCode and UpstreamBody are the status code and an excerpt of the body of the failed step response
+	UpstreamStatusCode int    `json:"upstreamStatus,omitempty"`
+	UpstreamBody       string `json:"upstreamBody,omitempty"`
+	// Attempts is the number of calls made to the step service including the retries
+	Attempts int `json:"attempts,omitempty"`
 }
 
 func (e *InferenceGraphRoutingError) Error() string {
 	return fmt.Sprintf("%s. %s", e.ErrorMessage, e.Cause)
 }
+
+// CircuitBreakerOpenError is returned when a step service is not called because its circuit breaker is open
+type CircuitBreakerOpenError struct {
+	ServiceURL string
+}
+
+func (e *CircuitBreakerOpenError) Error() string {
+	return fmt.Sprintf("circuit breaker is open for service %s", e.ServiceURL)
+}
+
+// ServiceNotReadyError is returned for steps calling an InferenceService which is not ready, the controller does not
+// resolve the url of the InferenceService until it has an address
+type ServiceNotReadyError struct {
+	ServiceName string
+}
+
+func (e *ServiceNotReadyError) Error() string {
+	return fmt.Sprintf("InferenceService %s is not ready", e.ServiceName)
+}
+
+// StepError reports the node and the step of the graph which failed the request. Steps fail when their service
+// can not be called or when a Hard dependency responds with an error, the error of the innermost step is reported.
+type StepError struct {
+	NodeName string
+	StepName string
+	// StatusCode is the status code of the router response
+	StatusCode int
+	// UpstreamStatusCode is the status code of the failed step response, 0 when the step did not respond
+	UpstreamStatusCode int
+	UpstreamBody       []byte
+	Attempts           int
+	Err                error
+}
+
+func (e *StepError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("step %s of node %s failed: %v", e.StepName, e.NodeName, e.Err)
+	}
+	return fmt.Sprintf("step %s of node %s failed with status %d", e.StepName, e.NodeName, e.UpstreamStatusCode)
+}
+
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// newStepError reports a step whose call failed with an error
+func newStepError(ctx context.Context, stepName string, statusCode int, attempts int, err error) *StepError {
+	var stepErr *StepError
+	if errors.As(err, &stepErr) {
+		return stepErr
+	}
+	return &StepError{
+		NodeName:   nodeFromContext(ctx),
+		StepName:   stepName,
+		StatusCode: callErrorStatusCode(statusCode, err),
+		Attempts:   attempts,
+		Err:        err,
+	}
+}
+
+// newUpstreamStepError reports a step which responded with an error
+func newUpstreamStepError(nodeName string, stepName string, statusCode int, body []byte, attempts int) *StepError {
+	return &StepError{
+		NodeName:           nodeName,
+		StepName:           stepName,
+		StatusCode:         upstreamStatusCode(statusCode),
+		UpstreamStatusCode: statusCode,
+		UpstreamBody:       body,
+		Attempts:           attempts,
+	}
+}
+
+// callErrorStatusCode maps the errors of a step call, timeouts are 504 and services which can not be reached 502
+func callErrorStatusCode(statusCode int, err error) int {
+	var urlErr *url.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.As(err, &urlErr):
+		if urlErr.Timeout() {
+			return http.StatusGatewayTimeout
+		}
+		return http.StatusBadGateway
+	}
+	return statusCode
+}
+
+// upstreamStatusCode maps the status of a failed step response, client errors are passed through and server
+// errors are 502 except timeouts which are 504
+func upstreamStatusCode(statusCode int) int {
+	switch {
+	case statusCode == http.StatusGatewayTimeout || statusCode == http.StatusRequestTimeout:
+		return http.StatusGatewayTimeout
+	case statusCode >= 400 && statusCode < 500:
+		return statusCode
+	}
+	return http.StatusBadGateway
+}
+
+// bodyExcerpt truncates the body to the excerpt size without splitting a utf8 character
+func bodyExcerpt(body []byte) string {
+	if len(body) <= upstreamBodyExcerptSize {
+		return string(body)
+	}
+	cut := upstreamBodyExcerptSize
+	for cut > 0 && !utf8.RuneStart(body[cut]) {
+		cut--
+	}
+	return string(body[:cut]) + "..."
+}
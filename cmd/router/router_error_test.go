@@ -0,0 +1,172 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestStepErrorResponses(t *testing.T) {
+	model := newPredictionServer(t, http.StatusOK, `[1]`)
+	failing := newPredictionServer(t, http.StatusServiceUnavailable, `"model is loading"`)
+	slow := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = io.ReadAll(req.Body)
+		select {
+		case <-req.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer slow.Close()
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+	// the flaky service responds with a retryable status code once and then with a status code which is not retried
+	var flakyCalls int32
+	flaky := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if atomic.AddInt32(&flakyCalls, 1) == 1 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		rw.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer flaky.Close()
+
+	graph := func(step v1alpha1.InferenceStep) *v1alpha1.InferenceGraphSpec {
+		return &v1alpha1.InferenceGraphSpec{
+			Nodes: map[string]v1alpha1.InferenceRouter{
+				"root": {
+					RouterType: v1alpha1.Sequence,
+					Steps: []v1alpha1.InferenceStep{
+						{StepName: "preprocess", InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: model.URL}},
+						{StepName: "models", InferenceTarget: v1alpha1.InferenceTarget{NodeName: "models"}},
+						{StepName: "postprocess", InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: model.URL}},
+					},
+				},
+				"models": {
+					RouterType: v1alpha1.Sequence,
+					Steps:      []v1alpha1.InferenceStep{{StepName: "model1", InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: model.URL}}, step},
+				},
+			},
+		}
+	}
+	scenarios := map[string]struct {
+		step               v1alpha1.InferenceStep
+		expectedStatusCode int
+		expectedAttempts   int64
+		expectedError      string
+	}{
+		"hard dependency fails": {
+			step: v1alpha1.InferenceStep{
+				StepName:        "model2",
+				InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: failing.URL},
+				Dependency:      v1alpha1.Hard,
+				RetryPolicy:     &v1alpha1.StepRetryPolicy{MaxAttempts: proto.Int32(2), Backoff: proto.Int64(1)},
+			},
+			expectedStatusCode: http.StatusBadGateway,
+			expectedAttempts:   2,
+			expectedError: `{"error": "Failed to process request", "cause": "step model2 of node models failed with status 503",
+				"node": "models", "step": "model2", "upstreamStatus": 503, "upstreamBody": "{\"predictions\": \"model is loading\"}",
+				"attempts": 2}`,
+		},
+		"hard dependency fails after a retry": {
+			step: v1alpha1.InferenceStep{
+				StepName:        "model2",
+				InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: flaky.URL},
+				Dependency:      v1alpha1.Hard,
+				RetryPolicy:     &v1alpha1.StepRetryPolicy{MaxAttempts: proto.Int32(3), Backoff: proto.Int64(1)},
+			},
+			expectedStatusCode: http.StatusBadGateway,
+			expectedAttempts:   2,
+		},
+		"step times out": {
+			step: v1alpha1.InferenceStep{
+				StepName:        "model2",
+				InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: slow.URL},
+				TimeoutSeconds:  proto.Int64(1),
+			},
+			expectedStatusCode: http.StatusGatewayTimeout,
+			expectedAttempts:   1,
+		},
+		"step service is unreachable": {
+			step: v1alpha1.InferenceStep{
+				StepName:        "model2",
+				InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: unreachable.URL},
+			},
+			expectedStatusCode: http.StatusBadGateway,
+			expectedAttempts:   1,
+		},
+		"step service is not ready": {
+			step: v1alpha1.InferenceStep{
+				StepName:        "model2",
+				InferenceTarget: v1alpha1.InferenceTarget{ServiceName: "model2-isvc"},
+				Dependency:      v1alpha1.Hard,
+			},
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedAttempts:   0,
+			expectedError: `{"error": "Failed to process request", "cause": "step model2 of node models failed: InferenceService model2-isvc is not ready",
+				"node": "models", "step": "model2"}`,
+		},
+	}
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			storeGraph(t, graph(scenario.step))
+			rec := httptest.NewRecorder()
+			graphHandler(rec, httptest.NewRequest("POST", "/", strings.NewReader(`{"instances": [1]}`)))
+			assert.Equal(t, scenario.expectedStatusCode, rec.Code)
+			assert.Equal(t, "models", gjson.Get(rec.Body.String(), "node").String())
+			assert.Equal(t, "model2", gjson.Get(rec.Body.String(), "step").String())
+			assert.Equal(t, scenario.expectedAttempts, gjson.Get(rec.Body.String(), "attempts").Int())
+			if scenario.expectedError != "" {
+				assert.JSONEq(t, scenario.expectedError, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestSwitchWithoutMatchingRoute(t *testing.T) {
+	model := newPredictionServer(t, http.StatusOK, `[1]`)
+	storeGraph(t, &v1alpha1.InferenceGraphSpec{
+		Nodes: map[string]v1alpha1.InferenceRouter{
+			"root": {
+				RouterType: v1alpha1.Switch,
+				Steps: []v1alpha1.InferenceStep{
+					{InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: model.URL}, Condition: `body.instances[0] == 2`},
+				},
+			},
+		},
+	})
+	rec := httptest.NewRecorder()
+	graphHandler(rec, httptest.NewRequest("POST", "/", strings.NewReader(`{"instances": [1]}`)))
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, "root", gjson.Get(rec.Body.String(), "node").String())
+}
+
+func TestBodyExcerpt(t *testing.T) {
+	assert.Equal(t, "short", bodyExcerpt([]byte("short")))
+	long := strings.Repeat("a", upstreamBodyExcerptSize-1) + "é"
+	assert.Equal(t, strings.Repeat("a", upstreamBodyExcerptSize-1)+"...", bodyExcerpt([]byte(long)))
+}
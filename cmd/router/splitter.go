@@ -0,0 +1,107 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"net/http"
+	"sync"
+
+	"github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	"github.com/tidwall/gjson"
+)
+
+// seededRand is a random number generator with a fixed seed which is safe for concurrent use
+type seededRand struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+func (s *seededRand) Intn(n int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.r.Intn(n)
+}
+
+var (
+	seededRandsMu sync.Mutex
+	seededRands   = map[string]*seededRand{}
+)
+
+// randomPoint returns a random point in [0,100), splitter nodes with a seed draw from their own seeded generator
+func randomPoint(nodeName string, seed *int64) int {
+	if seed == nil {
+		return rand.Intn(100)
+	}
+	seededRandsMu.Lock()
+	r, ok := seededRands[nodeName]
+	if !ok {
+		r = &seededRand{r: rand.New(rand.NewSource(*seed))}
+		seededRands[nodeName] = r
+	}
+	seededRandsMu.Unlock()
+	return r.Intn(100)
+}
+
+// hashPoint maps the hash key of a request to a point in [0,100), the same key always maps to the same point
+func hashPoint(nodeName string, key string) int {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(nodeName))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum64() % 100)
+}
+
+// splitterHashKey returns the value of the hash key in the request, if the key is not found routing falls back to random
+func splitterHashKey(hashKey *v1alpha1.SplitterHashKey, input []byte, headers http.Header) (string, bool) {
+	if hashKey == nil {
+		return "", false
+	}
+	if hashKey.Header != "" {
+		value := headers.Get(hashKey.Header)
+		return value, value != ""
+	}
+	if hashKey.Field != "" {
+		value := gjson.GetBytes(input, hashKey.Field)
+		return value.String(), value.Exists()
+	}
+	return "", false
+}
+
+// pickupRoute picks the step of a splitter node according to the step weights. Requests with a hash key value
+// are routed consistently, all other requests randomly.
+func pickupRoute(nodeName string, node v1alpha1.InferenceRouter, input []byte, headers http.Header) *v1alpha1.InferenceStep {
+	var point int
+	if key, ok := splitterHashKey(node.HashKey, input, headers); ok {
+		point = hashPoint(nodeName, key)
+	} else {
+		point = randomPoint(nodeName, node.Seed)
+	}
+	end := 0
+	for i := range node.Steps {
+		route := &node.Steps[i]
+		if route.Weight == nil {
+			continue
+		}
+		end += int(*route.Weight)
+		if point < end {
+			return route
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func makeSplitterNode(weights ...int64) v1alpha1.InferenceRouter {
+	node := v1alpha1.InferenceRouter{RouterType: v1alpha1.Splitter}
+	for i, weight := range weights {
+		node.Steps = append(node.Steps, v1alpha1.InferenceStep{
+			StepName:        fmt.Sprintf("route%d", i),
+			InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: fmt.Sprintf("http://route%d", i)},
+			Weight:          proto.Int64(weight),
+		})
+	}
+	return node
+}
+
+func TestPickupRouteReachesEveryRoute(t *testing.T) {
+	node := makeSplitterNode(99, 1)
+	node.Seed = proto.Int64(1)
+	picked := map[string]int{}
+	for i := 0; i < 2000; i++ {
+		route := pickupRoute("reaches-every-route", node, []byte(`{}`), http.Header{})
+		picked[route.StepName]++
+	}
+	// the last route gets its share of the [0,100) range
+	assert.Greater(t, picked["route1"], 0)
+	assert.Greater(t, picked["route0"], picked["route1"])
+
+	node = makeSplitterNode(0, 100)
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, "route1", pickupRoute("last-route-only", node, []byte(`{}`), http.Header{}).StepName)
+	}
+}
+
+func TestPickupRouteWithSeedIsReproducible(t *testing.T) {
+	node := makeSplitterNode(20, 30, 50)
+	node.Seed = proto.Int64(42)
+	var first, second []string
+	for i := 0; i < 50; i++ {
+		first = append(first, pickupRoute("seeded-a", node, []byte(`{}`), http.Header{}).StepName)
+		second = append(second, pickupRoute("seeded-b", node, []byte(`{}`), http.Header{}).StepName)
+	}
+	assert.Equal(t, first, second)
+}
+
+func TestPickupRouteIsStickyOnHashKey(t *testing.T) {
+	scenarios := map[string]struct {
+		hashKey *v1alpha1.SplitterHashKey
+		request func(user string) ([]byte, http.Header)
+	}{
+		"header": {
+			hashKey: &v1alpha1.SplitterHashKey{Header: "X-User-Id"},
+			request: func(user string) ([]byte, http.Header) {
+				return []byte(`{}`), http.Header{"X-User-Id": {user}}
+			},
+		},
+		"json field": {
+			hashKey: &v1alpha1.SplitterHashKey{Field: "instances.0.userId"},
+			request: func(user string) ([]byte, http.Header) {
+				return []byte(`{"instances": [{"userId": "` + user + `"}]}`), http.Header{}
+			},
+		},
+	}
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			node := makeSplitterNode(50, 50)
+			node.HashKey = scenario.hashKey
+			picked := map[string]bool{}
+			for u := 0; u < 100; u++ {
+				user := fmt.Sprintf("user-%d", u)
+				input, headers := scenario.request(user)
+				route := pickupRoute("sticky", node, input, headers)
+				// the same user always stays in the same arm
+				for i := 0; i < 5; i++ {
+					assert.Equal(t, route.StepName, pickupRoute("sticky", node, input, headers).StepName)
+				}
+				picked[route.StepName] = true
+			}
+			assert.Len(t, picked, 2)
+		})
+	}
+}
@@ -0,0 +1,292 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultRetryBackoff            = 100 * time.Millisecond
+	defaultCircuitFailureThreshold = 5
+	defaultCircuitResetTimeout     = 30 * time.Second
+	maxRetryBackoff                = v1alpha1.MaxStepRetryBackoffMillis * time.Millisecond
+)
+
+var defaultRetryableStatusCodes = []int{
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// circuitBreaker counts consecutive failed calls to a service and rejects calls while it is open.
+// After the reset timeout a single trial call is let through, its result closes or re-opens the circuit.
+type circuitBreaker struct {
+	mu               sync.Mutex
+	failureThreshold int
+	resetTimeout     time.Duration
+	failures         int
+	state            circuitState
+	openedAt         time.Time
+}
+
+func newCircuitBreaker(spec *v1alpha1.StepCircuitBreaker) *circuitBreaker {
+	cb := &circuitBreaker{
+		failureThreshold: defaultCircuitFailureThreshold,
+		resetTimeout:     defaultCircuitResetTimeout,
+	}
+	if spec.FailureThreshold != nil {
+		cb.failureThreshold = int(*spec.FailureThreshold)
+	}
+	if spec.ResetTimeoutSeconds != nil {
+		cb.resetTimeout = time.Duration(*spec.ResetTimeoutSeconds) * time.Second
+	}
+	return cb
+}
+
+// allow reports whether a call may be made through the circuit
+func (cb *circuitBreaker) allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	switch cb.state {
+	case circuitOpen:
+		if time.Since(cb.openedAt) < cb.resetTimeout {
+			return false
+		}
+		cb.state = circuitHalfOpen
+		return true
+	case circuitHalfOpen:
+		// only the trial call is let through until it reports back
+		return false
+	}
+	return true
+}
+
+// record reports the result of a call and returns true if the call tripped the circuit
+func (cb *circuitBreaker) record(success bool) bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if success {
+		cb.failures = 0
+		cb.state = circuitClosed
+		return false
+	}
+	cb.failures++
+	if cb.state == circuitHalfOpen || (cb.state == circuitClosed && cb.failures >= cb.failureThreshold) {
+		cb.state = circuitOpen
+		cb.openedAt = time.Now()
+		return true
+	}
+	return false
+}
+
+// release gives up a trial call which did not complete so that another call can be let through
+func (cb *circuitBreaker) release() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if cb.state == circuitHalfOpen {
+		cb.state = circuitOpen
+	}
+}
+
+var (
+	circuitBreakersMu sync.Mutex
+	circuitBreakers   = map[string]*circuitBreaker{}
+)
+
+// getCircuitBreaker returns the circuit breaker for the step service, steps calling the same url with the same
+// circuit breaker settings share the circuit. Changed settings get a new circuit when the graph is reloaded.
+func getCircuitBreaker(step *v1alpha1.InferenceStep) *circuitBreaker {
+	if step.CircuitBreaker == nil {
+		return nil
+	}
+	spec := newCircuitBreaker(step.CircuitBreaker)
+	key := fmt.Sprintf("%s|%d|%s", step.ServiceURL, spec.failureThreshold, spec.resetTimeout)
+	circuitBreakersMu.Lock()
+	defer circuitBreakersMu.Unlock()
+	cb, ok := circuitBreakers[key]
+	if !ok {
+		cb = spec
+		circuitBreakers[key] = cb
+	}
+	return cb
+}
+
+func isRetryable(err error, statusCode int, retryableStatusCodes []int) bool {
+	if err != nil {
+		return true
+	}
+	for _, code := range retryableStatusCodes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// stepRetryPolicy returns the retry policy of the step with the defaults applied
+func stepRetryPolicy(step *v1alpha1.InferenceStep) (maxAttempts int, backoff time.Duration, retryableStatusCodes []int) {
+	maxAttempts = 1
+	backoff = defaultRetryBackoff
+	retryableStatusCodes = defaultRetryableStatusCodes
+	if retry := step.RetryPolicy; retry != nil {
+		if retry.MaxAttempts != nil {
+			maxAttempts = int(*retry.MaxAttempts)
+		}
+		if maxAttempts > v1alpha1.MaxStepRetryAttempts {
+			maxAttempts = v1alpha1.MaxStepRetryAttempts
+		}
+		if retry.Backoff != nil {
+			backoff = time.Duration(*retry.Backoff) * time.Millisecond
+		}
+		if len(retry.RetryableStatusCodes) > 0 {
+			retryableStatusCodes = make([]int, len(retry.RetryableStatusCodes))
+			for i, code := range retry.RetryableStatusCodes {
+				retryableStatusCodes[i] = int(code)
+			}
+		}
+	}
+	return maxAttempts, backoff, retryableStatusCodes
+}
+
+// retryBackoff returns the backoff before the retry following the attempt, the backoff is doubled for every further
+// retry up to the max retry backoff
+func retryBackoff(backoff time.Duration, attempt int) time.Duration {
+	for i := 1; i < attempt && backoff < maxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxRetryBackoff {
+		return maxRetryBackoff
+	}
+	return backoff
+}
+
+type attemptsContextKey struct{}
+
+// withAttempts returns a context in which the call of a step service records the number of calls it made, a step
+// targeting a node makes a single call
+func withAttempts(ctx context.Context) (context.Context, *int) {
+	attempts := 1
+	return context.WithValue(ctx, attemptsContextKey{}, &attempts), &attempts
+}
+
+// withoutAttempts hides the attempts of the context from the calls of a node
+func withoutAttempts(ctx context.Context) context.Context {
+	return context.WithValue(ctx, attemptsContextKey{}, nil)
+}
+
+func recordAttempts(ctx context.Context, attempts int) {
+	if recorded, ok := ctx.Value(attemptsContextKey{}).(*int); ok {
+		*recorded = attempts
+	}
+}
+
+// callStepService calls the service of the step enforcing its timeout, retry policy and circuit breaker.
+// Failed calls return a StepError with the number of attempts made.
+func callStepService(ctx context.Context, step *v1alpha1.InferenceStep, input []byte, headers http.Header) ([]byte, int, error) {
+	if step.ServiceURL == "" {
+		log.Info("InferenceService is not ready, not calling the service", "stepName", step.StepName, "serviceName", step.ServiceName)
+		stepErr := newStepError(ctx, stepMetricsLabel(step), http.StatusServiceUnavailable, 0, &ServiceNotReadyError{ServiceName: step.ServiceName})
+		return nil, stepErr.StatusCode, stepErr
+	}
+	maxAttempts, backoff, retryableStatusCodes := stepRetryPolicy(step)
+	breaker := getCircuitBreaker(step)
+
+	var responseBytes []byte
+	var statusCode int
+	var err error
+	retries, calls := 0, 0
+	defer func() {
+		trace.SpanFromContext(ctx).SetAttributes(retryCountKey.Int(retries))
+		recordAttempts(ctx, calls)
+	}()
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if breaker != nil && !breaker.allow() {
+			log.Info("Circuit breaker is open, not calling the service", "stepName", step.StepName, "url", step.ServiceURL)
+			stepErr := newStepError(ctx, stepMetricsLabel(step), http.StatusServiceUnavailable, attempt-1, &CircuitBreakerOpenError{ServiceURL: step.ServiceURL})
+			return nil, stepErr.StatusCode, stepErr
+		}
+		responseBytes, statusCode, err = callServiceWithTimeout(ctx, step, input, headers)
+		calls++
+		if breaker != nil {
+			if ctx.Err() != nil {
+				// a cancelled request says nothing about the health of the service
+				breaker.release()
+			} else if breaker.record(err == nil && statusCode < 500) {
+				log.Info("Circuit breaker tripped", "stepName", step.StepName, "url", step.ServiceURL, "statusCode", statusCode)
+			}
+		}
+		if stream := streamFromContext(ctx); stream != nil && stream.started {
+			// the response has been sent to the client and can not be retried
+			break
+		}
+		if attempt == maxAttempts || ctx.Err() != nil || !isRetryable(err, statusCode, retryableStatusCodes) {
+			break
+		}
+		log.Info("Retrying step", "stepName", step.StepName, "attempt", attempt, "statusCode", statusCode, "error", err)
+		select {
+		case <-time.After(retryBackoff(backoff, attempt)):
+		case <-ctx.Done():
+			stepErr := newStepError(ctx, stepMetricsLabel(step), 500, attempt, ctx.Err())
+			return nil, stepErr.StatusCode, stepErr
+		}
+		retries++
+	}
+	if err != nil {
+		stepErr := newStepError(ctx, stepMetricsLabel(step), statusCode, retries+1, err)
+		return responseBytes, stepErr.StatusCode, stepErr
+	}
+	return responseBytes, statusCode, err
+}
+
+func callServiceWithTimeout(ctx context.Context, step *v1alpha1.InferenceStep, input []byte, headers http.Header) ([]byte, int, error) {
+	if step.TimeoutSeconds != nil {
+		timeout := time.Duration(*step.TimeoutSeconds) * time.Second
+		var cancel context.CancelFunc
+		if streamFromContext(ctx) != nil && !isGRPCStep(step) {
+			// the timeout stops when the response is streamed to the client
+			ctx, cancel = withStepTimeout(ctx, timeout)
+		} else {
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+		}
+		defer cancel()
+	}
+	authHeader, err := stepAuthHeader(step, headers)
+	if err != nil {
+		return nil, 500, err
+	}
+	ctx = withAuthHeader(ctx, authHeader)
+	if isGRPCStep(step) {
+		return callGRPCService(ctx, step, input, headers)
+	}
+	return callService(ctx, step.ServiceURL, input, headers)
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallStepServiceRetriesRetryableStatusCodes(t *testing.T) {
+	var calls int32
+	model := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		responseBytes, _ := json.Marshal(map[string]interface{}{"predictions": "1"})
+		rw.Write(responseBytes)
+	}))
+	defer model.Close()
+
+	step := &v1alpha1.InferenceStep{
+		InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: model.URL},
+		RetryPolicy: &v1alpha1.StepRetryPolicy{
+			MaxAttempts: proto.Int32(3),
+			Backoff:     proto.Int64(1),
+		},
+	}
+	res, statusCode, err := callStepService(context.Background(), step, []byte("{}"), http.Header{})
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.JSONEq(t, `{"predictions": "1"}`, string(res))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestCallStepServiceDoesNotRetryOtherStatusCodes(t *testing.T) {
+	var calls int32
+	model := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		rw.WriteHeader(http.StatusBadRequest)
+	}))
+	defer model.Close()
+
+	step := &v1alpha1.InferenceStep{
+		InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: model.URL},
+		RetryPolicy: &v1alpha1.StepRetryPolicy{
+			MaxAttempts: proto.Int32(3),
+			Backoff:     proto.Int64(1),
+		},
+	}
+	_, statusCode, err := callStepService(context.Background(), step, []byte("{}"), http.Header{})
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestCallStepServiceTimeout(t *testing.T) {
+	var calls int32
+	done := make(chan struct{})
+	model := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		<-done
+	}))
+	defer model.Close()
+	defer close(done)
+
+	step := &v1alpha1.InferenceStep{
+		InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: model.URL},
+		TimeoutSeconds:  proto.Int64(1),
+		RetryPolicy: &v1alpha1.StepRetryPolicy{
+			MaxAttempts: proto.Int32(2),
+			Backoff:     proto.Int64(1),
+		},
+	}
+	start := time.Now()
+	_, _, err := callStepService(context.Background(), step, []byte("{}"), http.Header{})
+	assert.NotNil(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestNodeStepTimeout(t *testing.T) {
+	done := make(chan struct{})
+	model := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		<-done
+	}))
+	defer model.Close()
+	defer close(done)
+
+	graph := v1alpha1.InferenceGraphSpec{
+		Nodes: map[string]v1alpha1.InferenceRouter{
+			"root": {
+				RouterType: v1alpha1.Sequence,
+				Steps: []v1alpha1.InferenceStep{
+					{
+						StepName:        "slow-node",
+						InferenceTarget: v1alpha1.InferenceTarget{NodeName: "slow"},
+						TimeoutSeconds:  proto.Int64(1),
+					},
+				},
+			},
+			"slow": {
+				RouterType: v1alpha1.Sequence,
+				Steps: []v1alpha1.InferenceStep{
+					{StepName: "model", InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: model.URL}},
+				},
+			},
+		},
+	}
+	start := time.Now()
+	_, _, err := routeStep(context.Background(), "root", graph, []byte("{}"), http.Header{})
+	assert.NotNil(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestCallStepServiceCircuitBreaker(t *testing.T) {
+	var calls int32
+	model := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		rw.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer model.Close()
+
+	step := &v1alpha1.InferenceStep{
+		InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: model.URL},
+		CircuitBreaker: &v1alpha1.StepCircuitBreaker{
+			FailureThreshold:    proto.Int32(2),
+			ResetTimeoutSeconds: proto.Int64(1),
+		},
+	}
+	for i := 0; i < 2; i++ {
+		_, statusCode, err := callStepService(context.Background(), step, []byte("{}"), http.Header{})
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusInternalServerError, statusCode)
+	}
+	// the circuit is open so the service is not called
+	_, statusCode, err := callStepService(context.Background(), step, []byte("{}"), http.Header{})
+	var circuitErr *CircuitBreakerOpenError
+	assert.ErrorAs(t, err, &circuitErr)
+	assert.Equal(t, http.StatusServiceUnavailable, statusCode)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	// after the reset timeout a trial call is let through and re-opens the circuit
+	time.Sleep(1100 * time.Millisecond)
+	_, statusCode, err = callStepService(context.Background(), step, []byte("{}"), http.Header{})
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusInternalServerError, statusCode)
+	_, _, err = callStepService(context.Background(), step, []byte("{}"), http.Header{})
+	assert.ErrorAs(t, err, &circuitErr)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestCircuitBreakerClosesAfterSuccessfulTrial(t *testing.T) {
+	cb := newCircuitBreaker(&v1alpha1.StepCircuitBreaker{
+		FailureThreshold:    proto.Int32(1),
+		ResetTimeoutSeconds: proto.Int64(1),
+	})
+	assert.True(t, cb.allow())
+	assert.True(t, cb.record(false))
+	assert.False(t, cb.allow())
+
+	cb.openedAt = time.Now().Add(-2 * time.Second)
+	assert.True(t, cb.allow())
+	// only one trial call is allowed while half open
+	assert.False(t, cb.allow())
+	assert.False(t, cb.record(true))
+	assert.True(t, cb.allow())
+}
+
+func TestCircuitBreakerSharedBySettings(t *testing.T) {
+	step := func(failureThreshold int32) *v1alpha1.InferenceStep {
+		return &v1alpha1.InferenceStep{
+			InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: "http://shared-breaker-model"},
+			CircuitBreaker:  &v1alpha1.StepCircuitBreaker{FailureThreshold: proto.Int32(failureThreshold)},
+		}
+	}
+	assert.Same(t, getCircuitBreaker(step(1)), getCircuitBreaker(step(1)))
+	assert.NotSame(t, getCircuitBreaker(step(1)), getCircuitBreaker(step(3)))
+	assert.Equal(t, 3, getCircuitBreaker(step(3)).failureThreshold)
+}
+
+func TestRetryPolicyLimits(t *testing.T) {
+	maxAttempts, backoff, _ := stepRetryPolicy(&v1alpha1.InferenceStep{
+		RetryPolicy: &v1alpha1.StepRetryPolicy{MaxAttempts: proto.Int32(1000), Backoff: proto.Int64(100)},
+	})
+	assert.Equal(t, v1alpha1.MaxStepRetryAttempts, maxAttempts)
+	assert.Equal(t, 100*time.Millisecond, retryBackoff(backoff, 1))
+	assert.Equal(t, 400*time.Millisecond, retryBackoff(backoff, 3))
+	assert.Equal(t, maxRetryBackoff, retryBackoff(backoff, 100))
+	assert.Equal(t, maxRetryBackoff, retryBackoff(time.Hour, 1))
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultMaxBodySize   = 100 << 20
+	eventStreamMediaType = "text/event-stream"
+	streamBufferSize     = 32 << 10
+)
+
+// streamedHeaders are the response headers of the step which are passed through to the client when streaming
+var streamedHeaders = []string{"Content-Type", "Cache-Control"}
+
+// BodyTooLargeError is returned when a request or a buffered step response exceeds the max body size
+type BodyTooLargeError struct {
+	MaxBodySize int64
+}
+
+func (e *BodyTooLargeError) Error() string {
+	return fmt.Sprintf("body exceeds the max body size of %d bytes", e.MaxBodySize)
+}
+
+// responseStream writes the response of the tail step of the graph straight to the client. The tail steps are the
+// last step of a Sequence node and the route of a Splitter or Switch node, when the graph ends in such a step its
+// response does not need to be buffered by the router.
+type responseStream struct {
+	w       http.ResponseWriter
+	started bool
+}
+
+type streamContextKey struct{}
+
+func withStream(ctx context.Context, stream *responseStream) context.Context {
+	return context.WithValue(ctx, streamContextKey{}, stream)
+}
+
+// withoutStream is the context of the steps whose response is consumed by the router instead of the client
+func withoutStream(ctx context.Context) context.Context {
+	if streamFromContext(ctx) == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, streamContextKey{}, (*responseStream)(nil))
+}
+
+func streamFromContext(ctx context.Context) *responseStream {
+	stream, _ := ctx.Value(streamContextKey{}).(*responseStream)
+	return stream
+}
+
+// isStreamingResponse reports whether the response is streamed rather than buffered: server-sent events and
+// responses whose length is larger than the max body size
+func isStreamingResponse(resp *http.Response) bool {
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return mediaType == eventStreamMediaType || resp.ContentLength > *maxBodySize
+}
+
+// isChunkedResponse reports whether the response is chunked, a chunked response is only streamed once it turns out to
+// be larger than the max body size
+func isChunkedResponse(resp *http.Response) bool {
+	for _, encoding := range resp.TransferEncoding {
+		if encoding == "chunked" {
+			return true
+		}
+	}
+	return false
+}
+
+// stepTimeoutContext is cancelled with a deadline exceeded error when the step times out. Unlike a context with a
+// deadline its timeout can be stopped, so that a streamed response lasts as long as the step service sends it.
+type stepTimeoutContext struct {
+	context.Context
+	timer    *time.Timer
+	timedOut atomic.Bool
+}
+
+type stepTimeoutContextKey struct{}
+
+// withStepTimeout returns a context which is cancelled after the timeout unless the timeout is stopped first
+func withStepTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	timeoutCtx := &stepTimeoutContext{Context: ctx}
+	timeoutCtx.timer = time.AfterFunc(timeout, func() {
+		timeoutCtx.timedOut.Store(true)
+		cancel(context.DeadlineExceeded)
+	})
+	return timeoutCtx, func() {
+		timeoutCtx.timer.Stop()
+		cancel(context.Canceled)
+	}
+}
+
+func (c *stepTimeoutContext) Err() error {
+	err := c.Context.Err()
+	if err != nil && c.timedOut.Load() {
+		return context.DeadlineExceeded
+	}
+	return err
+}
+
+func (c *stepTimeoutContext) Value(key any) any {
+	if key == (stepTimeoutContextKey{}) {
+		return c
+	}
+	return c.Context.Value(key)
+}
+
+// stopStepTimeout stops the timeout of the step before its response is streamed, it returns false when the step has
+// already timed out
+func stopStepTimeout(ctx context.Context) bool {
+	timeoutCtx, _ := ctx.Value(stepTimeoutContextKey{}).(*stepTimeoutContext)
+	return timeoutCtx == nil || timeoutCtx.timer.Stop()
+}
+
+// pipe copies the response to the client, every chunk is flushed so that events reach the client as they arrive
+func (s *responseStream) pipe(resp *http.Response) error {
+	s.started = true
+	header := s.w.Header()
+	for _, h := range streamedHeaders {
+		if value := resp.Header.Get(h); value != "" {
+			header.Set(h, value)
+		}
+	}
+	if resp.ContentLength >= 0 {
+		header.Set("Content-Length", strconv.FormatInt(resp.ContentLength, 10))
+	}
+	s.w.WriteHeader(resp.StatusCode)
+	flusher, _ := s.w.(http.Flusher)
+	buf := make([]byte, streamBufferSize)
+	for {
+		n, err := resp.Body.Read(buf)
+		if n > 0 {
+			if _, err := s.w.Write(buf[:n]); err != nil {
+				return err
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// readBody buffers the body failing when it exceeds the max body size
+func readBody(body io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(body, *maxBodySize+1))
+	if err != nil {
+		return data, err
+	}
+	if int64(len(data)) > *maxBodySize {
+		return nil, &BodyTooLargeError{MaxBodySize: *maxBodySize}
+	}
+	return data, nil
+}
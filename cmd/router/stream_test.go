@@ -0,0 +1,310 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+// newEventStreamServer sends the events one at a time, every event but the first waits for the previous one
+// to be received by the client so the test fails if the router buffers the response
+func newEventStreamServer(t *testing.T, events []string, received chan struct{}) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = io.ReadAll(req.Body)
+		rw.Header().Set("Content-Type", "text/event-stream")
+		rw.WriteHeader(http.StatusOK)
+		for i, event := range events {
+			if i > 0 {
+				<-received
+			}
+			_, _ = rw.Write([]byte("data: " + event + "\n\n"))
+			rw.(http.Flusher).Flush()
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestStreamEventsFromTailStep(t *testing.T) {
+	events := []string{`{"token": "hello"}`, `{"token": "world"}`, `[DONE]`}
+	scenarios := map[string]func(streamURL string, modelURL string) v1alpha1.InferenceGraphSpec{
+		"sequence tail": func(streamURL string, modelURL string) v1alpha1.InferenceGraphSpec {
+			return v1alpha1.InferenceGraphSpec{
+				Nodes: map[string]v1alpha1.InferenceRouter{
+					"root": {
+						RouterType: v1alpha1.Sequence,
+						Steps: []v1alpha1.InferenceStep{
+							{InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: modelURL}},
+							{InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: streamURL}},
+						},
+					},
+				},
+			}
+		},
+		"splitter route to a sequence node": func(streamURL string, modelURL string) v1alpha1.InferenceGraphSpec {
+			return v1alpha1.InferenceGraphSpec{
+				Nodes: map[string]v1alpha1.InferenceRouter{
+					"root": {
+						RouterType: v1alpha1.Splitter,
+						Steps: []v1alpha1.InferenceStep{
+							{InferenceTarget: v1alpha1.InferenceTarget{NodeName: "llm"}, Weight: proto.Int64(100)},
+						},
+					},
+					"llm": {
+						RouterType: v1alpha1.Sequence,
+						Steps: []v1alpha1.InferenceStep{
+							{InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: streamURL}},
+						},
+					},
+				},
+			}
+		},
+	}
+	for name, graph := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			received := make(chan struct{})
+			streamServer := newEventStreamServer(t, events, received)
+			model := newPredictionServer(t, http.StatusOK, `[1]`)
+			spec := graph(streamServer.URL, model.URL)
+			storeGraph(t, &spec)
+			router := httptest.NewServer(http.HandlerFunc(graphHandler))
+			defer router.Close()
+
+			resp, err := http.Post(router.URL, "application/json", strings.NewReader(`{"instances": [1]}`))
+			assert.Nil(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+			reader := bufio.NewReader(resp.Body)
+			for i, event := range events {
+				line, err := reader.ReadString('\n')
+				assert.Nil(t, err)
+				assert.Equal(t, "data: "+event+"\n", line)
+				_, _ = reader.ReadString('\n')
+				if i < len(events)-1 {
+					received <- struct{}{}
+				}
+			}
+			_, err = reader.ReadByte()
+			assert.Equal(t, io.EOF, err)
+		})
+	}
+}
+
+func TestStreamIsNotUsedForMergedResponses(t *testing.T) {
+	streamServer := newEventStreamServer(t, []string{"1"}, nil)
+	model := newPredictionServer(t, http.StatusOK, `[1]`)
+	storeGraph(t, &v1alpha1.InferenceGraphSpec{
+		Nodes: map[string]v1alpha1.InferenceRouter{
+			"root": {
+				RouterType: v1alpha1.Sequence,
+				Steps: []v1alpha1.InferenceStep{
+					{InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: streamServer.URL}},
+					{InferenceTarget: v1alpha1.InferenceTarget{NodeName: "ensemble"}},
+				},
+			},
+			"ensemble": {
+				RouterType: v1alpha1.Ensemble,
+				Steps: []v1alpha1.InferenceStep{
+					{StepName: "events", InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: streamServer.URL}},
+					{StepName: "model", InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: model.URL}},
+				},
+			},
+		},
+	})
+	rec := httptest.NewRecorder()
+	graphHandler(rec, httptest.NewRequest("POST", "/", strings.NewReader(`{"instances": [1]}`)))
+	// the event stream is buffered by the ensemble which fails to merge it
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), `"model":{"predictions":[1]}`)
+	assert.Contains(t, rec.Body.String(), `"errors":{"events"`)
+}
+
+func TestMaxBodySize(t *testing.T) {
+	defer func(size int64) { *maxBodySize = size }(*maxBodySize)
+	*maxBodySize = 32
+	model := newPredictionServer(t, http.StatusOK, `[1, 2, 3, 4, 5, 6, 7, 8, 9, 10]`)
+	storeGraph(t, &v1alpha1.InferenceGraphSpec{
+		Nodes: map[string]v1alpha1.InferenceRouter{
+			"root": {
+				RouterType: v1alpha1.Sequence,
+				Steps: []v1alpha1.InferenceStep{
+					{InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: model.URL}},
+					{InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: model.URL}},
+				},
+			},
+		},
+	})
+
+	rec := httptest.NewRecorder()
+	graphHandler(rec, httptest.NewRequest("POST", "/", strings.NewReader(`{"instances": [1, 2, 3, 4, 5, 6, 7, 8, 9, 10]}`)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+	// the response of the first step is buffered by the router
+	rec = httptest.NewRecorder()
+	graphHandler(rec, httptest.NewRequest("POST", "/", strings.NewReader(`{"instances": [1]}`)))
+	assert.Equal(t, http.StatusBadGateway, rec.Code)
+	assert.Contains(t, rec.Body.String(), "body exceeds the max body size of 32 bytes")
+
+	// the response of the last step is larger than the max body size and is streamed
+	storeGraph(t, &v1alpha1.InferenceGraphSpec{
+		Nodes: map[string]v1alpha1.InferenceRouter{
+			"root": {
+				RouterType: v1alpha1.Sequence,
+				Steps: []v1alpha1.InferenceStep{
+					{InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: model.URL}},
+				},
+			},
+		},
+	})
+	rec = httptest.NewRecorder()
+	graphHandler(rec, httptest.NewRequest("POST", "/", strings.NewReader(`{"instances": [1]}`)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `{"predictions": [1, 2, 3, 4, 5, 6, 7, 8, 9, 10]}`, rec.Body.String())
+}
+
+func TestChunkedResponses(t *testing.T) {
+	defer func(size int64) { *maxBodySize = size }(*maxBodySize)
+	*maxBodySize = 32
+	// every write is flushed so that the response is chunked
+	newChunkedServer := func(chunks ...string) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			_, _ = io.ReadAll(req.Body)
+			rw.Header().Set("Content-Type", "application/json")
+			for _, chunk := range chunks {
+				_, _ = rw.Write([]byte(chunk))
+				rw.(http.Flusher).Flush()
+			}
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+	scenarios := map[string]struct {
+		chunks   []string
+		streamed bool
+	}{
+		"small chunked response is buffered": {
+			chunks: []string{`{"predictions": `, `[1]}`},
+		},
+		"chunked response larger than the max body size is streamed": {
+			chunks:   []string{`{"predictions": [1, 2, 3, 4, 5, `, `6, 7, 8, 9, 10]}`},
+			streamed: true,
+		},
+	}
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			model := newChunkedServer(scenario.chunks...)
+			storeGraph(t, &v1alpha1.InferenceGraphSpec{
+				Nodes: map[string]v1alpha1.InferenceRouter{
+					"root": {
+						RouterType: v1alpha1.Sequence,
+						Steps: []v1alpha1.InferenceStep{
+							{InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: model.URL}},
+						},
+					},
+				},
+			})
+			router := httptest.NewServer(http.HandlerFunc(graphHandler))
+			defer router.Close()
+			resp, err := http.Post(router.URL, "application/json", strings.NewReader(`{"instances": [1]}`))
+			assert.Nil(t, err)
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, strings.Join(scenario.chunks, ""), string(body))
+			// the buffered response is written at once with its length
+			assert.Equal(t, scenario.streamed, resp.ContentLength < 0)
+		})
+	}
+}
+
+func TestStreamIsNotCutByStepTimeout(t *testing.T) {
+	events := newEventStreamServer(t, []string{"1"}, nil)
+	slowEvents := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = io.ReadAll(req.Body)
+		rw.Header().Set("Content-Type", "text/event-stream")
+		rw.WriteHeader(http.StatusOK)
+		_, _ = rw.Write([]byte("data: 1\n\n"))
+		rw.(http.Flusher).Flush()
+		time.Sleep(1500 * time.Millisecond)
+		_, _ = rw.Write([]byte("data: 2\n\n"))
+	}))
+	defer slowEvents.Close()
+	slowHeaders := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = io.ReadAll(req.Body)
+		select {
+		case <-req.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer slowHeaders.Close()
+	scenarios := map[string]struct {
+		serviceURL         string
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		"stream": {
+			serviceURL:         events.URL,
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "data: 1\n\n",
+		},
+		"stream outlasting the step timeout": {
+			serviceURL:         slowEvents.URL,
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "data: 1\n\ndata: 2\n\n",
+		},
+		"step timing out before responding": {
+			serviceURL:         slowHeaders.URL,
+			expectedStatusCode: http.StatusGatewayTimeout,
+		},
+	}
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			storeGraph(t, &v1alpha1.InferenceGraphSpec{
+				Nodes: map[string]v1alpha1.InferenceRouter{
+					"root": {
+						RouterType: v1alpha1.Sequence,
+						Steps: []v1alpha1.InferenceStep{
+							{
+								InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: scenario.serviceURL},
+								TimeoutSeconds:  proto.Int64(1),
+							},
+						},
+					},
+				},
+			})
+			rec := httptest.NewRecorder()
+			graphHandler(rec, httptest.NewRequest("POST", "/", strings.NewReader(`{"instances": [1]}`)))
+			assert.Equal(t, scenario.expectedStatusCode, rec.Code)
+			if scenario.expectedBody != "" {
+				assert.Equal(t, scenario.expectedBody, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	"github.com/kserve/kserve/pkg/constants"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	tracerName = "github.com/kserve/kserve/cmd/router"
+
+	// supported values of the traces exporter env variable
+	otlpTracesExporter   = "otlp"
+	stdoutTracesExporter = "stdout"
+	noneTracesExporter   = "none"
+)
+
+var (
+	nodeNameKey   = attribute.Key("inferencegraph.node")
+	routerTypeKey = attribute.Key("inferencegraph.router_type")
+	stepNameKey   = attribute.Key("inferencegraph.step")
+	stepTargetKey = attribute.Key("inferencegraph.step.target")
+	retryCountKey = attribute.Key("inferencegraph.step.retry_count")
+)
+
+// propagator reads and writes the W3C traceparent and tracestate headers,
+// the trace context is propagated to the steps even when the router does not export spans
+var propagator propagation.TextMapPropagator = propagation.TraceContext{}
+
+// initTracing sets up the span exporter configured through the OTEL_TRACES_EXPORTER env variable.
+// The otlp exporter is further configured by the standard OTEL_EXPORTER_OTLP_* env variables and sampling by
+// OTEL_TRACES_SAMPLER and OTEL_TRACES_SAMPLER_ARG. The returned function flushes the pending spans on shutdown.
+func initTracing(ctx context.Context) (func(context.Context) error, error) {
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch tracesExporter := os.Getenv(constants.RouterTracesExporterEnvVar); tracesExporter {
+	case "", noneTracesExporter:
+		return func(context.Context) error { return nil }, nil
+	case otlpTracesExporter:
+		exporter, err = otlptracegrpc.New(ctx)
+	case stdoutTracesExporter:
+		exporter, err = stdouttrace.New()
+	default:
+		return nil, fmt.Errorf("unsupported traces exporter %q, supported exporters are %s, %s and %s",
+			tracesExporter, otlpTracesExporter, stdoutTracesExporter, noneTracesExporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.Default()),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// tracer is fetched from the global provider on every span, so that spans are recorded by the provider currently set
+// by initTracing rather than the one set when the tracer was first created
+func tracer() trace.Tracer {
+	return otel.GetTracerProvider().Tracer(tracerName)
+}
+
+// startGraphSpan starts the span of a graph request as a child of the trace context of the request headers
+func startGraphSpan(ctx context.Context, headers http.Header) (context.Context, trace.Span) {
+	ctx = propagator.Extract(ctx, propagation.HeaderCarrier(headers))
+	return tracer().Start(ctx, "InferenceGraph", trace.WithSpanKind(trace.SpanKindServer))
+}
+
+func startNodeSpan(ctx context.Context, nodeName string, node v1alpha1.InferenceRouter) (context.Context, trace.Span) {
+	return tracer().Start(ctx, "node "+nodeName, trace.WithAttributes(
+		nodeNameKey.String(nodeName),
+		routerTypeKey.String(string(node.RouterType)),
+	))
+}
+
+func startStepSpan(ctx context.Context, step *v1alpha1.InferenceStep) (context.Context, trace.Span) {
+	name := "step"
+	if step.StepName != "" {
+		name += " " + step.StepName
+	}
+	target := step.ServiceURL
+	if step.NodeName != "" {
+		target = step.NodeName
+	}
+	return tracer().Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		stepNameKey.String(step.StepName),
+		stepTargetKey.String(target),
+	))
+}
+
+// endSpan records the outcome of a graph, node or step on its span and ends it
+func endSpan(span trace.Span, statusCode int, err error) {
+	if statusCode != 0 {
+		span.SetAttributes(semconv.HTTPStatusCode(statusCode))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else if statusCode >= 500 {
+		span.SetStatus(codes.Error, http.StatusText(statusCode))
+	}
+	span.End()
+}
+
+// metadataCarrier adapts gRPC metadata to the propagation carrier interface
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key string, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	"github.com/kserve/kserve/pkg/constants"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc/metadata"
+)
+
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestGraphSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previousProvider := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+	})
+
+	var receivedTraceparents []string
+	model1 := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = io.ReadAll(req.Body)
+		receivedTraceparents = append(receivedTraceparents, req.Header.Get("traceparent"))
+		_, _ = rw.Write([]byte(`{"predictions": [1]}`))
+	}))
+	defer model1.Close()
+	var model2Calls int32
+	model2 := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = io.ReadAll(req.Body)
+		receivedTraceparents = append(receivedTraceparents, req.Header.Get("traceparent"))
+		if atomic.AddInt32(&model2Calls, 1) == 1 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = rw.Write([]byte(`{"predictions": [2]}`))
+	}))
+	defer model2.Close()
+
+	storeGraph(t, &v1alpha1.InferenceGraphSpec{
+		Nodes: map[string]v1alpha1.InferenceRouter{
+			"root": {
+				RouterType: v1alpha1.Sequence,
+				Steps: []v1alpha1.InferenceStep{
+					{
+						StepName: "model1",
+						InferenceTarget: v1alpha1.InferenceTarget{
+							ServiceURL: model1.URL,
+						},
+					},
+					{
+						StepName: "model2",
+						InferenceTarget: v1alpha1.InferenceTarget{
+							ServiceURL: model2.URL,
+						},
+						RetryPolicy: &v1alpha1.StepRetryPolicy{MaxAttempts: proto.Int32(2), Backoff: proto.Int64(1)},
+					},
+				},
+			},
+		},
+	})
+	traceID := "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest("POST", "/", strings.NewReader(`{"instances": [1]}`))
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	rec := httptest.NewRecorder()
+	graphHandler(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// the trace of the incoming request is continued by the steps
+	assert.Len(t, receivedTraceparents, 3)
+	for _, traceparent := range receivedTraceparents {
+		assert.True(t, strings.HasPrefix(traceparent, "00-"+traceID+"-"), traceparent)
+	}
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		assert.Equal(t, traceID, span.SpanContext().TraceID().String())
+		spans[span.Name()] = span
+	}
+	assert.Len(t, spans, 4)
+	graphSpan := spans["InferenceGraph"]
+	nodeSpan := spans["node root"]
+	model1Span := spans["step model1"]
+	model2Span := spans["step model2"]
+	assert.Equal(t, graphSpan.SpanContext().SpanID(), nodeSpan.Parent().SpanID())
+	assert.Equal(t, nodeSpan.SpanContext().SpanID(), model1Span.Parent().SpanID())
+	assert.Equal(t, nodeSpan.SpanContext().SpanID(), model2Span.Parent().SpanID())
+
+	assert.Equal(t, string(v1alpha1.Sequence), spanAttribute(nodeSpan, routerTypeKey).AsString())
+	assert.Equal(t, "model2", spanAttribute(model2Span, stepNameKey).AsString())
+	assert.Equal(t, int64(0), spanAttribute(model1Span, retryCountKey).AsInt64())
+	assert.Equal(t, int64(1), spanAttribute(model2Span, retryCountKey).AsInt64())
+	assert.Equal(t, int64(http.StatusOK), spanAttribute(graphSpan, "http.status_code").AsInt64())
+}
+
+func TestMetadataCarrier(t *testing.T) {
+	traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	ctx := propagator.Extract(context.Background(), metadataCarrier(metadata.Pairs("traceparent", traceparent)))
+	md := metadata.MD{}
+	propagator.Inject(ctx, metadataCarrier(md))
+	assert.Equal(t, []string{traceparent}, md.Get("traceparent"))
+}
+
+func TestInitTracing(t *testing.T) {
+	t.Setenv(constants.RouterTracesExporterEnvVar, "none")
+	shutdown, err := initTracing(context.Background())
+	assert.Nil(t, err)
+	assert.Nil(t, shutdown(context.Background()))
+
+	t.Setenv(constants.RouterTracesExporterEnvVar, "zipkin")
+	_, err = initTracing(context.Background())
+	assert.NotNil(t, err)
+}
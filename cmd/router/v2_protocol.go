@@ -0,0 +1,123 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	"github.com/kserve/kserve/pkg/constants"
+)
+
+// InferTensor is a tensor of the Open Inference Protocol (v2) request or response. The data is kept as raw json so that
+// it is passed on unchanged, INT64 values beyond the float64 precision would be rounded by a generic json decoding.
+type InferTensor struct {
+	Name       string                 `json:"name"`
+	Shape      []int64                `json:"shape"`
+	Datatype   string                 `json:"datatype"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	Data       json.RawMessage        `json:"data"`
+}
+
+// InferRequestedOutput is an output requested by a v2 inference request
+type InferRequestedOutput struct {
+	Name       string                 `json:"name"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+}
+
+// InferRequest is the body of a v2 inference request
+type InferRequest struct {
+	ID         string                 `json:"id,omitempty"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	Inputs     []InferTensor          `json:"inputs"`
+	Outputs    []InferRequestedOutput `json:"outputs,omitempty"`
+}
+
+// InferResponse is the body of a v2 inference response
+type InferResponse struct {
+	ModelName    string                 `json:"model_name"`
+	ModelVersion string                 `json:"model_version,omitempty"`
+	ID           string                 `json:"id,omitempty"`
+	Parameters   map[string]interface{} `json:"parameters,omitempty"`
+	Outputs      []InferTensor          `json:"outputs"`
+}
+
+func isV2Node(node v1alpha1.InferenceRouter) bool {
+	return node.ProtocolVersion != nil && *node.ProtocolVersion == constants.ProtocolV2
+}
+
+// tensorView rewrites the inputs and outputs tensor lists of a v2 payload as objects keyed by tensor name,
+// so that conditions can address tensors by name e.g `outputs.label.data.#(=="dog")`.
+// Payloads which are not json objects are returned unchanged.
+func tensorView(payload []byte) []byte {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &doc); err != nil {
+		return payload
+	}
+	for _, key := range []string{"inputs", "outputs"} {
+		raw, ok := doc[key]
+		if !ok {
+			continue
+		}
+		var tensors []InferTensor
+		if err := json.Unmarshal(raw, &tensors); err != nil {
+			continue
+		}
+		byName := make(map[string]InferTensor, len(tensors))
+		for _, tensor := range tensors {
+			byName[tensor.Name] = tensor
+		}
+		viewTensors, err := json.Marshal(byName)
+		if err != nil {
+			return payload
+		}
+		doc[key] = viewTensors
+	}
+	view, err := json.Marshal(doc)
+	if err != nil {
+		return payload
+	}
+	return view
+}
+
+// v2ResponseToRequest builds the v2 request for the next step from the output tensors of a v2 response.
+// data is the step data field, `$response` passes all output tensors and `$response.<name>` only the named one.
+func v2ResponseToRequest(data string, response []byte) ([]byte, error) {
+	inferResponse := InferResponse{}
+	if err := json.Unmarshal(response, &inferResponse); err != nil || inferResponse.Outputs == nil {
+		return nil, fmt.Errorf("response is not a valid v2 inference response")
+	}
+	outputs := inferResponse.Outputs
+	if name := strings.TrimPrefix(data, "$response."); name != data {
+		outputs = nil
+		for _, output := range inferResponse.Outputs {
+			if output.Name == name {
+				outputs = append(outputs, output)
+			}
+		}
+		if len(outputs) == 0 {
+			return nil, fmt.Errorf("output tensor %q not found in the v2 inference response", name)
+		}
+	}
+	inferRequest := InferRequest{
+		ID:     inferResponse.ID,
+		Inputs: outputs,
+	}
+	return json.Marshal(inferRequest)
+}
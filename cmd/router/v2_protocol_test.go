@@ -0,0 +1,169 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	"github.com/kserve/kserve/pkg/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestTensorView(t *testing.T) {
+	payload := []byte(`{"model_name":"classifier","outputs":[{"name":"label","shape":[1],"datatype":"BYTES","data":["dog"]},` +
+		`{"name":"score","shape":[1],"datatype":"FP32","data":[0.8]}]}`)
+	view := tensorView(payload)
+	assert.True(t, gjson.GetBytes(view, `outputs.label.data.#(=="dog")`).Exists())
+	assert.False(t, gjson.GetBytes(view, `outputs.label.data.#(=="cat")`).Exists())
+	assert.True(t, gjson.GetBytes(view, `outputs.score.data.#(>0.5)`).Exists())
+	assert.Equal(t, "classifier", gjson.GetBytes(view, "model_name").String())
+
+	// non v2 payloads are left untouched
+	assert.Equal(t, []byte(`["a"]`), tensorView([]byte(`["a"]`)))
+}
+
+func TestV2ResponseToRequest(t *testing.T) {
+	response := []byte(`{"model_name":"m","id":"1","outputs":[{"name":"a","shape":[2],"datatype":"FP32","data":[1,2]},` +
+		`{"name":"b","shape":[1],"datatype":"INT32","data":[3]}]}`)
+	scenarios := map[string]struct {
+		data     string
+		expected string
+		hasError bool
+	}{
+		"all outputs": {
+			data: "$response",
+			expected: `{"id":"1","inputs":[{"name":"a","shape":[2],"datatype":"FP32","data":[1,2]},` +
+				`{"name":"b","shape":[1],"datatype":"INT32","data":[3]}]}`,
+		},
+		"named output": {
+			data:     "$response.b",
+			expected: `{"id":"1","inputs":[{"name":"b","shape":[1],"datatype":"INT32","data":[3]}]}`,
+		},
+		"missing output": {
+			data:     "$response.c",
+			hasError: true,
+		},
+	}
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			request, err := v2ResponseToRequest(scenario.data, response)
+			if scenario.hasError {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.JSONEq(t, scenario.expected, string(request))
+		})
+	}
+
+	_, err := v2ResponseToRequest("$response", []byte(`{"predictions": [1]}`))
+	assert.NotNil(t, err)
+
+	// INT64 values beyond the float64 precision are passed on exactly
+	request, err := v2ResponseToRequest("$response",
+		[]byte(`{"model_name":"m","outputs":[{"name":"id","shape":[1],"datatype":"INT64","data":[9007199254740993]}]}`))
+	assert.Nil(t, err)
+	assert.Equal(t, "9007199254740993", gjson.GetBytes(request, "inputs.0.data.0").Raw)
+}
+
+func TestV2InferenceGraph(t *testing.T) {
+	// classifier returns a label and a score tensor
+	classifier := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = io.ReadAll(req.Body)
+		_, _ = rw.Write([]byte(`{"model_name":"classifier","outputs":[` +
+			`{"name":"label","shape":[1],"datatype":"BYTES","data":["dog"]},` +
+			`{"name":"embedding","shape":[1,2],"datatype":"FP32","data":[0.1,0.2]}]}`))
+	}))
+	defer classifier.Close()
+	var breedRequest InferRequest
+	breed := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		_ = json.Unmarshal(body, &breedRequest)
+		_, _ = rw.Write([]byte(`{"model_name":"breed","outputs":[{"name":"breed","shape":[1],"datatype":"BYTES","data":["beagle"]}]}`))
+	}))
+	defer breed.Close()
+	other := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = rw.Write([]byte(`{"model_name":"other","outputs":[]}`))
+	}))
+	defer other.Close()
+
+	protocolV2 := constants.ProtocolV2
+	graphSpec := v1alpha1.InferenceGraphSpec{
+		Nodes: map[string]v1alpha1.InferenceRouter{
+			"root": {
+				RouterType:      v1alpha1.Switch,
+				ProtocolVersion: &protocolV2,
+				Steps: []v1alpha1.InferenceStep{
+					{
+						StepName: "other",
+						InferenceTarget: v1alpha1.InferenceTarget{
+							ServiceURL: other.URL,
+						},
+						Condition: `inputs.kind.data.#(=="other")`,
+					},
+					{
+						StepName: "animal",
+						InferenceTarget: v1alpha1.InferenceTarget{
+							NodeName: "animal",
+						},
+						Condition: `inputs.kind.data.#(=="animal")`,
+					},
+				},
+			},
+			"animal": {
+				RouterType:      v1alpha1.Sequence,
+				ProtocolVersion: &protocolV2,
+				Steps: []v1alpha1.InferenceStep{
+					{
+						StepName: "classifier",
+						InferenceTarget: v1alpha1.InferenceTarget{
+							ServiceURL: classifier.URL,
+						},
+					},
+					{
+						StepName: "breed",
+						InferenceTarget: v1alpha1.InferenceTarget{
+							ServiceURL: breed.URL,
+						},
+						Data:      "$response.embedding",
+						Condition: `outputs.label.data.#(=="dog")`,
+					},
+				},
+			},
+		},
+	}
+	input := []byte(`{"inputs":[{"name":"kind","shape":[1],"datatype":"BYTES","data":["animal"]}]}`)
+	res, statusCode, err := routeStep(context.Background(), "root", graphSpec, input, http.Header{})
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, "beagle", gjson.GetBytes(res, "outputs.0.data.0").String())
+	assert.Equal(t, []InferTensor{
+		{
+			Name:     "embedding",
+			Shape:    []int64{1, 2},
+			Datatype: "FP32",
+			Data:     json.RawMessage(`[0.1,0.2]`),
+		},
+	}, breedRequest.Inputs)
+}
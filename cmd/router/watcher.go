@@ -0,0 +1,129 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	"github.com/kserve/kserve/pkg/constants"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// defaultGraphName names the graph in the validation of the spec when the router does not know the graph name
+const defaultGraphName = "inference-graph"
+
+// validateGraph runs the InferenceGraph webhook validation on the spec and compiles its conditions and data mappings
+func validateGraph(spec *v1alpha1.InferenceGraphSpec) (*compiledGraph, error) {
+	name := graphName
+	if name == "" {
+		name = defaultGraphName
+	}
+	ig := &v1alpha1.InferenceGraph{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       *spec,
+	}
+	if _, err := ig.ValidateCreate(); err != nil {
+		return nil, err
+	}
+	return compileGraph(spec)
+}
+
+// loadGraph reads, validates and compiles the graph spec file
+func loadGraph(specFile string) (*compiledGraph, error) {
+	data, err := os.ReadFile(specFile)
+	if err != nil {
+		return nil, err
+	}
+	spec := &v1alpha1.InferenceGraphSpec{}
+	if err := json.Unmarshal(data, spec); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshall inference graph spec file %s", specFile)
+	}
+	compiled, err := validateGraph(spec)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid inference graph spec file %s", specFile)
+	}
+	return compiled, nil
+}
+
+// GraphWatcher reloads the graph when the ConfigMap mounted in the config dir changes. Every request is routed
+// through the graph which was current when the request started, so in-flight requests finish on the old graph.
+type GraphWatcher struct {
+	configDir string
+}
+
+func NewGraphWatcher(configDir string) *GraphWatcher {
+	return &GraphWatcher{configDir: configDir}
+}
+
+// Load reads the graph spec file of the config dir and swaps it with the current graph, an invalid spec is
+// rejected and the current graph is kept
+func (w *GraphWatcher) Load() error {
+	specFile := filepath.Join(w.configDir, constants.InferenceGraphSpecFileName)
+	graph, err := loadGraph(specFile)
+	if err != nil {
+		return err
+	}
+	if current := inferenceGraph.Load(); current != nil && reflect.DeepEqual(current.spec, graph.spec) {
+		return nil
+	}
+	inferenceGraph.Store(graph)
+	log.Info("Loaded inference graph", "file", specFile)
+	return nil
+}
+
+// Start watches the config dir until the stop channel is closed. The kubelet updates a mounted ConfigMap by
+// swapping the `..data` symlink of the dir, every swap reloads the graph.
+func (w *GraphWatcher) Start(stop <-chan struct{}) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return errors.Wrapf(err, "failed to create the graph config dir watcher")
+	}
+	defer watcher.Close()
+	if err = watcher.Add(w.configDir); err != nil {
+		return errors.Wrapf(err, "failed to watch graph config dir %s", w.configDir)
+	}
+	log.Info("Watching inference graph config dir", "dir", w.configDir)
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			isCreate := event.Op&fsnotify.Create != 0
+			isDataDir := filepath.Base(filepath.Clean(event.Name)) == "..data"
+			if isDataDir && isCreate {
+				log.Info("Processing graph config event", "event", event.String())
+				if err := w.Load(); err != nil {
+					log.Error(err, "Failed to reload the inference graph, keeping the current graph")
+				}
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Error(err, "graph config dir watcher error")
+		case <-stop:
+			return nil
+		}
+	}
+}
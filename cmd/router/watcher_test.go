@@ -0,0 +1,103 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	"github.com/kserve/kserve/pkg/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+// writeGraphConfig updates the config dir the way the kubelet updates a mounted ConfigMap,
+// the data is written to a new dir and the `..data` symlink is swapped to it
+func writeGraphConfig(t *testing.T, configDir string, version string, spec string) {
+	dataDir := filepath.Join(configDir, "..data_"+version)
+	assert.Nil(t, os.Mkdir(dataDir, 0o755))
+	assert.Nil(t, os.WriteFile(filepath.Join(dataDir, constants.InferenceGraphSpecFileName), []byte(spec), 0o644))
+	tmpLink := filepath.Join(configDir, "..data_tmp")
+	assert.Nil(t, os.Symlink(filepath.Base(dataDir), tmpLink))
+	assert.Nil(t, os.Rename(tmpLink, filepath.Join(configDir, "..data")))
+	specLink := filepath.Join(configDir, constants.InferenceGraphSpecFileName)
+	if _, err := os.Lstat(specLink); os.IsNotExist(err) {
+		assert.Nil(t, os.Symlink(filepath.Join("..data", constants.InferenceGraphSpecFileName), specLink))
+	}
+}
+
+func rootServiceURL() string {
+	return inferenceGraph.Load().spec.Nodes[v1alpha1.GraphRootNodeName].Steps[0].ServiceURL
+}
+
+func TestGraphWatcher(t *testing.T) {
+	defer inferenceGraph.Store(nil)
+	graphSpec := func(serviceURL string) string {
+		return `{"nodes": {"root": {"routerType": "Sequence", "steps": [{"serviceUrl": "` + serviceURL + `"}]}}}`
+	}
+	configDir := t.TempDir()
+	writeGraphConfig(t, configDir, "1", graphSpec("http://model1"))
+
+	watcher := NewGraphWatcher(configDir)
+	assert.Nil(t, watcher.Load())
+	assert.Equal(t, "http://model1", rootServiceURL())
+	// the graph the in-flight requests were started with is not modified by a reload
+	previousGraph := inferenceGraph.Load()
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		assert.Nil(t, watcher.Start(stop))
+	}()
+	// give the watcher time to watch the config dir
+	time.Sleep(100 * time.Millisecond)
+
+	writeGraphConfig(t, configDir, "2", graphSpec("http://model2"))
+	assert.Eventually(t, func() bool {
+		return rootServiceURL() == "http://model2"
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "http://model1", previousGraph.spec.Nodes[v1alpha1.GraphRootNodeName].Steps[0].ServiceURL)
+
+	// invalid graphs are rejected and the current graph is kept
+	writeGraphConfig(t, configDir, "3", `{"nodes": {"model": {"routerType": "Sequence"}}}`)
+	writeGraphConfig(t, configDir, "4", `{"nodes": {"root": {"routerType": "Switch", "steps": [{"serviceUrl": "http://model4", "condition": "input.x == 1"}]}}}`)
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, "http://model2", rootServiceURL())
+
+	writeGraphConfig(t, configDir, "5", graphSpec("http://model5"))
+	assert.Eventually(t, func() bool {
+		return rootServiceURL() == "http://model5"
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestLoadGraph(t *testing.T) {
+	configDir := t.TempDir()
+	specFile := filepath.Join(configDir, constants.InferenceGraphSpecFileName)
+	_, err := loadGraph(specFile)
+	assert.NotNil(t, err)
+
+	assert.Nil(t, os.WriteFile(specFile, []byte(`{"nodes": `), 0o644))
+	_, err = loadGraph(specFile)
+	assert.NotNil(t, err)
+
+	assert.Nil(t, os.WriteFile(specFile, []byte(`{"nodes": {"root": {"routerType": "Splitter", "steps": [`+
+		`{"serviceUrl": "http://model1", "weight": 30}, {"serviceUrl": "http://model2", "weight": 30}]}}}`), 0o644))
+	_, err = loadGraph(specFile)
+	assert.NotNil(t, err)
+}
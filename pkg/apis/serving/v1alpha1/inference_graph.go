@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"github.com/kserve/kserve/pkg/constants"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/pkg/apis"
@@ -50,11 +51,42 @@ type InferenceGraphSpec struct {
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
 	// +optional
 	Affinity *corev1.Affinity `json:"affinity,omitempty" protobuf:"bytes,18,opt,name=affinity"`
+	// Minimum number of replicas of the router, defaults to 1 but can be set to 0 to enable scale-to-zero
+	// in Serverless mode. The `autoscaling.knative.dev/min-scale` annotation of the graph takes precedence over it.
+	// +optional
+	MinReplicas *int `json:"minReplicas,omitempty"`
+	// Maximum number of replicas of the router for autoscaling. The `autoscaling.knative.dev/max-scale` annotation
+	// of the graph takes precedence over it in Serverless mode.
+	// +optional
+	MaxReplicas int `json:"maxReplicas,omitempty"`
+	// ScaleTarget specifies the integer target value of the metric type the Autoscaler watches for.
+	// concurrency and rps targets are supported by Knative Pod Autoscaler, cpu and memory utilization
+	// percentages are supported by the HorizontalPodAutoscaler which scales the router in RawDeployment mode.
+	// The `autoscaling.knative.dev/target` annotation of the graph takes precedence over it in Serverless mode.
+	// +optional
+	ScaleTarget *int `json:"scaleTarget,omitempty"`
+	// ScaleMetric defines the scaling metric type watched by autoscaler
+	// possible values are concurrency, rps, cpu, memory. concurrency, rps are supported via
+	// Knative Pod Autoscaler(https://knative.dev/docs/serving/autoscaling/autoscaling-metrics).
+	// The `autoscaling.knative.dev/metric` annotation of the graph takes precedence over it in Serverless mode.
+	// +optional
+	ScaleMetric *ScaleMetric `json:"scaleMetric,omitempty"`
 }
 
+// ScaleMetric enum
+// +kubebuilder:validation:Enum=cpu;memory;concurrency;rps
+type ScaleMetric string
+
+const (
+	MetricCPU         ScaleMetric = "cpu"
+	MetricMemory      ScaleMetric = "memory"
+	MetricConcurrency ScaleMetric = "concurrency"
+	MetricRPS         ScaleMetric = "rps"
+)
+
 // InferenceRouterType constant for inference routing types
 // +k8s:openapi-gen=true
-// +kubebuilder:validation:Enum=Sequence;Splitter;Ensemble;Switch
+// +kubebuilder:validation:Enum=Sequence;Splitter;Ensemble;Switch;ForEach
 type InferenceRouterType string
 
 // InferenceRouterType Enum
@@ -70,6 +102,9 @@ const (
 
 	// Switch routes the request to the model based on certain condition
 	Switch InferenceRouterType = "Switch"
+
+	// ForEach routes every element of an array in the request to its step and gathers the responses in order
+	ForEach InferenceRouterType = "ForEach"
 )
 
 const (
@@ -112,9 +147,9 @@ const (
 //	    routerType: Switch
 //	    routes:
 //	    - service: mymodel1
-//	      condition: "{ .input.userId == 1 }"
+//	      condition: "body.userId == 1"
 //	    - service: mymodel2
-//	      condition: "{ .input.userId == 2 }"
+//	      condition: "body.userId == 2 || headers['x-user-group'] == 'beta'"
 //
 // ```
 //
@@ -142,6 +177,9 @@ const (
 //	      data: $response
 //	  ensembleModel:
 //	    routerType: Ensemble
+//	    combiner:
+//	      type: MajorityVote
+//	      path: predictions
 //	    routes:
 //	    - service: sklearn-model
 //	    - service: xgboost-model
@@ -192,9 +230,9 @@ const (
 //	    routerType: Switch
 //	    routes:
 //	    - service: dog-breed-classifier
-//	      condition: { .predictions.class == "dog" }
+//	      condition: body.predictions.class == "dog"
 //	    - service: cat-breed-classifier
-//	      condition: { .predictions.class == "cat" }
+//	      condition: body.predictions.class == "cat"
 //
 // ```
 type InferenceRouter struct {
@@ -208,11 +246,143 @@ type InferenceRouter struct {
 	//
 	// - `Switch:` routes the request to one of the steps based on condition
 	//
+	// - `ForEach:` routes every element of an array in the request to the step and gathers the responses in order
+	//
 	RouterType InferenceRouterType `json:"routerType"`
 
 	// Steps defines destinations for the current router node
 	// +optional
 	Steps []InferenceStep `json:"steps,omitempty"`
+
+	// ProtocolVersion is the inference protocol spoken by the steps of this node, `v1` or `v2`, defaults to `v1`.
+	// For `v2` nodes conditions are evaluated against the tensors of the request or response, addressed by name
+	// e.g `outputs.label.data.#(=="dog")`, and `$response` passes the output tensors of the previous step as inputs.
+	// +optional
+	ProtocolVersion *constants.InferenceServiceProtocol `json:"protocolVersion,omitempty"`
+
+	// Combiner combines the step responses of an Ensemble node into a single prediction returned as
+	// `{"predictions": <combined>}`. Without a combiner the step responses are returned keyed by step name.
+	// +optional
+	Combiner *EnsembleCombiner `json:"combiner,omitempty"`
+
+	// HashKey makes the routing of a Splitter node sticky, requests with the same key value are always routed to
+	// the same step. Requests without the key are routed randomly.
+	// +optional
+	HashKey *SplitterHashKey `json:"hashKey,omitempty"`
+
+	// Seed of the random routing of a Splitter node, a fixed seed makes the sequence of routes reproducible
+	// +optional
+	Seed *int64 `json:"seed,omitempty"`
+
+	// FailurePolicy decides whether an Ensemble node succeeds when some of its steps fail, by default at least one step
+	// must succeed. Failed steps are reported in the `errors` section of the node response and a failed Hard step
+	// always fails the node.
+	// +optional
+	FailurePolicy *EnsembleFailurePolicy `json:"failurePolicy,omitempty"`
+
+	// ForEach selects the array a ForEach node iterates over, required by ForEach nodes.
+	// The responses of the elements are returned in order as `{"predictions": [<response>, ...]}`, failed elements are
+	// null and reported by index in the `errors` section of the response, a failed Hard step fails the node.
+	// +optional
+	ForEach *ForEachOptions `json:"forEach,omitempty"`
+}
+
+// ForEachOptions defines how a ForEach node splits its request, its single step is called once per array element.
+// The element is the request of the step, or `$response` in the step data, e.g `{"instances": ["$response"]}`.
+// +k8s:openapi-gen=true
+type ForEachOptions struct {
+	// Path of the json array in the request, e.g `instances`
+	Path string `json:"path"`
+
+	// Maximum number of elements processed in parallel, defaults to 10
+	// +optional
+	Parallelism *int32 `json:"parallelism,omitempty"`
+
+	// Path of the result in every step response e.g `predictions.0`, defaults to the whole response
+	// +optional
+	ResponsePath string `json:"responsePath,omitempty"`
+}
+
+// EnsembleFailurePolicyType constant for the partial failure policies of an Ensemble node
+// +k8s:openapi-gen=true
+// +kubebuilder:validation:Enum=All;Quorum;BestEffort
+type EnsembleFailurePolicyType string
+
+// EnsembleFailurePolicyType Enum
+const (
+	// All fails the node as soon as any step fails
+	All EnsembleFailurePolicyType = "All"
+
+	// Quorum fails the node as soon as fewer than the quorum of steps can succeed
+	Quorum EnsembleFailurePolicyType = "Quorum"
+
+	// BestEffort returns the successful step responses even when all the steps fail, the predictions of a node with
+	// a combiner are null when no step succeeds
+	BestEffort EnsembleFailurePolicyType = "BestEffort"
+)
+
+// EnsembleFailurePolicy defines how an Ensemble node handles failed steps.
+// A step fails when it returns an error, a non 2xx status code or a response without the combiner path.
+// +k8s:openapi-gen=true
+type EnsembleFailurePolicy struct {
+	// Type of the failure policy
+	Type EnsembleFailurePolicyType `json:"type"`
+
+	// Minimum number of steps which must succeed, required by the Quorum policy
+	// +optional
+	Quorum *int32 `json:"quorum,omitempty"`
+}
+
+// SplitterHashKey selects the request value Splitter nodes hash to pick a step, exactly one field must be specified
+// +k8s:openapi-gen=true
+type SplitterHashKey struct {
+	// Name of the request header to hash, e.g a user id header
+	// +optional
+	Header string `json:"header,omitempty"`
+
+	// Path of the json field in the request body to hash, e.g `instances.0.userId`
+	// +optional
+	Field string `json:"field,omitempty"`
+}
+
+// EnsembleCombinerType constant for the methods combining the step responses of an Ensemble node
+// +k8s:openapi-gen=true
+// +kubebuilder:validation:Enum=MajorityVote;Mean;WeightedMean;Max;Min;FirstSuccess
+type EnsembleCombinerType string
+
+// EnsembleCombinerType Enum
+const (
+	// MajorityVote returns the most frequent prediction, ties are broken by step order
+	MajorityVote EnsembleCombinerType = "MajorityVote"
+
+	// Mean returns the average of the predictions
+	Mean EnsembleCombinerType = "Mean"
+
+	// WeightedMean returns the average of the predictions weighted by the step weights
+	WeightedMean EnsembleCombinerType = "WeightedMean"
+
+	// Max returns the largest prediction
+	Max EnsembleCombinerType = "Max"
+
+	// Min returns the smallest prediction
+	Min EnsembleCombinerType = "Min"
+
+	// FirstSuccess returns the response of the first step which succeeds and cancels the other steps
+	FirstSuccess EnsembleCombinerType = "FirstSuccess"
+)
+
+// EnsembleCombiner defines how the step responses of an Ensemble node are combined.
+// Only successful step responses are combined.
+// +k8s:openapi-gen=true
+type EnsembleCombiner struct {
+	// Type of the combination method
+	Type EnsembleCombinerType `json:"type"`
+
+	// Path of the prediction in every step response e.g `predictions`, required by all combiners except FirstSuccess.
+	// When the path selects arrays, e.g the predictions of a batch, they are combined element wise.
+	// A step whose response does not have the path is failed under the failure policy of the node.
+	// +optional
+	Path string `json:"path,omitempty"`
 }
 
 // +k8s:openapi-gen=true
@@ -254,24 +424,172 @@ type InferenceStep struct {
 	// Node or service used to process this step
 	InferenceTarget `json:",inline"`
 
-	// request data sent to the next route with input/output from the previous step
-	// $request
-	// $response.predictions
+	// request data sent to the next route with input/output from the previous step, only the steps of Sequence and
+	// ForEach nodes can have data.
+	// Data is either a single reference or a json template in which strings starting with `$` are references and all
+	// other values are constants. `$request` refers to the request of the node, `$response` to the response of the
+	// previous step and `$steps.<name>` to the response of an earlier step in a Sequence node, each optionally
+	// followed by a json path, e.g `{"instances": "$steps.preprocess.predictions", "threshold": 0.5}`.
+	// Strings starting with `$$` are constants starting with `$`.
+	// For v2 nodes `$response.<name>` sends only the named output tensor of the previous step as input
 	// +optional
 	Data string `json:"data,omitempty"`
 
 	// the weight for split of the traffic, only used for Split Router
 	// when weight is specified all the routing targets should be sum to 100
+	// For Ensemble nodes with a WeightedMean combiner it is the weight of the step prediction
 	// +optional
 	Weight *int64 `json:"weight,omitempty"`
 
-	// routing based on the condition
+	// routing based on the condition, a CEL expression evaluating to a bool over the json `body` of the request
+	// (Switch) or previous step response (Sequence) and the request `headers` keyed by lower case name,
+	// e.g `body.instances[0].userId == 1 && headers["x-tenant"] == "gold"`.
+	// Conditions which are not CEL expressions are gjson paths which match when the path exists.
 	// +optional
 	Condition string `json:"condition,omitempty"`
 
 	// to decide whether a step is a hard or a soft dependency in the Inference Graph
 	// +optional
 	Dependency InferenceStepDependencyType `json:"dependency,omitempty"`
+
+	// TimeoutSeconds specifies the number of seconds to wait for the step service to respond before
+	// cancelling the call. When a retry policy is set the timeout applies to every attempt. On a step
+	// targeting a node the timeout bounds the routing of the whole node. A response streamed back to the
+	// client is not bounded by the timeout once the step service has started to respond.
+	// +optional
+	TimeoutSeconds *int64 `json:"timeout,omitempty"`
+
+	// RetryPolicy specifies how the router retries a failed call to the step service
+	// +optional
+	RetryPolicy *StepRetryPolicy `json:"retry,omitempty"`
+
+	// CircuitBreaker stops the router from calling the step service for a while after consecutive failures
+	// +optional
+	CircuitBreaker *StepCircuitBreaker `json:"circuitBreaker,omitempty"`
+
+	// Cache serves the responses of the step from an in memory cache of the router, requests with the same body
+	// and cache headers as an earlier request are not sent to the step target again until the response expires
+	// +optional
+	Cache *StepCache `json:"cache,omitempty"`
+
+	// Mirror sends a copy of the step request to a shadow target, the shadow response is only logged and
+	// reported in the router metrics and never affects the response of the step
+	// +optional
+	Mirror *StepMirror `json:"mirror,omitempty"`
+
+	// Auth sets the credentials the router sends to the step service, the reconciler mounts the
+	// ServiceAccount tokens and secrets the router needs in the router pod
+	// +optional
+	Auth *StepAuth `json:"auth,omitempty"`
+
+	// ProtocolVersion of the step service, `grpc-v2` calls the service over the v2 gRPC inference protocol
+	// instead of http. A `grpc://` or `grpcs://` serviceUrl also selects gRPC. gRPC steps require a `v2` node.
+	// +optional
+	ProtocolVersion *constants.InferenceServiceProtocol `json:"protocolVersion,omitempty"`
+}
+
+// StepRetryPolicy defines how the router retries a failed call to an inference step.
+// Connection errors and timeouts are always retried.
+// +k8s:openapi-gen=true
+type StepRetryPolicy struct {
+	// Maximum number of attempts including the first call, defaults to 1 and at most 10
+	// +optional
+	MaxAttempts *int32 `json:"maxAttempts,omitempty"`
+
+	// Backoff in milliseconds before the first retry, doubled for every further retry up to 60 seconds.
+	// Defaults to 100 and at most 60000
+	// +optional
+	Backoff *int64 `json:"backoff,omitempty"`
+
+	// Response status codes which are retried, defaults to 502, 503 and 504
+	// +optional
+	RetryableStatusCodes []int32 `json:"retryableStatusCodes,omitempty"`
+}
+
+// StepCircuitBreaker defines when the router stops calling an inference step after consecutive failures.
+// A call fails when it returns an error or a 5xx status code. Steps calling the same service url with the same settings
+// share the circuit.
+// +k8s:openapi-gen=true
+type StepCircuitBreaker struct {
+	// Number of consecutive failed calls that opens the circuit, defaults to 5
+	// +optional
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
+
+	// Number of seconds the circuit stays open before a single trial call is let through, defaults to 30
+	// +optional
+	ResetTimeoutSeconds *int64 `json:"resetTimeout,omitempty"`
+}
+
+// StepCache defines how the router caches the responses of an inference step.
+// Only successful responses are cached, the cache of every step is bounded and evicts the least recently used response.
+// +k8s:openapi-gen=true
+type StepCache struct {
+	// Number of seconds a cached response is served, defaults to 60
+	// +optional
+	TTLSeconds *int64 `json:"ttl,omitempty"`
+
+	// Maximum number of responses cached for the step, defaults to 1000
+	// +optional
+	MaxEntries *int32 `json:"maxEntries,omitempty"`
+
+	// Request headers which are part of the cache key in addition to the request body
+	// +optional
+	Headers []string `json:"headers,omitempty"`
+}
+
+// StepMirror defines the shadow target a copy of the step request is sent to.
+// The copy is sent asynchronously, the shadow response, latency and errors are logged and reported in the metrics.
+// +k8s:openapi-gen=true
+type StepMirror struct {
+	// Node or service the copy of the request is sent to
+	InferenceTarget `json:",inline"`
+
+	// Percentage of the step requests which are mirrored, defaults to 100
+	// +optional
+	Percent *int64 `json:"percent,omitempty"`
+
+	// Auth sets the credentials the router sends to the shadow service. The auth of the step is not used
+	// for the shadow target, the copy of the request is sent without credentials when it is not set
+	// +optional
+	Auth *StepAuth `json:"auth,omitempty"`
+}
+
+// StepAuthType is the type of credentials the router sends to an inference step
+// +k8s:openapi-gen=true
+// +kubebuilder:validation:Enum=ForwardToken;ServiceAccountToken;SecretHeader
+type StepAuthType string
+
+// StepAuthType Enum
+const (
+	// ForwardToken forwards the Authorization header of the caller
+	ForwardToken StepAuthType = "ForwardToken"
+
+	// ServiceAccountToken sends a projected ServiceAccount token of the router pod as a bearer token
+	ServiceAccountToken StepAuthType = "ServiceAccountToken"
+
+	// SecretHeader sends a header whose value is taken from a Secret
+	SecretHeader StepAuthType = "SecretHeader"
+)
+
+// StepAuth defines the credentials the router sends to an inference step.
+// The credentials replace the headers with the same name propagated to the step.
+// +k8s:openapi-gen=true
+type StepAuth struct {
+	// Type of the credentials
+	Type StepAuthType `json:"type"`
+
+	// Audience of the projected ServiceAccount token, defaults to the audience of the API server.
+	// Only for the ServiceAccountToken type
+	// +optional
+	Audience string `json:"audience,omitempty"`
+
+	// Name of the header set from the Secret, defaults to Authorization. Only for the SecretHeader type
+	// +optional
+	Header string `json:"header,omitempty"`
+
+	// Key of the Secret in the namespace of the graph holding the header value. Only for the SecretHeader type
+	// +optional
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
 }
 
 // InferenceGraphStatus defines the InferenceGraph conditions and status
@@ -282,6 +600,30 @@ type InferenceGraphStatus struct {
 	// Url for the InferenceGraph
 	// +optional
 	URL *apis.URL `json:"url,omitempty"`
+	// Status of the InferenceServices referenced by the steps of the graph
+	// +optional
+	// +listType=map
+	// +listMapKey=serviceName
+	Services []InferenceGraphServiceStatus `json:"services,omitempty"`
+}
+
+// InferenceGraphServiceStatus is the status of an InferenceService referenced by the steps of the graph
+// +k8s:openapi-gen=true
+type InferenceGraphServiceStatus struct {
+	// Name of the InferenceService
+	ServiceName string `json:"serviceName"`
+	// URL of the InferenceService called by the router
+	// +optional
+	URL *apis.URL `json:"url,omitempty"`
+	// Ready is true when the InferenceService is ready
+	Ready bool `json:"ready"`
+	// Hard is true when a step with a Hard dependency calls the InferenceService, the graph is not ready
+	// until the InferenceService is ready
+	// +optional
+	Hard bool `json:"hard,omitempty"`
+	// Last time the readiness of the InferenceService changed
+	// +optional
+	LastTransitionTime apis.VolatileTime `json:"lastTransitionTime,omitempty"`
 }
 
 // InferenceGraphList contains a list of InferenceGraph
@@ -18,10 +18,15 @@ package v1alpha1
 
 import (
 	"fmt"
+	"github.com/kserve/kserve/pkg/constants"
+	"github.com/kserve/kserve/pkg/inferencegraph/condition"
+	"github.com/kserve/kserve/pkg/inferencegraph/mapping"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"regexp"
+	"sort"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -43,11 +48,73 @@ const (
 	TargetNotProvidedError = "Step %d (\"%s\") in node \"%s\" of InferenceGraph \"%s\" does not specify an inference target"
 	// InvalidTargetError defines the error message for inference graph target specifies more than one of nodeName, serviceName, serviceUrl
 	InvalidTargetError = "Step %d (\"%s\") in node \"%s\" of InferenceGraph \"%s\" specifies more than one of nodeName, serviceName, serviceUrl"
+	// InvalidStepTimeoutError defines the error message for a step timeout which is not positive
+	InvalidStepTimeoutError = "Step %d (\"%s\") in node \"%s\" of InferenceGraph \"%s\" must have a timeout greater than 0"
+	// InvalidRetryPolicyError defines the error message for a step retry policy with invalid values
+	InvalidRetryPolicyError = "Step %d (\"%s\") in node \"%s\" of InferenceGraph \"%s\" has an invalid retry policy: %s"
+	// InvalidCircuitBreakerError defines the error message for a step circuit breaker with invalid values
+	InvalidCircuitBreakerError = "Step %d (\"%s\") in node \"%s\" of InferenceGraph \"%s\" has an invalid circuit breaker: %s"
+	// InvalidStepCacheError defines the error message for a step cache with invalid values
+	InvalidStepCacheError = "Step %d (\"%s\") in node \"%s\" of InferenceGraph \"%s\" has an invalid cache: %s"
+	// InvalidStepMirrorError defines the error message for a step mirror with an invalid target or percentage
+	InvalidStepMirrorError = "Step %d (\"%s\") in node \"%s\" of InferenceGraph \"%s\" has an invalid mirror: %s"
+	// InvalidStepAuthError defines the error message for step auth with missing or unexpected options
+	InvalidStepAuthError = "Step %d (\"%s\") in node \"%s\" of InferenceGraph \"%s\" has invalid auth: %s"
+	// InvalidNodeProtocolError defines the error message for a node protocol version which is not supported by the router
+	InvalidNodeProtocolError = "Node \"%s\" of InferenceGraph \"%s\" has unsupported protocol version \"%s\", the router supports v1 and v2"
+	// InvalidStepProtocolError defines the error message for a step protocol version which is not supported by the router
+	InvalidStepProtocolError = "Step %d (\"%s\") in node \"%s\" of InferenceGraph \"%s\" has unsupported protocol version \"%s\", the router supports v1, v2 and grpc-v2"
+	// GRPCStepNodeProtocolError defines the error message for a gRPC step in a node which does not speak the v2 protocol
+	GRPCStepNodeProtocolError = "Step %d (\"%s\") in node \"%s\" of InferenceGraph \"%s\" calls a gRPC service, the node protocol version must be v2"
+	// CombinerNotEnsembleError defines the error message for a combiner set on a node which is not an Ensemble
+	CombinerNotEnsembleError = "Node \"%s\" of InferenceGraph \"%s\" is not an Ensemble node, only Ensemble nodes can have a combiner"
+	// CombinerPathNotProvidedError defines the error message for a combiner without the path of the prediction
+	CombinerPathNotProvidedError = "Node \"%s\" of InferenceGraph \"%s\" has a %s combiner without the path of the prediction"
+	// CombinerWeightError defines the error message for a step of a WeightedMean combiner with a missing or negative weight
+	CombinerWeightError = "Step %d (\"%s\") in node \"%s\" of InferenceGraph \"%s\" must have a weight greater than or equal to 0 for the WeightedMean combiner"
+	// FailurePolicyNotEnsembleError defines the error message for a failure policy set on a node which is not an Ensemble
+	FailurePolicyNotEnsembleError = "Node \"%s\" of InferenceGraph \"%s\" is not an Ensemble node, only Ensemble nodes can have a failure policy"
+	// InvalidQuorumError defines the error message for a Quorum failure policy with an invalid quorum
+	InvalidQuorumError = "Node \"%s\" of InferenceGraph \"%s\" has an invalid failure policy: %s"
+	// InvalidConditionError defines the error message for a step condition which does not compile
+	InvalidConditionError = "Step %d (\"%s\") in node \"%s\" of InferenceGraph \"%s\" has an invalid condition: %v"
+	// InvalidDataError defines the error message for step data which does not compile
+	InvalidDataError = "Step %d (\"%s\") in node \"%s\" of InferenceGraph \"%s\" has invalid data: %v"
+	// DataNotSupportedError defines the error message for step data set on a node which does not send it to the step
+	DataNotSupportedError = "Step %d (\"%s\") in node \"%s\" of InferenceGraph \"%s\" has data, only the steps of Sequence and ForEach nodes can have data"
+	// DataStepReferenceError defines the error message for step data referencing a step which does not run before it
+	DataStepReferenceError = "Step %d (\"%s\") in node \"%s\" of InferenceGraph \"%s\" references step \"%s\" which is not an earlier step of a Sequence node"
+	// SplitterOptionNotSplitterError defines the error message for a hash key or seed set on a node which is not a Splitter
+	SplitterOptionNotSplitterError = "Node \"%s\" of InferenceGraph \"%s\" is not a Splitter node, only Splitter nodes can have a hashKey or seed"
+	// InvalidHashKeyError defines the error message for a hash key which does not specify exactly one of header and field
+	InvalidHashKeyError = "Node \"%s\" of InferenceGraph \"%s\" has an invalid hashKey, exactly one of header and field must be specified"
+	// NodeStepPolicyError defines the error message for retry or circuit breaker set on a step which targets a node
+	NodeStepPolicyError = "Step %d (\"%s\") in node \"%s\" of InferenceGraph \"%s\" targets a node, retry and circuit breaker can only be set on steps calling a service"
+	// ForEachOptionNotForEachError defines the error message for forEach options set on a node which is not a ForEach node
+	ForEachOptionNotForEachError = "Node \"%s\" of InferenceGraph \"%s\" is not a ForEach node, only ForEach nodes can have forEach options"
+	// InvalidForEachError defines the error message for a ForEach node with missing or invalid options
+	InvalidForEachError = "Node \"%s\" of InferenceGraph \"%s\" has invalid forEach options: %s"
+	// InvalidReplicasError defines the error message for invalid router replicas
+	InvalidReplicasError = "InferenceGraph \"%s\" has invalid replicas: %s"
+	// DanglingNodeReferenceError defines the error message for a step targeting a node which does not exist
+	DanglingNodeReferenceError = "Step %d (\"%s\") in node \"%s\" of InferenceGraph \"%s\" targets node \"%s\" which does not exist"
+	// GraphCycleError defines the error message for nodes which call each other
+	GraphCycleError = "InferenceGraph \"%s\" has a cycle: %s"
+	// UnreachableNodeError defines the error message for a node which is not called from the root node
+	UnreachableNodeError = "Node \"%s\" of InferenceGraph \"%s\" is not reachable from the root node"
+	// GraphDepthError defines the error message for a graph nesting more nodes than MaxGraphDepth
+	GraphDepthError = "InferenceGraph \"%s\" nests more than %d nodes: %s"
 )
 
 const (
 	// GraphNameFmt regular expressions for validation of isvc name
 	GraphNameFmt string = "[a-z]([-a-z0-9]*[a-z0-9])?"
+	// MaxGraphDepth is the maximum number of nodes on a path from the root node of a graph
+	MaxGraphDepth = 10
+	// MaxStepRetryAttempts is the maximum number of attempts of a step retry policy
+	MaxStepRetryAttempts = 10
+	// MaxStepRetryBackoffMillis is the maximum backoff in milliseconds between two attempts of a step
+	MaxStepRetryBackoffMillis = 60000
 )
 
 var (
@@ -73,6 +140,10 @@ func (ig *InferenceGraph) ValidateCreate() (admission.Warnings, error) {
 		return nil, err
 	}
 
+	if err := validateInferenceGraphReplicas(ig); err != nil {
+		return nil, err
+	}
+
 	if err := validateInferenceGraphStepNameUniqueness(ig); err != nil {
 		return nil, err
 	}
@@ -84,6 +155,50 @@ func (ig *InferenceGraph) ValidateCreate() (admission.Warnings, error) {
 	if err := validateInferenceGraphSplitterWeight(ig); err != nil {
 		return nil, err
 	}
+
+	if err := validateInferenceGraphSplitterRouting(ig); err != nil {
+		return nil, err
+	}
+
+	if err := validateInferenceGraphStepPolicies(ig); err != nil {
+		return nil, err
+	}
+
+	if err := validateInferenceGraphStepMirrors(ig); err != nil {
+		return nil, err
+	}
+
+	if err := validateInferenceGraphStepAuth(ig); err != nil {
+		return nil, err
+	}
+
+	if err := validateInferenceGraphProtocolVersion(ig); err != nil {
+		return nil, err
+	}
+
+	if err := validateInferenceGraphEnsembleCombiner(ig); err != nil {
+		return nil, err
+	}
+
+	if err := validateInferenceGraphEnsembleFailurePolicy(ig); err != nil {
+		return nil, err
+	}
+
+	if err := validateInferenceGraphForEach(ig); err != nil {
+		return nil, err
+	}
+
+	if err := validateInferenceGraphConditions(ig); err != nil {
+		return nil, err
+	}
+
+	if err := validateInferenceGraphStepData(ig); err != nil {
+		return nil, err
+	}
+
+	if err := validateInferenceGraphStructure(ig); err != nil {
+		return nil, err
+	}
 	return nil, nil
 }
 
@@ -164,6 +279,27 @@ func validateInferenceGraphRouterRoot(ig *InferenceGraph) error {
 	return fmt.Errorf(RootNodeNotFoundError)
 }
 
+// Validation of the router replicas
+func validateInferenceGraphReplicas(ig *InferenceGraph) error {
+	minReplicas := constants.DefaultMinReplicas
+	if ig.Spec.MinReplicas != nil {
+		minReplicas = *ig.Spec.MinReplicas
+	}
+	if minReplicas < 0 {
+		return fmt.Errorf(InvalidReplicasError, ig.Name, "minReplicas cannot be less than 0")
+	}
+	if ig.Spec.MaxReplicas < 0 {
+		return fmt.Errorf(InvalidReplicasError, ig.Name, "maxReplicas cannot be less than 0")
+	}
+	if ig.Spec.MaxReplicas != 0 && minReplicas > ig.Spec.MaxReplicas {
+		return fmt.Errorf(InvalidReplicasError, ig.Name, "minReplicas cannot be greater than maxReplicas")
+	}
+	if ig.Spec.ScaleTarget != nil && *ig.Spec.ScaleTarget < 1 {
+		return fmt.Errorf(InvalidReplicasError, ig.Name, "scaleTarget must be at least 1")
+	}
+	return nil
+}
+
 // Validation of inference graph router type
 func validateInferenceGraphSplitterWeight(ig *InferenceGraph) error {
 	nodes := ig.Spec.Nodes
@@ -172,7 +308,7 @@ func validateInferenceGraphSplitterWeight(ig *InferenceGraph) error {
 		if node.RouterType == Splitter {
 			for _, route := range node.Steps {
 				if route.Weight == nil {
-					return fmt.Errorf(WeightNotProvidedError, ig.Name, name, route.ServiceName)
+					return fmt.Errorf(WeightNotProvidedError, ig.Name, name, stepTarget(route))
 				}
 				weight += int(*route.Weight)
 			}
@@ -183,3 +319,390 @@ func validateInferenceGraphSplitterWeight(ig *InferenceGraph) error {
 	}
 	return nil
 }
+
+// stepTarget returns the node name, service name or service url targeted by the step
+func stepTarget(step InferenceStep) string {
+	switch {
+	case step.NodeName != "":
+		return step.NodeName
+	case step.ServiceName != "":
+		return step.ServiceName
+	}
+	return step.ServiceURL
+}
+
+// Validation of splitter hash key and seed
+func validateInferenceGraphSplitterRouting(ig *InferenceGraph) error {
+	for nodeName, node := range ig.Spec.Nodes {
+		if node.HashKey == nil && node.Seed == nil {
+			continue
+		}
+		if node.RouterType != Splitter {
+			return fmt.Errorf(SplitterOptionNotSplitterError, nodeName, ig.Name)
+		}
+		if hashKey := node.HashKey; hashKey != nil && (hashKey.Header == "") == (hashKey.Field == "") {
+			return fmt.Errorf(InvalidHashKeyError, nodeName, ig.Name)
+		}
+	}
+	return nil
+}
+
+// Validation of step timeout, retry policy, circuit breaker and cache
+func validateInferenceGraphStepPolicies(ig *InferenceGraph) error {
+	nodes := ig.Spec.Nodes
+	for nodeName, node := range nodes {
+		for i, step := range node.Steps {
+			if step.TimeoutSeconds != nil && *step.TimeoutSeconds <= 0 {
+				return fmt.Errorf(InvalidStepTimeoutError, i, step.StepName, nodeName, ig.Name)
+			}
+			if step.NodeName != "" && (step.RetryPolicy != nil || step.CircuitBreaker != nil) {
+				return fmt.Errorf(NodeStepPolicyError, i, step.StepName, nodeName, ig.Name)
+			}
+			if retry := step.RetryPolicy; retry != nil {
+				if retry.MaxAttempts != nil && *retry.MaxAttempts < 1 {
+					return fmt.Errorf(InvalidRetryPolicyError, i, step.StepName, nodeName, ig.Name, "maxAttempts must be at least 1")
+				}
+				if retry.MaxAttempts != nil && *retry.MaxAttempts > MaxStepRetryAttempts {
+					return fmt.Errorf(InvalidRetryPolicyError, i, step.StepName, nodeName, ig.Name,
+						fmt.Sprintf("maxAttempts must be at most %d", MaxStepRetryAttempts))
+				}
+				if retry.Backoff != nil && *retry.Backoff < 0 {
+					return fmt.Errorf(InvalidRetryPolicyError, i, step.StepName, nodeName, ig.Name, "backoff must not be negative")
+				}
+				if retry.Backoff != nil && *retry.Backoff > MaxStepRetryBackoffMillis {
+					return fmt.Errorf(InvalidRetryPolicyError, i, step.StepName, nodeName, ig.Name,
+						fmt.Sprintf("backoff must be at most %d", MaxStepRetryBackoffMillis))
+				}
+				for _, code := range retry.RetryableStatusCodes {
+					if code < 100 || code > 599 {
+						return fmt.Errorf(InvalidRetryPolicyError, i, step.StepName, nodeName, ig.Name,
+							fmt.Sprintf("%d is not a valid status code", code))
+					}
+				}
+			}
+			if breaker := step.CircuitBreaker; breaker != nil {
+				if breaker.FailureThreshold != nil && *breaker.FailureThreshold < 1 {
+					return fmt.Errorf(InvalidCircuitBreakerError, i, step.StepName, nodeName, ig.Name, "failureThreshold must be at least 1")
+				}
+				if breaker.ResetTimeoutSeconds != nil && *breaker.ResetTimeoutSeconds <= 0 {
+					return fmt.Errorf(InvalidCircuitBreakerError, i, step.StepName, nodeName, ig.Name, "resetTimeout must be greater than 0")
+				}
+			}
+			if cache := step.Cache; cache != nil {
+				if cache.TTLSeconds != nil && *cache.TTLSeconds <= 0 {
+					return fmt.Errorf(InvalidStepCacheError, i, step.StepName, nodeName, ig.Name, "ttl must be greater than 0")
+				}
+				if cache.MaxEntries != nil && *cache.MaxEntries < 1 {
+					return fmt.Errorf(InvalidStepCacheError, i, step.StepName, nodeName, ig.Name, "maxEntries must be at least 1")
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Validation of step mirror targets and percentages
+func validateInferenceGraphStepMirrors(ig *InferenceGraph) error {
+	for nodeName, node := range ig.Spec.Nodes {
+		for i, step := range node.Steps {
+			mirror := step.Mirror
+			if mirror == nil {
+				continue
+			}
+			targets := 0
+			for _, target := range []string{mirror.NodeName, mirror.ServiceName, mirror.ServiceURL} {
+				if target != "" {
+					targets++
+				}
+			}
+			if targets != 1 {
+				return fmt.Errorf(InvalidStepMirrorError, i, step.StepName, nodeName, ig.Name,
+					"exactly one of nodeName, serviceName, serviceUrl must be specified")
+			}
+			if _, ok := ig.Spec.Nodes[mirror.NodeName]; mirror.NodeName != "" && !ok {
+				return fmt.Errorf(InvalidStepMirrorError, i, step.StepName, nodeName, ig.Name,
+					fmt.Sprintf("node \"%s\" does not exist", mirror.NodeName))
+			}
+			if mirror.Percent != nil && (*mirror.Percent < 0 || *mirror.Percent > 100) {
+				return fmt.Errorf(InvalidStepMirrorError, i, step.StepName, nodeName, ig.Name, "percent must be between 0 and 100")
+			}
+		}
+	}
+	return nil
+}
+
+// Validation of the step and mirror auth options of every auth type
+func validateInferenceGraphStepAuth(ig *InferenceGraph) error {
+	for nodeName, node := range ig.Spec.Nodes {
+		for i, step := range node.Steps {
+			if step.Auth != nil {
+				if step.NodeName != "" {
+					return fmt.Errorf(InvalidStepAuthError, i, step.StepName, nodeName, ig.Name, "auth can only be set on steps calling a service")
+				}
+				if reason := stepAuthError(step.Auth); reason != "" {
+					return fmt.Errorf(InvalidStepAuthError, i, step.StepName, nodeName, ig.Name, reason)
+				}
+			}
+			if mirror := step.Mirror; mirror != nil && mirror.Auth != nil {
+				if mirror.NodeName != "" {
+					return fmt.Errorf(InvalidStepMirrorError, i, step.StepName, nodeName, ig.Name, "auth can only be set on mirrors calling a service")
+				}
+				if reason := stepAuthError(mirror.Auth); reason != "" {
+					return fmt.Errorf(InvalidStepMirrorError, i, step.StepName, nodeName, ig.Name, "invalid auth: "+reason)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// stepAuthError returns why the auth options are invalid, empty when they are valid
+func stepAuthError(auth *StepAuth) string {
+	if auth.Type != ServiceAccountToken && auth.Audience != "" {
+		return "audience is only supported by ServiceAccountToken"
+	}
+	if auth.Type != SecretHeader && (auth.Header != "" || auth.SecretKeyRef != nil) {
+		return "header and secretKeyRef are only supported by SecretHeader"
+	}
+	switch auth.Type {
+	case ForwardToken, ServiceAccountToken:
+	case SecretHeader:
+		if auth.SecretKeyRef == nil || auth.SecretKeyRef.Name == "" || auth.SecretKeyRef.Key == "" {
+			return "SecretHeader requires the name and key of the secretKeyRef"
+		}
+	default:
+		return fmt.Sprintf("unsupported type \"%s\"", auth.Type)
+	}
+	return ""
+}
+
+// Validation of node and step protocol versions
+func validateInferenceGraphProtocolVersion(ig *InferenceGraph) error {
+	for nodeName, node := range ig.Spec.Nodes {
+		if node.ProtocolVersion == nil {
+			continue
+		}
+		switch *node.ProtocolVersion {
+		case constants.ProtocolV1, constants.ProtocolV2:
+		default:
+			return fmt.Errorf(InvalidNodeProtocolError, nodeName, ig.Name, *node.ProtocolVersion)
+		}
+	}
+	for nodeName, node := range ig.Spec.Nodes {
+		for i, step := range node.Steps {
+			isGRPC := strings.HasPrefix(step.ServiceURL, "grpc://") || strings.HasPrefix(step.ServiceURL, "grpcs://")
+			if step.ProtocolVersion != nil {
+				switch *step.ProtocolVersion {
+				case constants.ProtocolV1, constants.ProtocolV2:
+				case constants.ProtocolGRPCV2:
+					isGRPC = true
+				default:
+					return fmt.Errorf(InvalidStepProtocolError, i, step.StepName, nodeName, ig.Name, *step.ProtocolVersion)
+				}
+			}
+			if isGRPC && (node.ProtocolVersion == nil || *node.ProtocolVersion != constants.ProtocolV2) {
+				return fmt.Errorf(GRPCStepNodeProtocolError, i, step.StepName, nodeName, ig.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// Validation of ensemble combiners
+func validateInferenceGraphEnsembleCombiner(ig *InferenceGraph) error {
+	for nodeName, node := range ig.Spec.Nodes {
+		combiner := node.Combiner
+		if combiner == nil {
+			continue
+		}
+		if node.RouterType != Ensemble {
+			return fmt.Errorf(CombinerNotEnsembleError, nodeName, ig.Name)
+		}
+		if combiner.Type != FirstSuccess && combiner.Path == "" {
+			return fmt.Errorf(CombinerPathNotProvidedError, nodeName, ig.Name, combiner.Type)
+		}
+		if combiner.Type == WeightedMean {
+			for i, step := range node.Steps {
+				if step.Weight == nil || *step.Weight < 0 {
+					return fmt.Errorf(CombinerWeightError, i, step.StepName, nodeName, ig.Name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Validation of ensemble failure policies
+func validateInferenceGraphEnsembleFailurePolicy(ig *InferenceGraph) error {
+	for nodeName, node := range ig.Spec.Nodes {
+		policy := node.FailurePolicy
+		if policy == nil {
+			continue
+		}
+		if node.RouterType != Ensemble {
+			return fmt.Errorf(FailurePolicyNotEnsembleError, nodeName, ig.Name)
+		}
+		if policy.Type != Quorum {
+			if policy.Quorum != nil {
+				return fmt.Errorf(InvalidQuorumError, nodeName, ig.Name, "quorum can only be set for the Quorum policy")
+			}
+			continue
+		}
+		if policy.Quorum == nil {
+			return fmt.Errorf(InvalidQuorumError, nodeName, ig.Name, "quorum is required for the Quorum policy")
+		}
+		if *policy.Quorum < 1 || int(*policy.Quorum) > len(node.Steps) {
+			return fmt.Errorf(InvalidQuorumError, nodeName, ig.Name,
+				fmt.Sprintf("quorum must be between 1 and the number of steps %d", len(node.Steps)))
+		}
+	}
+	return nil
+}
+
+// Validation of the forEach options, ForEach nodes call their single step once per array element
+func validateInferenceGraphForEach(ig *InferenceGraph) error {
+	for nodeName, node := range ig.Spec.Nodes {
+		options := node.ForEach
+		if node.RouterType != ForEach {
+			if options != nil {
+				return fmt.Errorf(ForEachOptionNotForEachError, nodeName, ig.Name)
+			}
+			continue
+		}
+		if options == nil {
+			return fmt.Errorf(InvalidForEachError, nodeName, ig.Name, "forEach is required for ForEach nodes")
+		}
+		if options.Path == "" {
+			return fmt.Errorf(InvalidForEachError, nodeName, ig.Name, "path is required")
+		}
+		if options.Parallelism != nil && *options.Parallelism < 1 {
+			return fmt.Errorf(InvalidForEachError, nodeName, ig.Name, "parallelism must be at least 1")
+		}
+		if len(node.Steps) != 1 {
+			return fmt.Errorf(InvalidForEachError, nodeName, ig.Name, "ForEach nodes must have exactly one step")
+		}
+	}
+	return nil
+}
+
+// Validation of step conditions, conditions are compiled the same way as by the router
+func validateInferenceGraphConditions(ig *InferenceGraph) error {
+	for nodeName, node := range ig.Spec.Nodes {
+		for i, step := range node.Steps {
+			if step.Condition == "" {
+				continue
+			}
+			if _, err := condition.Compile(step.Condition); err != nil {
+				return fmt.Errorf(InvalidConditionError, i, step.StepName, nodeName, ig.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Validation of step data mappings, data is only sent by Sequence and ForEach nodes and `$steps` references must name
+// an earlier step of a Sequence node
+func validateInferenceGraphStepData(ig *InferenceGraph) error {
+	for nodeName, node := range ig.Spec.Nodes {
+		earlierSteps := sets.NewString()
+		for i, step := range node.Steps {
+			if step.Data != "" {
+				if node.RouterType != Sequence && node.RouterType != ForEach {
+					return fmt.Errorf(DataNotSupportedError, i, step.StepName, nodeName, ig.Name)
+				}
+				m, err := mapping.Compile(step.Data)
+				if err != nil {
+					return fmt.Errorf(InvalidDataError, i, step.StepName, nodeName, ig.Name, err)
+				}
+				for _, referenced := range m.StepReferences() {
+					if node.RouterType != Sequence || !earlierSteps.Has(referenced) {
+						return fmt.Errorf(DataStepReferenceError, i, step.StepName, nodeName, ig.Name, referenced)
+					}
+				}
+			}
+			if step.StepName != "" {
+				earlierSteps.Insert(step.StepName)
+			}
+		}
+	}
+	return nil
+}
+
+// graphNodeTargets returns the nodes called by the steps and mirrors of a node in step order
+func graphNodeTargets(node InferenceRouter) []string {
+	var targets []string
+	for _, step := range node.Steps {
+		if step.NodeName != "" {
+			targets = append(targets, step.NodeName)
+		}
+		if step.Mirror != nil && step.Mirror.NodeName != "" {
+			targets = append(targets, step.Mirror.NodeName)
+		}
+	}
+	return targets
+}
+
+// Validation of the graph structure, every node targeted by a step must exist and the nodes called from the root node
+// must form an acyclic graph of at most MaxGraphDepth nested nodes which includes every node
+func validateInferenceGraphStructure(ig *InferenceGraph) error {
+	nodes := ig.Spec.Nodes
+	nodeNames := make([]string, 0, len(nodes))
+	for nodeName := range nodes {
+		nodeNames = append(nodeNames, nodeName)
+	}
+	sort.Strings(nodeNames)
+	for _, nodeName := range nodeNames {
+		for i, step := range nodes[nodeName].Steps {
+			if _, ok := nodes[step.NodeName]; step.NodeName != "" && !ok {
+				return fmt.Errorf(DanglingNodeReferenceError, i, step.StepName, nodeName, ig.Name, step.NodeName)
+			}
+		}
+	}
+
+	// heights holds the number of nodes on the longest path starting at each visited node and next the node which
+	// continues that path, so that nodes called from several nodes are only walked once
+	heights := map[string]int{}
+	next := map[string]string{}
+	var visit func(path []string) error
+	visit = func(path []string) error {
+		nodeName := path[len(path)-1]
+		for i, visited := range path[:len(path)-1] {
+			if visited == nodeName {
+				return fmt.Errorf(GraphCycleError, ig.Name, strings.Join(path[i:], " -> "))
+			}
+		}
+		if len(path) > MaxGraphDepth {
+			return fmt.Errorf(GraphDepthError, ig.Name, MaxGraphDepth, strings.Join(path, " -> "))
+		}
+		if _, ok := heights[nodeName]; !ok {
+			height := 1
+			for _, target := range graphNodeTargets(nodes[nodeName]) {
+				if err := visit(append(path, target)); err != nil {
+					return err
+				}
+				if heights[target]+1 > height {
+					height = heights[target] + 1
+					next[nodeName] = target
+				}
+			}
+			heights[nodeName] = height
+		}
+		if len(path)-1+heights[nodeName] > MaxGraphDepth {
+			deepest := append([]string{}, path...)
+			for n := next[nodeName]; n != ""; n = next[n] {
+				deepest = append(deepest, n)
+			}
+			return fmt.Errorf(GraphDepthError, ig.Name, MaxGraphDepth, strings.Join(deepest, " -> "))
+		}
+		return nil
+	}
+	if err := visit([]string{GraphRootNodeName}); err != nil {
+		return err
+	}
+	for _, nodeName := range nodeNames {
+		if _, ok := heights[nodeName]; !ok {
+			return fmt.Errorf(UnreachableNodeError, nodeName, ig.Name)
+		}
+	}
+	return nil
+}
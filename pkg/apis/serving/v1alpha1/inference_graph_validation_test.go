@@ -19,8 +19,10 @@ package v1alpha1
 import (
 	"fmt"
 	"github.com/golang/protobuf/proto"
+	"github.com/kserve/kserve/pkg/constants"
 	"github.com/onsi/gomega"
 	"github.com/onsi/gomega/types"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"testing"
 )
@@ -41,6 +43,9 @@ func makeTestInferenceGraph() InferenceGraph {
 
 func TestInferenceGraph_ValidateCreate(t *testing.T) {
 	g := gomega.NewGomegaWithT(t)
+	protocolV2 := constants.ProtocolV2
+	protocolGRPCV1 := constants.ProtocolGRPCV1
+	protocolGRPCV2 := constants.ProtocolGRPCV2
 	scenarios := map[string]struct {
 		ig              InferenceGraph
 		update          map[string]string
@@ -258,6 +263,1154 @@ func TestInferenceGraph_ValidateCreate(t *testing.T) {
 			errMatcher:      gomega.MatchError(fmt.Errorf(DuplicateStepNameError, GraphRootNodeName, "foo-bar", "step1")),
 			warningsMatcher: gomega.BeEmpty(),
 		},
+		"step with timeout, retry and circuit breaker": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: "Sequence",
+					Steps: []InferenceStep{
+						{
+							StepName: "step1",
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+							TimeoutSeconds: proto.Int64(5),
+							RetryPolicy: &StepRetryPolicy{
+								MaxAttempts:          proto.Int32(3),
+								Backoff:              proto.Int64(50),
+								RetryableStatusCodes: []int32{503},
+							},
+							CircuitBreaker: &StepCircuitBreaker{
+								FailureThreshold:    proto.Int32(2),
+								ResetTimeoutSeconds: proto.Int64(10),
+							},
+						},
+					},
+				},
+			},
+			errMatcher:      gomega.MatchError(nil),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"step with cache": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: "Sequence",
+					Steps: []InferenceStep{
+						{
+							StepName: "step1",
+							InferenceTarget: InferenceTarget{
+								NodeName: "features",
+							},
+							Cache: &StepCache{
+								TTLSeconds: proto.Int64(300),
+								MaxEntries: proto.Int32(100),
+								Headers:    []string{"X-Tenant"},
+							},
+						},
+					},
+				},
+				"features": {
+					RouterType: "Sequence",
+					Steps: []InferenceStep{
+						{
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+						},
+					},
+				},
+			},
+			errMatcher:      gomega.MatchError(nil),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"step with invalid cache ttl": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: "Sequence",
+					Steps: []InferenceStep{
+						{
+							StepName: "step1",
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+							Cache: &StepCache{
+								TTLSeconds: proto.Int64(0),
+							},
+						},
+					},
+				},
+			},
+			errMatcher: gomega.MatchError(fmt.Errorf(InvalidStepCacheError, 0, "step1", GraphRootNodeName, "foo-bar",
+				"ttl must be greater than 0")),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"step with mirror": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: "Sequence",
+					Steps: []InferenceStep{
+						{
+							StepName: "step1",
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+							Mirror: &StepMirror{
+								InferenceTarget: InferenceTarget{
+									ServiceName: "candidate",
+								},
+								Percent: proto.Int64(10),
+							},
+						},
+					},
+				},
+			},
+			errMatcher:      gomega.MatchError(nil),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"step with mirror to a missing node": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: "Sequence",
+					Steps: []InferenceStep{
+						{
+							StepName: "step1",
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+							Mirror: &StepMirror{
+								InferenceTarget: InferenceTarget{
+									NodeName: "candidate",
+								},
+							},
+						},
+					},
+				},
+			},
+			errMatcher: gomega.MatchError(fmt.Errorf(InvalidStepMirrorError, 0, "step1", GraphRootNodeName, "foo-bar",
+				"node \"candidate\" does not exist")),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"step with invalid mirror percent": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: "Sequence",
+					Steps: []InferenceStep{
+						{
+							StepName: "step1",
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+							Mirror: &StepMirror{
+								InferenceTarget: InferenceTarget{
+									ServiceURL: "http://candidate",
+								},
+								Percent: proto.Int64(101),
+							},
+						},
+					},
+				},
+			},
+			errMatcher: gomega.MatchError(fmt.Errorf(InvalidStepMirrorError, 0, "step1", GraphRootNodeName, "foo-bar",
+				"percent must be between 0 and 100")),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"steps with auth": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: "Sequence",
+					Steps: []InferenceStep{
+						{
+							StepName: "step1",
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+							Auth: &StepAuth{Type: ForwardToken},
+						},
+						{
+							StepName: "step2",
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service2",
+							},
+							Auth: &StepAuth{Type: ServiceAccountToken, Audience: "models"},
+						},
+						{
+							StepName: "step3",
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service3",
+							},
+							Auth: &StepAuth{
+								Type:   SecretHeader,
+								Header: "X-Api-Key",
+								SecretKeyRef: &v1.SecretKeySelector{
+									LocalObjectReference: v1.LocalObjectReference{Name: "model-credentials"},
+									Key:                  "apiKey",
+								},
+							},
+						},
+					},
+				},
+			},
+			errMatcher:      gomega.MatchError(nil),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"step with secret header auth without secret": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: "Sequence",
+					Steps: []InferenceStep{
+						{
+							StepName: "step1",
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+							Auth: &StepAuth{Type: SecretHeader, Header: "X-Api-Key"},
+						},
+					},
+				},
+			},
+			errMatcher: gomega.MatchError(fmt.Errorf(InvalidStepAuthError, 0, "step1", GraphRootNodeName, "foo-bar",
+				"SecretHeader requires the name and key of the secretKeyRef")),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"mirror with audience on forwarded token auth": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: "Sequence",
+					Steps: []InferenceStep{
+						{
+							StepName: "step1",
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+							Mirror: &StepMirror{
+								InferenceTarget: InferenceTarget{
+									ServiceURL: "http://candidate",
+								},
+								Auth: &StepAuth{Type: ForwardToken, Audience: "models"},
+							},
+						},
+					},
+				},
+			},
+			errMatcher: gomega.MatchError(fmt.Errorf(InvalidStepMirrorError, 0, "step1", GraphRootNodeName, "foo-bar",
+				"invalid auth: audience is only supported by ServiceAccountToken")),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"step with invalid timeout": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: "Sequence",
+					Steps: []InferenceStep{
+						{
+							StepName: "step1",
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+							TimeoutSeconds: proto.Int64(0),
+						},
+					},
+				},
+			},
+			errMatcher:      gomega.MatchError(fmt.Errorf(InvalidStepTimeoutError, 0, "step1", GraphRootNodeName, "foo-bar")),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"step with invalid retry attempts": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: "Sequence",
+					Steps: []InferenceStep{
+						{
+							StepName: "step1",
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+							RetryPolicy: &StepRetryPolicy{
+								MaxAttempts: proto.Int32(0),
+							},
+						},
+					},
+				},
+			},
+			errMatcher: gomega.MatchError(fmt.Errorf(InvalidRetryPolicyError, 0, "step1", GraphRootNodeName, "foo-bar",
+				"maxAttempts must be at least 1")),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"step with too many retry attempts": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: "Sequence",
+					Steps: []InferenceStep{
+						{
+							StepName: "step1",
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+							RetryPolicy: &StepRetryPolicy{
+								MaxAttempts: proto.Int32(100),
+							},
+						},
+					},
+				},
+			},
+			errMatcher: gomega.MatchError(fmt.Errorf(InvalidRetryPolicyError, 0, "step1", GraphRootNodeName, "foo-bar",
+				"maxAttempts must be at most 10")),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"step with too long retry backoff": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: "Sequence",
+					Steps: []InferenceStep{
+						{
+							StepName: "step1",
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+							RetryPolicy: &StepRetryPolicy{
+								Backoff: proto.Int64(600000),
+							},
+						},
+					},
+				},
+			},
+			errMatcher: gomega.MatchError(fmt.Errorf(InvalidRetryPolicyError, 0, "step1", GraphRootNodeName, "foo-bar",
+				"backoff must be at most 60000")),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"step with invalid retryable status code": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: "Sequence",
+					Steps: []InferenceStep{
+						{
+							StepName: "step1",
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+							RetryPolicy: &StepRetryPolicy{
+								RetryableStatusCodes: []int32{1000},
+							},
+						},
+					},
+				},
+			},
+			errMatcher: gomega.MatchError(fmt.Errorf(InvalidRetryPolicyError, 0, "step1", GraphRootNodeName, "foo-bar",
+				"1000 is not a valid status code")),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"step with invalid circuit breaker threshold": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: "Sequence",
+					Steps: []InferenceStep{
+						{
+							StepName: "step1",
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+							CircuitBreaker: &StepCircuitBreaker{
+								FailureThreshold: proto.Int32(0),
+							},
+						},
+					},
+				},
+			},
+			errMatcher: gomega.MatchError(fmt.Errorf(InvalidCircuitBreakerError, 0, "step1", GraphRootNodeName, "foo-bar",
+				"failureThreshold must be at least 1")),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"retry policy on node step": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: "Sequence",
+					Steps: []InferenceStep{
+						{
+							StepName: "step1",
+							InferenceTarget: InferenceTarget{
+								NodeName: "node1",
+							},
+							RetryPolicy: &StepRetryPolicy{
+								MaxAttempts: proto.Int32(2),
+							},
+						},
+					},
+				},
+			},
+			errMatcher:      gomega.MatchError(fmt.Errorf(NodeStepPolicyError, 0, "step1", GraphRootNodeName, "foo-bar")),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"v2 protocol node": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType:      "Sequence",
+					ProtocolVersion: &protocolV2,
+				},
+			},
+			errMatcher:      gomega.MatchError(nil),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"unsupported protocol node": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType:      "Sequence",
+					ProtocolVersion: &protocolGRPCV1,
+				},
+			},
+			errMatcher:      gomega.MatchError(fmt.Errorf(InvalidNodeProtocolError, GraphRootNodeName, "foo-bar", protocolGRPCV1)),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"grpc step in v2 node": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType:      "Sequence",
+					ProtocolVersion: &protocolV2,
+					Steps: []InferenceStep{
+						{
+							StepName: "step1",
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+							ProtocolVersion: &protocolGRPCV2,
+						},
+						{
+							StepName: "step2",
+							InferenceTarget: InferenceTarget{
+								ServiceURL: "grpc://service2.default:8081/model",
+							},
+						},
+					},
+				},
+			},
+			errMatcher:      gomega.MatchError(nil),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"grpc step in v1 node": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: "Sequence",
+					Steps: []InferenceStep{
+						{
+							StepName: "step1",
+							InferenceTarget: InferenceTarget{
+								ServiceURL: "grpc://service1.default:8081/model",
+							},
+						},
+					},
+				},
+			},
+			errMatcher:      gomega.MatchError(fmt.Errorf(GRPCStepNodeProtocolError, 0, "step1", GraphRootNodeName, "foo-bar")),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"unsupported step protocol": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType:      "Sequence",
+					ProtocolVersion: &protocolV2,
+					Steps: []InferenceStep{
+						{
+							StepName: "step1",
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+							ProtocolVersion: &protocolGRPCV1,
+						},
+					},
+				},
+			},
+			errMatcher: gomega.MatchError(fmt.Errorf(InvalidStepProtocolError, 0, "step1", GraphRootNodeName, "foo-bar",
+				protocolGRPCV1)),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"ensemble with weighted mean combiner": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: Ensemble,
+					Combiner: &EnsembleCombiner{
+						Type: WeightedMean,
+						Path: "predictions",
+					},
+					Steps: []InferenceStep{
+						{
+							StepName: "step1",
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+							Weight: proto.Int64(1),
+						},
+						{
+							StepName: "step2",
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service2",
+							},
+							Weight: proto.Int64(3),
+						},
+					},
+				},
+			},
+			errMatcher:      gomega.MatchError(nil),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"combiner on sequence node": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: Sequence,
+					Combiner: &EnsembleCombiner{
+						Type: FirstSuccess,
+					},
+					Steps: []InferenceStep{
+						{
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+						},
+					},
+				},
+			},
+			errMatcher:      gomega.MatchError(fmt.Errorf(CombinerNotEnsembleError, GraphRootNodeName, "foo-bar")),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"combiner without path": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: Ensemble,
+					Combiner: &EnsembleCombiner{
+						Type: MajorityVote,
+					},
+					Steps: []InferenceStep{
+						{
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+						},
+					},
+				},
+			},
+			errMatcher:      gomega.MatchError(fmt.Errorf(CombinerPathNotProvidedError, GraphRootNodeName, "foo-bar", MajorityVote)),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"weighted mean combiner without weight": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: Ensemble,
+					Combiner: &EnsembleCombiner{
+						Type: WeightedMean,
+						Path: "predictions",
+					},
+					Steps: []InferenceStep{
+						{
+							StepName: "step1",
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+						},
+					},
+				},
+			},
+			errMatcher:      gomega.MatchError(fmt.Errorf(CombinerWeightError, 0, "step1", GraphRootNodeName, "foo-bar")),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"switch with condition expressions": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: Switch,
+					Steps: []InferenceStep{
+						{
+							StepName: "step1",
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+							Condition: `body.instances[0].userId == 1 && headers["x-tenant"] == "gold"`,
+						},
+						{
+							StepName: "step2",
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service2",
+							},
+							Condition: `instances.#(userId==2)`,
+						},
+					},
+				},
+			},
+			errMatcher:      gomega.MatchError(nil),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"switch with invalid condition expression": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: Switch,
+					Steps: []InferenceStep{
+						{
+							StepName: "step1",
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+							Condition: `headers["x-tenant"]`,
+						},
+					},
+				},
+			},
+			errMatcher: gomega.MatchError(fmt.Errorf(InvalidConditionError, 0, "step1", GraphRootNodeName, "foo-bar",
+				fmt.Errorf(`condition "headers[\"x-tenant\"]" must evaluate to a bool but evaluates to string`))),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"sequence with data mappings": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: Sequence,
+					Steps: []InferenceStep{
+						{
+							StepName: "step1",
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+							Data: "$request.instances",
+						},
+						{
+							StepName: "step2",
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service2",
+							},
+							Data: `{"instances": "$steps.step1.predictions", "threshold": 0.5}`,
+						},
+					},
+				},
+			},
+			errMatcher:      gomega.MatchError(nil),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"invalid data mapping": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: Sequence,
+					Steps: []InferenceStep{
+						{
+							StepName: "step1",
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+							Data: "$input",
+						},
+					},
+				},
+			},
+			errMatcher: gomega.MatchError(fmt.Errorf(InvalidDataError, 0, "step1", GraphRootNodeName, "foo-bar",
+				fmt.Errorf("unknown reference $input, references start with $request, $response or $steps"))),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"data mapping referencing a later step": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: Sequence,
+					Steps: []InferenceStep{
+						{
+							StepName: "step1",
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+							Data: `{"instances": "$steps.step2.predictions"}`,
+						},
+						{
+							StepName: "step2",
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service2",
+							},
+						},
+					},
+				},
+			},
+			errMatcher:      gomega.MatchError(fmt.Errorf(DataStepReferenceError, 0, "step1", GraphRootNodeName, "foo-bar", "step2")),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"data in an ensemble node": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: Ensemble,
+					Steps: []InferenceStep{
+						{
+							StepName: "step1",
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+							Data: "$request.instances",
+						},
+					},
+				},
+			},
+			errMatcher:      gomega.MatchError(fmt.Errorf(DataNotSupportedError, 0, "step1", GraphRootNodeName, "foo-bar")),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"sticky splitter": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: Splitter,
+					HashKey: &SplitterHashKey{
+						Header: "x-user-id",
+					},
+					Seed: proto.Int64(42),
+					Steps: []InferenceStep{
+						{
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+							Weight: proto.Int64(50),
+						},
+						{
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service2",
+							},
+							Weight: proto.Int64(50),
+						},
+					},
+				},
+			},
+			errMatcher:      gomega.MatchError(nil),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"hash key with header and field": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: Splitter,
+					HashKey: &SplitterHashKey{
+						Header: "x-user-id",
+						Field:  "instances.0.userId",
+					},
+					Steps: []InferenceStep{
+						{
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+							Weight: proto.Int64(100),
+						},
+					},
+				},
+			},
+			errMatcher:      gomega.MatchError(fmt.Errorf(InvalidHashKeyError, GraphRootNodeName, "foo-bar")),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"seed on sequence node": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: Sequence,
+					Seed:       proto.Int64(42),
+					Steps: []InferenceStep{
+						{
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+						},
+					},
+				},
+			},
+			errMatcher:      gomega.MatchError(fmt.Errorf(SplitterOptionNotSplitterError, GraphRootNodeName, "foo-bar")),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"ensemble with quorum failure policy": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: Ensemble,
+					FailurePolicy: &EnsembleFailurePolicy{
+						Type:   Quorum,
+						Quorum: proto.Int32(2),
+					},
+					Steps: []InferenceStep{
+						{
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+						},
+						{
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service2",
+							},
+						},
+					},
+				},
+			},
+			errMatcher:      gomega.MatchError(nil),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"failure policy on switch node": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: Switch,
+					FailurePolicy: &EnsembleFailurePolicy{
+						Type: All,
+					},
+					Steps: []InferenceStep{
+						{
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+						},
+					},
+				},
+			},
+			errMatcher:      gomega.MatchError(fmt.Errorf(FailurePolicyNotEnsembleError, GraphRootNodeName, "foo-bar")),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"quorum larger than the number of steps": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: Ensemble,
+					FailurePolicy: &EnsembleFailurePolicy{
+						Type:   Quorum,
+						Quorum: proto.Int32(2),
+					},
+					Steps: []InferenceStep{
+						{
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+						},
+					},
+				},
+			},
+			errMatcher: gomega.MatchError(fmt.Errorf(InvalidQuorumError, GraphRootNodeName, "foo-bar",
+				"quorum must be between 1 and the number of steps 1")),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"quorum without quorum policy": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: Ensemble,
+					FailurePolicy: &EnsembleFailurePolicy{
+						Type:   BestEffort,
+						Quorum: proto.Int32(1),
+					},
+					Steps: []InferenceStep{
+						{
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+						},
+					},
+				},
+			},
+			errMatcher: gomega.MatchError(fmt.Errorf(InvalidQuorumError, GraphRootNodeName, "foo-bar",
+				"quorum can only be set for the Quorum policy")),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"valid replicas": {
+			ig: func() InferenceGraph {
+				ig := makeTestInferenceGraph()
+				minReplicas, scaleTarget := 0, 80
+				ig.Spec.MinReplicas = &minReplicas
+				ig.Spec.MaxReplicas = 3
+				ig.Spec.ScaleTarget = &scaleTarget
+				return ig
+			}(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {},
+			},
+			errMatcher:      gomega.MatchError(nil),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"min replicas greater than max replicas": {
+			ig: func() InferenceGraph {
+				ig := makeTestInferenceGraph()
+				minReplicas := 4
+				ig.Spec.MinReplicas = &minReplicas
+				ig.Spec.MaxReplicas = 3
+				return ig
+			}(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {},
+			},
+			errMatcher:      gomega.MatchError(fmt.Errorf(InvalidReplicasError, "foo-bar", "minReplicas cannot be greater than maxReplicas")),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"negative max replicas": {
+			ig: func() InferenceGraph {
+				ig := makeTestInferenceGraph()
+				ig.Spec.MaxReplicas = -1
+				return ig
+			}(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {},
+			},
+			errMatcher:      gomega.MatchError(fmt.Errorf(InvalidReplicasError, "foo-bar", "maxReplicas cannot be less than 0")),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"valid forEach node": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: ForEach,
+					ForEach: &ForEachOptions{
+						Path:        "instances",
+						Parallelism: proto.Int32(4),
+					},
+					Steps: []InferenceStep{
+						{
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+							Data: `{"instances": ["$response"]}`,
+						},
+					},
+				},
+			},
+			errMatcher:      gomega.MatchError(nil),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"forEach node without path": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: ForEach,
+					ForEach:    &ForEachOptions{},
+					Steps: []InferenceStep{
+						{
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+						},
+					},
+				},
+			},
+			errMatcher:      gomega.MatchError(fmt.Errorf(InvalidForEachError, GraphRootNodeName, "foo-bar", "path is required")),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"forEach node with more than one step": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: ForEach,
+					ForEach:    &ForEachOptions{Path: "instances"},
+					Steps: []InferenceStep{
+						{
+							StepName: "step1",
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+						},
+						{
+							StepName: "step2",
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service2",
+							},
+						},
+					},
+				},
+			},
+			errMatcher:      gomega.MatchError(fmt.Errorf(InvalidForEachError, GraphRootNodeName, "foo-bar", "ForEach nodes must have exactly one step")),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"forEach options on a sequence node": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: Sequence,
+					ForEach:    &ForEachOptions{Path: "instances"},
+				},
+			},
+			errMatcher:      gomega.MatchError(fmt.Errorf(ForEachOptionNotForEachError, GraphRootNodeName, "foo-bar")),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"weight missing in splitter route targeting a node": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: "Splitter",
+					Steps: []InferenceStep{
+						{
+							InferenceTarget: InferenceTarget{
+								NodeName: "node1",
+							},
+						},
+					},
+				},
+				"node1": {},
+			},
+			errMatcher:      gomega.MatchError(fmt.Errorf(WeightNotProvidedError, "foo-bar", GraphRootNodeName, "node1")),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"step targets a node which does not exist": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					Steps: []InferenceStep{
+						{
+							StepName: "step1",
+							InferenceTarget: InferenceTarget{
+								NodeName: "node1",
+							},
+						},
+					},
+				},
+			},
+			errMatcher:      gomega.MatchError(fmt.Errorf(DanglingNodeReferenceError, 0, "step1", GraphRootNodeName, "foo-bar", "node1")),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"nodes call each other": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					Steps: []InferenceStep{
+						{
+							InferenceTarget: InferenceTarget{
+								NodeName: "node1",
+							},
+						},
+					},
+				},
+				"node1": {
+					Steps: []InferenceStep{
+						{
+							InferenceTarget: InferenceTarget{
+								NodeName: "node2",
+							},
+						},
+					},
+				},
+				"node2": {
+					Steps: []InferenceStep{
+						{
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+							Mirror: &StepMirror{
+								InferenceTarget: InferenceTarget{
+									NodeName: "node1",
+								},
+							},
+						},
+					},
+				},
+			},
+			errMatcher:      gomega.MatchError(fmt.Errorf(GraphCycleError, "foo-bar", "node1 -> node2 -> node1")),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"node is not reachable from the root node": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					Steps: []InferenceStep{
+						{
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+						},
+					},
+				},
+				"node1": {
+					Steps: []InferenceStep{
+						{
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service2",
+							},
+						},
+					},
+				},
+			},
+			errMatcher:      gomega.MatchError(fmt.Errorf(UnreachableNodeError, "node1", "foo-bar")),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"nodes nested deeper than the maximum depth": {
+			ig: makeTestInferenceGraph(),
+			nodes: func() map[string]InferenceRouter {
+				nodes := map[string]InferenceRouter{}
+				path := []string{GraphRootNodeName}
+				for i := 1; i <= MaxGraphDepth; i++ {
+					path = append(path, fmt.Sprintf("node%d", i))
+				}
+				for i, nodeName := range path[:len(path)-1] {
+					nodes[nodeName] = InferenceRouter{
+						Steps: []InferenceStep{
+							{
+								InferenceTarget: InferenceTarget{
+									NodeName: path[i+1],
+								},
+							},
+						},
+					}
+				}
+				nodes[path[len(path)-1]] = InferenceRouter{}
+				return nodes
+			}(),
+			errMatcher: gomega.MatchError(fmt.Errorf(GraphDepthError, "foo-bar", MaxGraphDepth,
+				"root -> node1 -> node2 -> node3 -> node4 -> node5 -> node6 -> node7 -> node8 -> node9 -> node10")),
+			warningsMatcher: gomega.BeEmpty(),
+		},
+		"nodes called from several nodes": {
+			ig: makeTestInferenceGraph(),
+			nodes: map[string]InferenceRouter{
+				GraphRootNodeName: {
+					RouterType: "Ensemble",
+					Steps: []InferenceStep{
+						{
+							StepName: "step1",
+							InferenceTarget: InferenceTarget{
+								NodeName: "node1",
+							},
+						},
+						{
+							StepName: "step2",
+							InferenceTarget: InferenceTarget{
+								NodeName: "node2",
+							},
+						},
+					},
+				},
+				"node1": {
+					Steps: []InferenceStep{
+						{
+							InferenceTarget: InferenceTarget{
+								NodeName: "node2",
+							},
+						},
+					},
+				},
+				"node2": {
+					Steps: []InferenceStep{
+						{
+							InferenceTarget: InferenceTarget{
+								ServiceName: "service1",
+							},
+						},
+					},
+				},
+			},
+			errMatcher:      gomega.MatchError(nil),
+			warningsMatcher: gomega.BeEmpty(),
+		},
 	}
 
 	for testName, scenario := range scenarios {
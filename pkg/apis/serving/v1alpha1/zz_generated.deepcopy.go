@@ -173,6 +173,61 @@ func (in *ClusterStorageContainerList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnsembleCombiner) DeepCopyInto(out *EnsembleCombiner) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnsembleCombiner.
+func (in *EnsembleCombiner) DeepCopy() *EnsembleCombiner {
+	if in == nil {
+		return nil
+	}
+	out := new(EnsembleCombiner)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnsembleFailurePolicy) DeepCopyInto(out *EnsembleFailurePolicy) {
+	*out = *in
+	if in.Quorum != nil {
+		in, out := &in.Quorum, &out.Quorum
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnsembleFailurePolicy.
+func (in *EnsembleFailurePolicy) DeepCopy() *EnsembleFailurePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(EnsembleFailurePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ForEachOptions) DeepCopyInto(out *ForEachOptions) {
+	*out = *in
+	if in.Parallelism != nil {
+		in, out := &in.Parallelism, &out.Parallelism
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ForEachOptions.
+func (in *ForEachOptions) DeepCopy() *ForEachOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(ForEachOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InferenceGraph) DeepCopyInto(out *InferenceGraph) {
 	*out = *in
@@ -232,6 +287,27 @@ func (in *InferenceGraphList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InferenceGraphServiceStatus) DeepCopyInto(out *InferenceGraphServiceStatus) {
+	*out = *in
+	if in.URL != nil {
+		in, out := &in.URL, &out.URL
+		*out = new(apis.URL)
+		(*in).DeepCopyInto(*out)
+	}
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InferenceGraphServiceStatus.
+func (in *InferenceGraphServiceStatus) DeepCopy() *InferenceGraphServiceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InferenceGraphServiceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InferenceGraphSpec) DeepCopyInto(out *InferenceGraphSpec) {
 	*out = *in
@@ -248,6 +324,21 @@ func (in *InferenceGraphSpec) DeepCopyInto(out *InferenceGraphSpec) {
 		*out = new(v1.Affinity)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int)
+		**out = **in
+	}
+	if in.ScaleTarget != nil {
+		in, out := &in.ScaleTarget, &out.ScaleTarget
+		*out = new(int)
+		**out = **in
+	}
+	if in.ScaleMetric != nil {
+		in, out := &in.ScaleMetric, &out.ScaleMetric
+		*out = new(ScaleMetric)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InferenceGraphSpec.
@@ -269,6 +360,13 @@ func (in *InferenceGraphStatus) DeepCopyInto(out *InferenceGraphStatus) {
 		*out = new(apis.URL)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make([]InferenceGraphServiceStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InferenceGraphStatus.
@@ -291,6 +389,36 @@ func (in *InferenceRouter) DeepCopyInto(out *InferenceRouter) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ProtocolVersion != nil {
+		in, out := &in.ProtocolVersion, &out.ProtocolVersion
+		*out = new(constants.InferenceServiceProtocol)
+		**out = **in
+	}
+	if in.Combiner != nil {
+		in, out := &in.Combiner, &out.Combiner
+		*out = new(EnsembleCombiner)
+		**out = **in
+	}
+	if in.HashKey != nil {
+		in, out := &in.HashKey, &out.HashKey
+		*out = new(SplitterHashKey)
+		**out = **in
+	}
+	if in.Seed != nil {
+		in, out := &in.Seed, &out.Seed
+		*out = new(int64)
+		**out = **in
+	}
+	if in.FailurePolicy != nil {
+		in, out := &in.FailurePolicy, &out.FailurePolicy
+		*out = new(EnsembleFailurePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ForEach != nil {
+		in, out := &in.ForEach, &out.ForEach
+		*out = new(ForEachOptions)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InferenceRouter.
@@ -312,6 +440,41 @@ func (in *InferenceStep) DeepCopyInto(out *InferenceStep) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(StepRetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CircuitBreaker != nil {
+		in, out := &in.CircuitBreaker, &out.CircuitBreaker
+		*out = new(StepCircuitBreaker)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		*out = new(StepCache)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Mirror != nil {
+		in, out := &in.Mirror, &out.Mirror
+		*out = new(StepMirror)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(StepAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProtocolVersion != nil {
+		in, out := &in.ProtocolVersion, &out.ProtocolVersion
+		*out = new(constants.InferenceServiceProtocol)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InferenceStep.
@@ -564,6 +727,152 @@ func (in *ServingRuntimeStatus) DeepCopy() *ServingRuntimeStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SplitterHashKey) DeepCopyInto(out *SplitterHashKey) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SplitterHashKey.
+func (in *SplitterHashKey) DeepCopy() *SplitterHashKey {
+	if in == nil {
+		return nil
+	}
+	out := new(SplitterHashKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StepAuth) DeepCopyInto(out *StepAuth) {
+	*out = *in
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepAuth.
+func (in *StepAuth) DeepCopy() *StepAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(StepAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StepCache) DeepCopyInto(out *StepCache) {
+	*out = *in
+	if in.TTLSeconds != nil {
+		in, out := &in.TTLSeconds, &out.TTLSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxEntries != nil {
+		in, out := &in.MaxEntries, &out.MaxEntries
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepCache.
+func (in *StepCache) DeepCopy() *StepCache {
+	if in == nil {
+		return nil
+	}
+	out := new(StepCache)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StepCircuitBreaker) DeepCopyInto(out *StepCircuitBreaker) {
+	*out = *in
+	if in.FailureThreshold != nil {
+		in, out := &in.FailureThreshold, &out.FailureThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ResetTimeoutSeconds != nil {
+		in, out := &in.ResetTimeoutSeconds, &out.ResetTimeoutSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepCircuitBreaker.
+func (in *StepCircuitBreaker) DeepCopy() *StepCircuitBreaker {
+	if in == nil {
+		return nil
+	}
+	out := new(StepCircuitBreaker)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StepMirror) DeepCopyInto(out *StepMirror) {
+	*out = *in
+	out.InferenceTarget = in.InferenceTarget
+	if in.Percent != nil {
+		in, out := &in.Percent, &out.Percent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(StepAuth)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepMirror.
+func (in *StepMirror) DeepCopy() *StepMirror {
+	if in == nil {
+		return nil
+	}
+	out := new(StepMirror)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StepRetryPolicy) DeepCopyInto(out *StepRetryPolicy) {
+	*out = *in
+	if in.MaxAttempts != nil {
+		in, out := &in.MaxAttempts, &out.MaxAttempts
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Backoff != nil {
+		in, out := &in.Backoff, &out.Backoff
+		*out = new(int64)
+		**out = **in
+	}
+	if in.RetryableStatusCodes != nil {
+		in, out := &in.RetryableStatusCodes, &out.RetryableStatusCodes
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepRetryPolicy.
+func (in *StepRetryPolicy) DeepCopy() *StepRetryPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(StepRetryPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageContainerSpec) DeepCopyInto(out *StorageContainerSpec) {
 	*out = *in
@@ -74,6 +74,10 @@ type Batcher struct {
 	// Specifies the timeout of a batch
 	// +optional
 	Timeout *int `json:"timeout,omitempty"`
+	// Specifies the target p99 latency in milliseconds of the batched requests, the batch window adapts to the
+	// observed predictor latency and queue depth to meet it. MaxBatchSize and MaxLatency still bound the batch.
+	// +optional
+	TargetLatency *int `json:"targetLatency,omitempty"`
 }
 
 // InferenceService is the Schema for the InferenceServices API
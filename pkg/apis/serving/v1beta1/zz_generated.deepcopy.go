@@ -80,6 +80,11 @@ func (in *Batcher) DeepCopyInto(out *Batcher) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.TargetLatency != nil {
+		in, out := &in.TargetLatency, &out.TargetLatency
+		*out = new(int)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Batcher.
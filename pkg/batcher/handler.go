@@ -30,7 +30,6 @@ import (
 )
 
 const (
-	SleepTime    = time.Microsecond * 100
 	MaxBatchSize = 32
 	MaxLatency   = 5000
 )
@@ -41,20 +40,29 @@ type Request struct {
 
 type Input struct {
 	ContextInput *context.Context
-	Path         string
-	Instances    *[]interface{}
+	// Key identifies the batch of the request, only the requests with the same key are batched together
+	Key       string
+	Path      string
+	Instances *[]interface{}
+	// BatchSize is the number of instances of a v1 request and the first dimension of the inputs of a v2 request
+	BatchSize    int
+	InferRequest *InferRequest
 	ChannelOut   *chan Response
 }
 
 type InputInfo struct {
 	ChannelOut *chan Response
 	Index      []int
+	RequestID  string
 }
 
 type Response struct {
 	Message     string        `json:"message"`
 	BatchID     string        `json:"batchId"`
 	Predictions []interface{} `json:"predictions"`
+	// statusCode and body are the response of a v2 request
+	statusCode int
+	body       []byte
 }
 
 type ResponseError struct {
@@ -75,6 +83,11 @@ type BatcherInfo struct {
 	Start              time.Time
 	Now                time.Time
 	CurrentInputLen    int
+	Key                string
+	// Deadline is the end of the batch window
+	Deadline time.Time
+	// InferRequest is the batched v2 request, the instances are only used by v1 batches
+	InferRequest *InferRequest
 }
 
 func GetNowTime() time.Time {
@@ -95,18 +108,22 @@ func (batcherInfo *BatcherInfo) InitializeInfo() {
 	batcherInfo.Now = batcherInfo.Start
 }
 
-func (handler *BatchHandler) batchPredict() {
+func (handler *BatchHandler) batchPredict(batcherInfo *BatcherInfo) {
+	if batcherInfo.InferRequest != nil {
+		handler.batchInferPredict(batcherInfo)
+		return
+	}
 	jsonStr, _ := json.Marshal(Request{
-		handler.batcherInfo.Instances,
+		batcherInfo.Instances,
 	})
 	reader := bytes.NewReader(jsonStr)
-	r := httptest.NewRequest("POST", handler.batcherInfo.Path, reader)
+	r := httptest.NewRequest("POST", batcherInfo.Path, reader)
 	rr := httptest.NewRecorder()
 	handler.next.ServeHTTP(rr, r)
 	responseBody := rr.Body.Bytes()
 	if rr.Code != http.StatusOK {
 		handler.log.Errorf("error response with code %v", rr)
-		for _, v := range handler.batcherInfo.ContextMap {
+		for _, v := range batcherInfo.ContextMap {
 			res := Response{
 				Message:     string(responseBody),
 				BatchID:     "",
@@ -115,34 +132,34 @@ func (handler *BatchHandler) batchPredict() {
 			*v.ChannelOut <- res
 		}
 	} else {
-		handler.batcherInfo.BatchID = GenerateUUID()
-		err := json.Unmarshal(responseBody, &handler.batcherInfo.PredictionResponse)
+		batcherInfo.BatchID = GenerateUUID()
+		err := json.Unmarshal(responseBody, &batcherInfo.PredictionResponse)
 		if err != nil {
-			for _, v := range handler.batcherInfo.ContextMap {
+			for _, v := range batcherInfo.ContextMap {
 				res := Response{
 					Message: err.Error(),
-					BatchID: handler.batcherInfo.BatchID,
+					BatchID: batcherInfo.BatchID,
 				}
 				*v.ChannelOut <- res
 			}
 		} else {
-			if len(handler.batcherInfo.PredictionResponse.Predictions) != len(handler.batcherInfo.Instances) {
-				for _, v := range handler.batcherInfo.ContextMap {
+			if len(batcherInfo.PredictionResponse.Predictions) != len(batcherInfo.Instances) {
+				for _, v := range batcherInfo.ContextMap {
 					res := Response{
 						Message: "size of prediction is not equal to the size of instances",
-						BatchID: handler.batcherInfo.BatchID,
+						BatchID: batcherInfo.BatchID,
 					}
 					*v.ChannelOut <- res
 				}
 			} else {
-				for _, v := range handler.batcherInfo.ContextMap {
+				for _, v := range batcherInfo.ContextMap {
 					predictions := make([]interface{}, 0)
 					for _, i := range v.Index {
-						predictions = append(predictions, handler.batcherInfo.PredictionResponse.Predictions[i])
+						predictions = append(predictions, batcherInfo.PredictionResponse.Predictions[i])
 					}
 					res := Response{
 						Message:     "",
-						BatchID:     handler.batcherInfo.BatchID,
+						BatchID:     batcherInfo.BatchID,
 						Predictions: predictions,
 					}
 					*v.ChannelOut <- res
@@ -150,49 +167,132 @@ func (handler *BatchHandler) batchPredict() {
 			}
 		}
 	}
-	handler.batcherInfo.InitializeInfo()
 }
 
+// addInput adds the request to the batch of its key, a new batch starts its window with the request
+func (handler *BatchHandler) addInput(req Input, inflight bool, inflightStart time.Time) {
+	batcherInfo, ok := handler.batches[req.Key]
+	if !ok {
+		batcherInfo = &BatcherInfo{}
+		batcherInfo.InitializeInfo()
+		batcherInfo.Key = req.Key
+		batcherInfo.Path = req.Path
+		batcherInfo.Deadline = batcherInfo.Start.Add(handler.batchWindow(batcherInfo.Start, inflight, inflightStart))
+		handler.batches[req.Key] = batcherInfo
+	}
+	var index = make([]int, 0)
+	for i := 0; i < req.BatchSize; i++ {
+		index = append(index, batcherInfo.CurrentInputLen+i)
+	}
+	inputInfo := InputInfo{
+		ChannelOut: req.ChannelOut,
+		Index:      index,
+	}
+	if req.InferRequest != nil {
+		batcherInfo.InferRequest = appendInferRequest(batcherInfo.InferRequest, req.InferRequest)
+		inputInfo.RequestID = req.InferRequest.ID
+	} else {
+		batcherInfo.Instances = append(batcherInfo.Instances, *req.Instances...)
+	}
+	batcherInfo.ContextMap[req.ContextInput] = inputInfo
+	batcherInfo.CurrentInputLen += req.BatchSize
+}
+
+// nextBatch returns the oldest batch which is full or whose window expired
+func (handler *BatchHandler) nextBatch(now time.Time) *BatcherInfo {
+	var next *BatcherInfo
+	for _, batcherInfo := range handler.batches {
+		if batcherInfo.CurrentInputLen < handler.MaxBatchSize && now.Before(batcherInfo.Deadline) {
+			continue
+		}
+		if next == nil || batcherInfo.Start.Before(next.Start) {
+			next = batcherInfo
+		}
+	}
+	return next
+}
+
+// batchFull reports whether a batch is full, the requests wait until it is sent
+func (handler *BatchHandler) batchFull() bool {
+	for _, batcherInfo := range handler.batches {
+		if batcherInfo.CurrentInputLen >= handler.MaxBatchSize {
+			return true
+		}
+	}
+	return false
+}
+
+// batch collects the requests into batches until a batch is full or its window expires. The loop only wakes up on
+// requests, on the window timer and on the completion of the batch in flight, so an idle batcher does not use CPU.
+// One batch is sent to the predictor at a time, the next batches are collected meanwhile and no request is accepted
+// while one of them is full.
 func (handler *BatchHandler) batch() {
-	handler.log.Infof("Starting batch loop maxLatency:%d, maxBatchSize:%d", handler.MaxLatency, handler.MaxBatchSize)
+	handler.log.Infof("Starting batch loop maxLatency:%d, maxBatchSize:%d, targetLatency:%d",
+		handler.MaxLatency, handler.MaxBatchSize, handler.TargetLatency)
+	window := time.NewTimer(time.Hour)
+	stopTimer(window)
+	// done receives the latency of the batch in flight, it is nil when no batch is in flight
+	var done chan time.Duration
+	var inflightStart time.Time
 	for {
+		channelIn := handler.channelIn
+		if handler.batchFull() {
+			channelIn = nil
+		}
 		select {
-		case req := <-handler.channelIn:
-			if len(handler.batcherInfo.Instances) == 0 {
-				handler.batcherInfo.Start = GetNowTime()
-			}
-			handler.batcherInfo.Path = req.Path
-			handler.batcherInfo.CurrentInputLen = len(handler.batcherInfo.Instances)
-			handler.batcherInfo.Instances = append(handler.batcherInfo.Instances, *req.Instances...)
-			var index = make([]int, 0)
-			for i := 0; i < len(*req.Instances); i++ {
-				index = append(index, handler.batcherInfo.CurrentInputLen+i)
-			}
-			handler.batcherInfo.ContextMap[req.ContextInput] = InputInfo{
-				req.ChannelOut,
-				index,
+		case req := <-channelIn:
+			handler.addInput(req, done != nil, inflightStart)
+		case <-window.C:
+		case latency := <-done:
+			done = nil
+			handler.latency.observe(latency)
+		}
+		if done != nil {
+			// the expired batches are sent once the batch in flight completes
+			continue
+		}
+		now := GetNowTime()
+		if batcherInfo := handler.nextBatch(now); batcherInfo != nil {
+			batcherInfo.Now = now
+			handler.log.Infof("batch predict with size %d %s", batcherInfo.CurrentInputLen, batcherInfo.Path)
+			delete(handler.batches, batcherInfo.Key)
+			done = make(chan time.Duration, 1)
+			inflightStart = time.Now()
+			go func(done chan<- time.Duration, start time.Time) {
+				handler.batchPredict(batcherInfo)
+				done <- time.Since(start)
+			}(done, inflightStart)
+			continue
+		}
+		// wake up at the end of the first batch window
+		stopTimer(window)
+		var deadline time.Time
+		for _, batcherInfo := range handler.batches {
+			if deadline.IsZero() || batcherInfo.Deadline.Before(deadline) {
+				deadline = batcherInfo.Deadline
 			}
-			handler.batcherInfo.CurrentInputLen = len(handler.batcherInfo.Instances)
-		case <-time.After(SleepTime):
 		}
-		handler.batcherInfo.Now = GetNowTime()
-		if handler.batcherInfo.CurrentInputLen >= handler.MaxBatchSize ||
-			(handler.batcherInfo.Now.Sub(handler.batcherInfo.Start).Milliseconds() >= int64(handler.MaxLatency) &&
-				handler.batcherInfo.CurrentInputLen > 0) {
-			handler.log.Infof("batch predict with size %d %s", len(handler.batcherInfo.Instances), handler.batcherInfo.Path)
-			handler.batchPredict()
+		if !deadline.IsZero() {
+			window.Reset(deadline.Sub(now))
 		}
 	}
 }
 
-func (handler *BatchHandler) Consume() {
+func (handler *BatchHandler) setDefaults() {
 	if handler.MaxBatchSize <= 0 {
 		handler.MaxBatchSize = MaxBatchSize
 	}
 	if handler.MaxLatency <= 0 {
 		handler.MaxLatency = MaxLatency
 	}
-	handler.batcherInfo.InitializeInfo()
+	if handler.TargetLatency < 0 {
+		handler.TargetLatency = 0
+	}
+}
+
+func (handler *BatchHandler) Consume() {
+	handler.setDefaults()
+	handler.batches = make(map[string]*BatcherInfo)
 	handler.batch()
 }
 
@@ -202,25 +302,52 @@ type BatchHandler struct {
 	channelIn    chan Input
 	MaxBatchSize int
 	MaxLatency   int
-	batcherInfo  BatcherInfo
+	// TargetLatency is the target p99 latency in milliseconds, the batch window is MaxLatency when it is 0
+	TargetLatency int
+	// batches are the batches collecting requests by key
+	batches map[string]*BatcherInfo
+	latency latencyEstimator
 }
 
+// New creates a batcher which sends a batch when it reaches maxBatchSize instances or maxLatency milliseconds after
+// its first request
 func New(maxBatchSize int, maxLatency int, handler http.Handler, logger *zap.SugaredLogger) *BatchHandler {
+	return NewAdaptive(maxBatchSize, maxLatency, 0, handler, logger)
+}
+
+// NewAdaptive creates a batcher whose batch window adapts to the observed predictor latency and queue depth to meet
+// the target p99 latency in milliseconds, the window never exceeds maxLatency
+func NewAdaptive(maxBatchSize int, maxLatency int, targetLatency int, handler http.Handler, logger *zap.SugaredLogger) *BatchHandler {
 	batchHandler := BatchHandler{
-		next:         handler,
-		log:          logger,
-		channelIn:    make(chan Input),
-		MaxBatchSize: maxBatchSize,
-		MaxLatency:   maxLatency,
+		next:          handler,
+		log:           logger,
+		channelIn:     make(chan Input),
+		MaxBatchSize:  maxBatchSize,
+		MaxLatency:    maxLatency,
+		TargetLatency: targetLatency,
 	}
+	batchHandler.setDefaults()
 	go batchHandler.Consume()
 	return &batchHandler
 }
 
+// enqueue sends the request to the batch loop and waits for its response
+func (handler *BatchHandler) enqueue(input Input) Response {
+	var ctx = context.Background()
+	var chl = make(chan Response)
+	input.ContextInput = &ctx
+	input.ChannelOut = &chl
+	handler.channelIn <- input
+	response := <-chl
+	close(chl)
+	return response
+}
+
 func (handler *BatchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	// only batch predict requests
+	// only batch predict and infer requests
 	var predictVerb = regexp.MustCompile(`:predict$`)
-	if !predictVerb.MatchString(r.URL.Path) {
+	isInfer := r.Method == http.MethodPost && inferVerb.MatchString(r.URL.Path)
+	if !predictVerb.MatchString(r.URL.Path) && !isInfer {
 		handler.next.ServeHTTP(w, r)
 		return
 	}
@@ -232,6 +359,10 @@ func (handler *BatchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "can't read body", http.StatusBadRequest)
 		return
 	}
+	if isInfer {
+		handler.serveInferRequest(w, r, body)
+		return
+	}
 	if err = json.Unmarshal(body, &req); err != nil {
 		http.Error(w, "can't Unmarshal body", http.StatusBadRequest)
 		return
@@ -241,17 +372,12 @@ func (handler *BatchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	handler.log.Infof("serving request %s", r.URL.Path)
-	var ctx = context.Background()
-	var chl = make(chan Response)
-	handler.channelIn <- Input{
-		&ctx,
-		r.URL.Path,
-		&req.Instances,
-		&chl,
-	}
-
-	response := <-chl
-	close(chl)
+	response := handler.enqueue(Input{
+		Key:       r.URL.Path,
+		Path:      r.URL.Path,
+		Instances: &req.Instances,
+		BatchSize: len(req.Instances),
+	})
 	rspbytes, err := json.Marshal(response)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
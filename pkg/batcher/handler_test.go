@@ -29,6 +29,7 @@ import (
 	"net/url"
 	"sync"
 	"testing"
+	"time"
 )
 
 func serveRequest(batchHandler *BatchHandler, wg *sync.WaitGroup, index int) {
@@ -170,3 +171,109 @@ func TestBatcherDefaults(t *testing.T) {
 	g.Expect(batchHandler.MaxBatchSize).To(gomega.Equal(MaxBatchSize))
 	g.Expect(batchHandler.MaxLatency).To(gomega.Equal(MaxLatency))
 }
+
+// Tests that a full batch is sent without waiting for the batch window
+func TestBatcherFullBatch(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	logger, _ := pkglogging.NewLogger("", "INFO")
+
+	var batchSizes []int
+	var mu sync.Mutex
+	predictor := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		b, err := io.ReadAll(req.Body)
+		g.Expect(err).To(gomega.BeNil())
+		var request Request
+		err = json.Unmarshal(b, &request)
+		g.Expect(err).To(gomega.BeNil())
+		mu.Lock()
+		batchSizes = append(batchSizes, len(request.Instances))
+		mu.Unlock()
+		responseBytes, err := json.Marshal(Response{Predictions: request.Instances})
+		g.Expect(err).To(gomega.BeNil())
+		_, err = rw.Write(responseBytes)
+		g.Expect(err).To(gomega.BeNil())
+	}))
+	defer predictor.Close()
+	predictorSvcUrl, err := url.Parse(predictor.URL)
+	g.Expect(err).To(gomega.BeNil())
+	batchHandler := New(4, 60000, httputil.NewSingleHostReverseProxy(predictorSvcUrl), logger)
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go serveRequest(batchHandler, &wg, i)
+	}
+	wg.Wait()
+	g.Expect(time.Since(start)).To(gomega.BeNumerically("<", 10*time.Second))
+	g.Expect(batchSizes).To(gomega.Equal([]int{4, 4}))
+}
+
+// Tests that the batch window of an adaptive batcher leaves room for the predictor latency and the batch in flight
+func TestBatchWindow(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	start := time.Now()
+	scenarios := map[string]struct {
+		targetLatency  int
+		latencies      []time.Duration
+		inflight       bool
+		inflightStart  time.Time
+		expectedWindow time.Duration
+	}{
+		"fixed window": {
+			latencies:      []time.Duration{100 * time.Millisecond},
+			expectedWindow: 500 * time.Millisecond,
+		},
+		"no latency observed": {
+			targetLatency:  200,
+			expectedWindow: 200 * time.Millisecond,
+		},
+		"target bounded by max latency": {
+			targetLatency:  1000,
+			latencies:      []time.Duration{20 * time.Millisecond},
+			expectedWindow: 500 * time.Millisecond,
+		},
+		"predictor latency": {
+			targetLatency:  200,
+			latencies:      []time.Duration{20 * time.Millisecond},
+			expectedWindow: 140 * time.Millisecond,
+		},
+		"batch in flight": {
+			targetLatency:  200,
+			latencies:      []time.Duration{20 * time.Millisecond},
+			inflight:       true,
+			inflightStart:  start.Add(-20 * time.Millisecond),
+			expectedWindow: 100 * time.Millisecond,
+		},
+		"predictor slower than the target": {
+			targetLatency:  200,
+			latencies:      []time.Duration{150 * time.Millisecond},
+			expectedWindow: 0,
+		},
+	}
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			handler := &BatchHandler{MaxLatency: 500, TargetLatency: scenario.targetLatency}
+			for _, latency := range scenario.latencies {
+				handler.latency.observe(latency)
+			}
+			window := handler.batchWindow(start, scenario.inflight, scenario.inflightStart)
+			g.Expect(window).To(gomega.Equal(scenario.expectedWindow))
+		})
+	}
+}
+
+func TestLatencyEstimator(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	estimator := latencyEstimator{}
+	g.Expect(estimator.p99()).To(gomega.Equal(time.Duration(0)))
+	for i := 0; i < 100; i++ {
+		estimator.observe(10 * time.Millisecond)
+	}
+	g.Expect(estimator.p99()).To(gomega.BeNumerically("~", 10*time.Millisecond, time.Millisecond))
+	// a latency spike raises the estimate above the spike
+	estimator.observe(50 * time.Millisecond)
+	g.Expect(estimator.p99()).To(gomega.BeNumerically(">", 50*time.Millisecond))
+}
@@ -0,0 +1,284 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// decodeJSON decodes the json body keeping the numbers as json.Number, so that the tensor data is passed on exactly and
+// INT64 values beyond the float64 precision are not rounded
+func decodeJSON(body []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+// inferVerb matches the path of the v2 inference protocol requests
+var inferVerb = regexp.MustCompile(`^/v2/models/[^/]+(/versions/[^/]+)?/infer$`)
+
+// InferTensor is an input or output tensor of the v2 inference protocol, the data is flattened in row-major order
+type InferTensor struct {
+	Name       string                 `json:"name"`
+	Shape      []int                  `json:"shape"`
+	Datatype   string                 `json:"datatype"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	Data       []interface{}          `json:"data"`
+}
+
+type InferRequestedOutput struct {
+	Name       string                 `json:"name"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+}
+
+type InferRequest struct {
+	ID         string                 `json:"id,omitempty"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	Inputs     []InferTensor          `json:"inputs"`
+	Outputs    []InferRequestedOutput `json:"outputs,omitempty"`
+}
+
+type InferResponse struct {
+	ModelName    string                 `json:"model_name"`
+	ModelVersion string                 `json:"model_version,omitempty"`
+	ID           string                 `json:"id,omitempty"`
+	Parameters   map[string]interface{} `json:"parameters,omitempty"`
+	Outputs      []InferTensor          `json:"outputs"`
+}
+
+type InferErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// flattenData flattens the nested arrays of the tensor data in row-major order
+func flattenData(data []interface{}) []interface{} {
+	flattened := make([]interface{}, 0, len(data))
+	for _, element := range data {
+		if nested, ok := element.([]interface{}); ok {
+			flattened = append(flattened, flattenData(nested)...)
+		} else {
+			flattened = append(flattened, element)
+		}
+	}
+	return flattened
+}
+
+// elementCount returns the number of elements of a tensor of the shape
+func elementCount(shape []int) int {
+	count := 1
+	for _, dim := range shape {
+		count *= dim
+	}
+	return count
+}
+
+// parseInferRequest validates the request and returns it with the inputs sorted by name and their data flattened,
+// along with its batch size which is the first dimension shared by all the inputs
+func parseInferRequest(body []byte) (*InferRequest, int, error) {
+	var req InferRequest
+	if err := decodeJSON(body, &req); err != nil {
+		return nil, 0, fmt.Errorf("can't Unmarshal body")
+	}
+	if len(req.Inputs) == 0 {
+		return nil, 0, fmt.Errorf("no inputs in the request")
+	}
+	sort.Slice(req.Inputs, func(i, j int) bool {
+		return req.Inputs[i].Name < req.Inputs[j].Name
+	})
+	batchSize := -1
+	for i := range req.Inputs {
+		input := &req.Inputs[i]
+		if i > 0 && input.Name == req.Inputs[i-1].Name {
+			return nil, 0, fmt.Errorf("input %s is duplicated", input.Name)
+		}
+		if len(input.Shape) == 0 {
+			return nil, 0, fmt.Errorf("input %s has no batch dimension", input.Name)
+		}
+		for _, dim := range input.Shape {
+			if dim < 0 {
+				return nil, 0, fmt.Errorf("input %s has a negative dimension", input.Name)
+			}
+		}
+		if batchSize >= 0 && input.Shape[0] != batchSize {
+			return nil, 0, fmt.Errorf("inputs have different batch sizes")
+		}
+		batchSize = input.Shape[0]
+		input.Data = flattenData(input.Data)
+		if len(input.Data) != elementCount(input.Shape) {
+			return nil, 0, fmt.Errorf("size of input %s data is not equal to its shape %v", input.Name, input.Shape)
+		}
+	}
+	if batchSize == 0 {
+		return nil, 0, fmt.Errorf("no instances in the request")
+	}
+	return &req, batchSize, nil
+}
+
+// inferBatchKey returns the key of the batch the request goes into, requests are only batched together when their
+// inputs have the same names, datatypes and shapes apart from the batch dimension, and they request the same outputs
+// with the same parameters
+func inferBatchKey(path string, req *InferRequest) string {
+	var key strings.Builder
+	key.WriteString(path)
+	for _, input := range req.Inputs {
+		parameters, _ := json.Marshal(input.Parameters)
+		fmt.Fprintf(&key, "|%s:%s:%v:%s", input.Name, input.Datatype, input.Shape[1:], parameters)
+	}
+	parameters, _ := json.Marshal(req.Parameters)
+	outputs, _ := json.Marshal(req.Outputs)
+	fmt.Fprintf(&key, "|%s|%s", parameters, outputs)
+	return key.String()
+}
+
+// appendInferRequest concatenates the inputs of the request to the batched request along the batch dimension
+func appendInferRequest(batch *InferRequest, req *InferRequest) *InferRequest {
+	if batch == nil {
+		batch = &InferRequest{
+			Parameters: req.Parameters,
+			Inputs:     make([]InferTensor, len(req.Inputs)),
+			Outputs:    req.Outputs,
+		}
+		for i, input := range req.Inputs {
+			batch.Inputs[i] = InferTensor{
+				Name:       input.Name,
+				Shape:      append([]int{0}, input.Shape[1:]...),
+				Datatype:   input.Datatype,
+				Parameters: input.Parameters,
+				Data:       make([]interface{}, 0, len(input.Data)),
+			}
+		}
+	}
+	for i, input := range req.Inputs {
+		batch.Inputs[i].Shape[0] += input.Shape[0]
+		batch.Inputs[i].Data = append(batch.Inputs[i].Data, input.Data...)
+	}
+	return batch
+}
+
+// splitInferResponse validates that the outputs of the batched response have the batch size and splits them back to
+// the rows of a request
+func splitInferResponse(response *InferResponse, batchSize int, index []int) (*InferResponse, error) {
+	split := &InferResponse{
+		ModelName:    response.ModelName,
+		ModelVersion: response.ModelVersion,
+		Parameters:   response.Parameters,
+		Outputs:      make([]InferTensor, len(response.Outputs)),
+	}
+	for i, output := range response.Outputs {
+		if len(output.Shape) == 0 || output.Shape[0] != batchSize {
+			return nil, fmt.Errorf("batch size of output %s is not equal to the batch size of the inputs", output.Name)
+		}
+		data := flattenData(output.Data)
+		if len(data) != elementCount(output.Shape) {
+			return nil, fmt.Errorf("size of output %s data is not equal to its shape %v", output.Name, output.Shape)
+		}
+		rowSize := elementCount(output.Shape[1:])
+		rows := make([]interface{}, 0, len(index)*rowSize)
+		for _, row := range index {
+			rows = append(rows, data[row*rowSize:(row+1)*rowSize]...)
+		}
+		split.Outputs[i] = InferTensor{
+			Name:       output.Name,
+			Shape:      append([]int{len(index)}, output.Shape[1:]...),
+			Datatype:   output.Datatype,
+			Parameters: output.Parameters,
+			Data:       rows,
+		}
+	}
+	return split, nil
+}
+
+func inferErrorBody(message string) []byte {
+	body, _ := json.Marshal(InferErrorResponse{Error: message})
+	return body
+}
+
+// batchInferPredict sends the batched v2 request to the predictor and responds to each request with its rows of the
+// outputs, the error response of the predictor is returned as is to all the requests
+func (handler *BatchHandler) batchInferPredict(batcherInfo *BatcherInfo) {
+	batcherInfo.BatchID = GenerateUUID()
+	batcherInfo.InferRequest.ID = batcherInfo.BatchID
+	jsonStr, _ := json.Marshal(batcherInfo.InferRequest)
+	r := httptest.NewRequest("POST", batcherInfo.Path, bytes.NewReader(jsonStr))
+	r.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	handler.next.ServeHTTP(rr, r)
+	responseBody := rr.Body.Bytes()
+	respond := func(statusCode int, body []byte) {
+		for _, v := range batcherInfo.ContextMap {
+			*v.ChannelOut <- Response{BatchID: batcherInfo.BatchID, statusCode: statusCode, body: body}
+		}
+	}
+	if rr.Code != http.StatusOK {
+		handler.log.Errorf("error response with code %v", rr)
+		respond(rr.Code, responseBody)
+		return
+	}
+	var response InferResponse
+	if err := decodeJSON(responseBody, &response); err != nil {
+		respond(http.StatusInternalServerError, inferErrorBody(err.Error()))
+		return
+	}
+	responses := make(map[*chan Response][]byte, len(batcherInfo.ContextMap))
+	for _, v := range batcherInfo.ContextMap {
+		split, err := splitInferResponse(&response, batcherInfo.CurrentInputLen, v.Index)
+		if err != nil {
+			respond(http.StatusInternalServerError, inferErrorBody(err.Error()))
+			return
+		}
+		split.ID = v.RequestID
+		body, err := json.Marshal(split)
+		if err != nil {
+			respond(http.StatusInternalServerError, inferErrorBody(err.Error()))
+			return
+		}
+		responses[v.ChannelOut] = body
+	}
+	for channelOut, body := range responses {
+		*channelOut <- Response{BatchID: batcherInfo.BatchID, statusCode: http.StatusOK, body: body}
+	}
+}
+
+// serveInferRequest batches a v2 request and writes the response of the predictor for its rows
+func (handler *BatchHandler) serveInferRequest(w http.ResponseWriter, r *http.Request, body []byte) {
+	req, batchSize, err := parseInferRequest(body)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write(inferErrorBody(err.Error()))
+		return
+	}
+	handler.log.Infof("serving request %s", r.URL.Path)
+	response := handler.enqueue(Input{
+		Key:          inferBatchKey(r.URL.Path, req),
+		Path:         r.URL.Path,
+		BatchSize:    batchSize,
+		InferRequest: req,
+	})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.statusCode)
+	if _, err = w.Write(response.body); err != nil {
+		handler.log.Errorf("failed to write the response %v", err)
+	}
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batcher
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/onsi/gomega"
+	pkglogging "knative.dev/pkg/logging"
+)
+
+// newDoublingPredictor doubles the input tensors of the v2 requests and records the shapes of the batched inputs
+func newDoublingPredictor(t *testing.T, shapes *[][]int, mu *sync.Mutex) *httptest.Server {
+	g := gomega.NewGomegaWithT(t)
+	predictor := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		b, err := io.ReadAll(req.Body)
+		g.Expect(err).To(gomega.BeNil())
+		var request InferRequest
+		g.Expect(json.Unmarshal(b, &request)).To(gomega.Succeed())
+		mu.Lock()
+		*shapes = append(*shapes, request.Inputs[0].Shape)
+		mu.Unlock()
+		response := InferResponse{ModelName: "test", ID: request.ID}
+		for _, input := range request.Inputs {
+			output := InferTensor{Name: input.Name + "_doubled", Shape: input.Shape, Datatype: input.Datatype}
+			for _, value := range input.Data {
+				output.Data = append(output.Data, value.(float64)*2)
+			}
+			response.Outputs = append(response.Outputs, output)
+		}
+		responseBytes, err := json.Marshal(response)
+		g.Expect(err).To(gomega.BeNil())
+		_, err = rw.Write(responseBytes)
+		g.Expect(err).To(gomega.BeNil())
+	}))
+	t.Cleanup(predictor.Close)
+	return predictor
+}
+
+func TestBatcherV2(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	logger, _ := pkglogging.NewLogger("", "INFO")
+	var shapes [][]int
+	var mu sync.Mutex
+	predictor := newDoublingPredictor(t, &shapes, &mu)
+	predictorSvcUrl, err := url.Parse(predictor.URL)
+	g.Expect(err).To(gomega.BeNil())
+	batchHandler := New(32, 200, httputil.NewSingleHostReverseProxy(predictorSvcUrl), logger)
+
+	requests := map[string]struct {
+		body     string
+		expected string
+	}{
+		"single row": {
+			body:     `{"id": "1", "inputs": [{"name": "x", "shape": [1, 2], "datatype": "FP32", "data": [1, 2]}]}`,
+			expected: `{"model_name": "test", "id": "1", "outputs": [{"name": "x_doubled", "shape": [1, 2], "datatype": "FP32", "data": [2, 4]}]}`,
+		},
+		"nested data": {
+			body:     `{"id": "2", "inputs": [{"name": "x", "shape": [2, 2], "datatype": "FP32", "data": [[3, 4], [5, 6]]}]}`,
+			expected: `{"model_name": "test", "id": "2", "outputs": [{"name": "x_doubled", "shape": [2, 2], "datatype": "FP32", "data": [6, 8, 10, 12]}]}`,
+		},
+		"incompatible shape": {
+			body:     `{"id": "3", "inputs": [{"name": "x", "shape": [1, 3], "datatype": "FP32", "data": [1, 2, 3]}]}`,
+			expected: `{"model_name": "test", "id": "3", "outputs": [{"name": "x_doubled", "shape": [1, 3], "datatype": "FP32", "data": [2, 4, 6]}]}`,
+		},
+	}
+	var wg sync.WaitGroup
+	for name, request := range requests {
+		wg.Add(1)
+		go func(name string, body string, expected string) {
+			defer wg.Done()
+			r := httptest.NewRequest(http.MethodPost, "/v2/models/test/infer", strings.NewReader(body))
+			w := httptest.NewRecorder()
+			batchHandler.ServeHTTP(w, r)
+			g.Expect(w.Code).To(gomega.Equal(http.StatusOK), name)
+			g.Expect(w.Body.String()).To(gomega.MatchJSON(expected), name)
+		}(name, request.body, request.expected)
+	}
+	wg.Wait()
+	g.Expect(shapes).To(gomega.ConsistOf([]int{3, 2}, []int{1, 3}))
+}
+
+func TestBatcherV2Int64Precision(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	logger, _ := pkglogging.NewLogger("", "INFO")
+	var predictorBody string
+	// the predictor returns the inputs as outputs without decoding the data
+	predictor := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		b, err := io.ReadAll(req.Body)
+		g.Expect(err).To(gomega.BeNil())
+		predictorBody = string(b)
+		var request struct {
+			ID     string            `json:"id"`
+			Inputs []json.RawMessage `json:"inputs"`
+		}
+		g.Expect(json.Unmarshal(b, &request)).To(gomega.Succeed())
+		responseBytes, err := json.Marshal(map[string]interface{}{"model_name": "test", "id": request.ID, "outputs": request.Inputs})
+		g.Expect(err).To(gomega.BeNil())
+		_, _ = rw.Write(responseBytes)
+	})
+	batchHandler := New(32, 50, predictor, logger)
+
+	body := `{"id": "1", "inputs": [{"name": "x", "shape": [2], "datatype": "INT64", "data": [9007199254740993, -9007199254740993]}]}`
+	r := httptest.NewRequest(http.MethodPost, "/v2/models/test/infer", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	batchHandler.ServeHTTP(w, r)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	g.Expect(predictorBody).To(gomega.ContainSubstring(`"data":[9007199254740993,-9007199254740993]`))
+	g.Expect(w.Body.String()).To(gomega.ContainSubstring(`"data":[9007199254740993,-9007199254740993]`))
+	g.Expect(w.Body.String()).To(gomega.MatchJSON(
+		`{"model_name": "test", "id": "1", "outputs": [{"name": "x", "shape": [2], "datatype": "INT64", "data": [9007199254740993, -9007199254740993]}]}`))
+}
+
+func TestBatcherV2InvalidRequest(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	logger, _ := pkglogging.NewLogger("", "INFO")
+	predictor := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		t.Errorf("unexpected request to the predictor %s", req.URL.Path)
+	})
+	batchHandler := New(32, 50, predictor, logger)
+	scenarios := map[string]struct {
+		body     string
+		expected string
+	}{
+		"invalid json": {
+			body:     `{"inputs": `,
+			expected: `{"error": "can't Unmarshal body"}`,
+		},
+		"no inputs": {
+			body:     `{"inputs": []}`,
+			expected: `{"error": "no inputs in the request"}`,
+		},
+		"data does not match the shape": {
+			body:     `{"inputs": [{"name": "x", "shape": [2, 2], "datatype": "FP32", "data": [1, 2, 3]}]}`,
+			expected: `{"error": "size of input x data is not equal to its shape [2 2]"}`,
+		},
+		"different batch sizes": {
+			body: `{"inputs": [{"name": "x", "shape": [1], "datatype": "FP32", "data": [1]},
+				{"name": "y", "shape": [2], "datatype": "FP32", "data": [1, 2]}]}`,
+			expected: `{"error": "inputs have different batch sizes"}`,
+		},
+		"duplicated input": {
+			body: `{"inputs": [{"name": "x", "shape": [1], "datatype": "FP32", "data": [1]},
+				{"name": "x", "shape": [1], "datatype": "FP32", "data": [1]}]}`,
+			expected: `{"error": "input x is duplicated"}`,
+		},
+	}
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/v2/models/test/infer", strings.NewReader(scenario.body))
+			w := httptest.NewRecorder()
+			batchHandler.ServeHTTP(w, r)
+			g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+			g.Expect(w.Body.String()).To(gomega.MatchJSON(scenario.expected))
+		})
+	}
+}
+
+func TestSplitInferResponse(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	response := &InferResponse{
+		ModelName: "test",
+		Outputs: []InferTensor{
+			{Name: "label", Shape: []int{3}, Datatype: "BYTES", Data: []interface{}{"a", "b", "c"}},
+			{Name: "scores", Shape: []int{3, 2}, Datatype: "FP32", Data: []interface{}{[]interface{}{1.0, 2.0},
+				[]interface{}{3.0, 4.0}, []interface{}{5.0, 6.0}}},
+		},
+	}
+	split, err := splitInferResponse(response, 3, []int{1, 2})
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(split.Outputs).To(gomega.Equal([]InferTensor{
+		{Name: "label", Shape: []int{2}, Datatype: "BYTES", Data: []interface{}{"b", "c"}},
+		{Name: "scores", Shape: []int{2, 2}, Datatype: "FP32", Data: []interface{}{3.0, 4.0, 5.0, 6.0}},
+	}))
+
+	_, err = splitInferResponse(response, 4, []int{0})
+	g.Expect(err).To(gomega.MatchError("batch size of output label is not equal to the batch size of the inputs"))
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batcher
+
+import (
+	"time"
+)
+
+// latencyEstimator estimates the p99 latency of the predictor from the smoothed mean and mean deviation of the
+// observed batch latencies, the same way TCP estimates the retransmission timeout from the round trip times
+type latencyEstimator struct {
+	observed  bool
+	mean      time.Duration
+	deviation time.Duration
+}
+
+func (e *latencyEstimator) observe(latency time.Duration) {
+	if !e.observed {
+		e.observed = true
+		e.mean = latency
+		e.deviation = latency / 2
+		return
+	}
+	diff := latency - e.mean
+	if diff < 0 {
+		diff = -diff
+	}
+	e.deviation += (diff - e.deviation) / 4
+	e.mean += (latency - e.mean) / 8
+}
+
+// p99 returns the estimated p99 latency, it is 0 until a latency is observed
+func (e *latencyEstimator) p99() time.Duration {
+	return e.mean + 4*e.deviation
+}
+
+// batchWindow returns how long the batch started at start waits for more requests. Without a target latency it is
+// MaxLatency, otherwise it is the part of the target left once the batch waited for the batch in flight and was
+// predicted, bounded by MaxLatency.
+func (handler *BatchHandler) batchWindow(start time.Time, inflight bool, inflightStart time.Time) time.Duration {
+	maxLatency := time.Duration(handler.MaxLatency) * time.Millisecond
+	if handler.TargetLatency <= 0 {
+		return maxLatency
+	}
+	estimate := handler.latency.p99()
+	var queueDelay time.Duration
+	if inflight {
+		queueDelay = inflightStart.Add(estimate).Sub(start)
+		if queueDelay < 0 {
+			queueDelay = 0
+		}
+	}
+	window := time.Duration(handler.TargetLatency)*time.Millisecond - estimate - queueDelay
+	if window < 0 {
+		return 0
+	}
+	if window > maxLatency {
+		return maxLatency
+	}
+	return window
+}
+
+// stopTimer stops the timer and drains its channel so it can be reset
+func stopTimer(timer *time.Timer) {
+	if !timer.Stop() {
+		select {
+		case <-timer.C:
+		default:
+		}
+	}
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package constants
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"regexp"
@@ -52,6 +54,22 @@ var (
 const (
 	RouterHeadersPropagateEnvVar = "PROPAGATE_HEADERS"
 	InferenceGraphLabel          = "serving.kserve.io/inferencegraph"
+	// RouterGraphNameEnvVar is set by knative to the name of the graph service, it labels the router metrics
+	RouterGraphNameEnvVar = "K_SERVICE"
+	// RouterH2CPortName names the router port when the graph serves gRPC, so that knative proxies it over h2c
+	RouterH2CPortName = "h2c"
+	RouterPort        = int32(8080)
+)
+
+// InferenceGraph router tracing env variables, these are the standard OpenTelemetry sdk env variables
+const (
+	RouterTracesExporterEnvVar     = "OTEL_TRACES_EXPORTER"
+	RouterTracesEndpointEnvVar     = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	RouterTracesInsecureEnvVar     = "OTEL_EXPORTER_OTLP_INSECURE"
+	RouterTracesSamplerEnvVar      = "OTEL_TRACES_SAMPLER"
+	RouterTracesSamplerArgEnvVar   = "OTEL_TRACES_SAMPLER_ARG"
+	RouterTracesServiceNameEnvVar  = "OTEL_SERVICE_NAME"
+	RouterTracesRatioSamplerPolicy = "parentbased_traceidratio"
 )
 
 // TrainedModel Constants
@@ -110,6 +128,7 @@ var (
 	BatcherMaxBatchSizeInternalAnnotationKey         = InferenceServiceInternalAnnotationsPrefix + "/batcher-max-batchsize"
 	BatcherMaxLatencyInternalAnnotationKey           = InferenceServiceInternalAnnotationsPrefix + "/batcher-max-latency"
 	BatcherTimeoutInternalAnnotationKey              = InferenceServiceInternalAnnotationsPrefix + "/batcher-timeout"
+	BatcherTargetLatencyInternalAnnotationKey        = InferenceServiceInternalAnnotationsPrefix + "/batcher-target-latency"
 	AgentShouldInjectAnnotationKey                   = InferenceServiceInternalAnnotationsPrefix + "/agent"
 	AgentModelConfigVolumeNameAnnotationKey          = InferenceServiceInternalAnnotationsPrefix + "/configVolumeName"
 	AgentModelConfigMountPathAnnotationKey           = InferenceServiceInternalAnnotationsPrefix + "/configMountPath"
@@ -309,6 +328,23 @@ const (
 	ModelDir              = DefaultModelLocalMountPath
 )
 
+// Hot-reloadable InferenceGraph spec
+const (
+	InferenceGraphConfigVolumeName = "graph-config"
+	InferenceGraphConfigDir        = "/mnt/graph"
+	InferenceGraphSpecFileName     = "graph.json"
+)
+
+// InferenceGraph step auth credentials mounted in the router
+const (
+	InferenceGraphAuthTokensVolumeName       = "graph-auth-tokens"
+	InferenceGraphAuthTokensDir              = "/var/run/secrets/kserve/graph/tokens"
+	InferenceGraphAuthSecretsDir             = "/var/run/secrets/kserve/graph/secrets"
+	InferenceGraphAuthSecretVolumePrefix     = "graph-auth-secret-"
+	InferenceGraphAuthTokenExpirationSeconds = 3600
+	InferenceGraphAuthDefaultTokenFileName   = "token"
+)
+
 var (
 	ServiceAnnotationDisallowedList = []string{
 		autoscaling.MinScaleAnnotationKey,
@@ -491,6 +527,19 @@ func ModelConfigName(inferenceserviceName string, shardId int) string {
 	return fmt.Sprintf("modelconfig-%s-%d", inferenceserviceName, shardId)
 }
 
+func InferenceGraphConfigName(inferenceGraphName string) string {
+	return fmt.Sprintf("graphconfig-%s", inferenceGraphName)
+}
+
+// InferenceGraphAuthTokenFileName is the file of the projected ServiceAccount token with the audience
+func InferenceGraphAuthTokenFileName(audience string) string {
+	if audience == "" {
+		return InferenceGraphAuthDefaultTokenFileName
+	}
+	hash := sha256.Sum256([]byte(audience))
+	return fmt.Sprintf("%s-%s", InferenceGraphAuthDefaultTokenFileName, hex.EncodeToString(hash[:8]))
+}
+
 func InferenceServicePrefix(name string) string {
 	return fmt.Sprintf("/v1/models/%s", name)
 }
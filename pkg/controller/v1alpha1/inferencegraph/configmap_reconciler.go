@@ -0,0 +1,110 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inferencegraph
+
+import (
+	"context"
+	"encoding/json"
+
+	v1alpha1api "github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	"github.com/kserve/kserve/pkg/constants"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GraphConfigMapReconciler reconciles the ConfigMap holding the spec of a hot-reloadable inference graph, the router
+// mounts the ConfigMap and reloads the graph when it changes instead of rolling out a new revision
+type GraphConfigMapReconciler struct {
+	client    client.Client
+	scheme    *runtime.Scheme
+	ConfigMap *v1.ConfigMap
+}
+
+func NewGraphConfigMapReconciler(client client.Client,
+	scheme *runtime.Scheme,
+	configMap *v1.ConfigMap) *GraphConfigMapReconciler {
+	return &GraphConfigMapReconciler{
+		client:    client,
+		scheme:    scheme,
+		ConfigMap: configMap,
+	}
+}
+
+// GraphConfigMapSelector selects the ConfigMaps holding the graph specs. The manager only caches these ConfigMaps so
+// that watching the graph ConfigMaps does not cache all the ConfigMaps of the cluster.
+func GraphConfigMapSelector() labels.Selector {
+	requirement, _ := labels.NewRequirement(constants.InferenceGraphLabel, selection.Exists, nil)
+	return labels.NewSelector().Add(*requirement)
+}
+
+func createGraphConfigMap(graph *v1alpha1api.InferenceGraph) (*v1.ConfigMap, error) {
+	spec, err := json.Marshal(graph.Spec)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      constants.InferenceGraphConfigName(graph.Name),
+			Namespace: graph.Namespace,
+			Labels: map[string]string{
+				constants.InferenceGraphLabel: graph.Name,
+			},
+		},
+		Data: map[string]string{
+			constants.InferenceGraphSpecFileName: string(spec),
+		},
+	}, nil
+}
+
+func (r *GraphConfigMapReconciler) Reconcile() error {
+	desired := r.ConfigMap
+	existing := &v1.ConfigMap{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, existing)
+	if err != nil {
+		if apierr.IsNotFound(err) {
+			log.Info("Creating inference graph config map", "namespace", desired.Namespace, "name", desired.Name)
+			return r.client.Create(context.TODO(), desired)
+		}
+		return err
+	}
+	if equality.Semantic.DeepEqual(desired.Data, existing.Data) &&
+		equality.Semantic.DeepEqual(desired.Labels, existing.Labels) {
+		return nil
+	}
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		log.Info("Updating inference graph config map", "namespace", desired.Namespace, "name", desired.Name)
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, existing); err != nil {
+			return err
+		}
+		existing.Data = desired.Data
+		existing.Labels = desired.Labels
+		return r.client.Update(context.TODO(), existing)
+	})
+	if err != nil {
+		return errors.Wrapf(err, "fails to update inference graph config map")
+	}
+	return nil
+}
@@ -19,19 +19,21 @@ limitations under the License.
 // +kubebuilder:rbac:groups=serving.knative.dev,resources=services,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=serving.knative.dev,resources=services/finalizers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=serving.knative.dev,resources=services/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update
 package inferencegraph
 
 import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
+
 	isvcutils "github.com/kserve/kserve/pkg/controller/v1beta1/inferenceservice/utils"
 	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/client-go/util/retry"
 
 	"github.com/go-logr/logr"
 	v1alpha1api "github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
-	"github.com/kserve/kserve/pkg/apis/serving/v1beta1"
 	v1beta1api "github.com/kserve/kserve/pkg/apis/serving/v1beta1"
 	"github.com/kserve/kserve/pkg/constants"
 	"github.com/pkg/errors"
@@ -47,6 +49,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -85,6 +88,37 @@ type RouterConfig struct {
 		want to transform headers keys or values before passing down to nodes.
 	*/
 	Headers map[string][]string `json:"headers"`
+	/*
+		Example of how to export the router spans to an OpenTelemetry collector in router config:
+		tracing: {
+		 "exporter": "otlp",
+		 "endpoint": "http://otel-collector.observability:4317",
+		 "insecure": true,
+		 "samplingRatio": "0.1"
+		}
+	*/
+	Tracing *RouterTracingConfig `json:"tracing,omitempty"`
+	// HotReload delivers the graph spec to the router through a mounted ConfigMap, spec changes are then reloaded
+	// by the running router instead of rolling out a new knative revision
+	HotReload bool `json:"hotReload,omitempty"`
+	// MaxBodySize is the max size of the requests and of the step responses buffered by the router as a quantity,
+	// e.g. 100Mi, the responses the router streams back to the client are not limited
+	MaxBodySize string `json:"maxBodySize,omitempty"`
+	// EnableTrace serves the execution trace of a request on POST /_graph/trace, the trace includes excerpts of the
+	// step requests and responses
+	EnableTrace bool `json:"enableTrace,omitempty"`
+}
+
+// RouterTracingConfig configures the export of the OpenTelemetry spans of the router
+type RouterTracingConfig struct {
+	// Exporter is one of otlp, stdout or none, spans are not exported when not set
+	Exporter string `json:"exporter,omitempty"`
+	// Endpoint is the gRPC endpoint of the OTLP collector
+	Endpoint string `json:"endpoint,omitempty"`
+	// Insecure disables TLS for the connection to the OTLP collector
+	Insecure bool `json:"insecure,omitempty"`
+	// SamplingRatio is the ratio of the traces started by the router which are sampled, defaults to all traces
+	SamplingRatio string `json:"samplingRatio,omitempty"`
 }
 
 func getRouterConfigs(configMap *v1.ConfigMap) (*RouterConfig, error) {
@@ -110,6 +144,20 @@ func getRouterConfigs(configMap *v1.ConfigMap) (*RouterConfig, error) {
 		}
 	}
 
+	if routerConfig.MaxBodySize != "" {
+		if maxBodySize, err := resource.ParseQuantity(routerConfig.MaxBodySize); err != nil || maxBodySize.Sign() <= 0 {
+			return routerConfig, fmt.Errorf("Invalid max body size for router %q, the size must be a positive quantity",
+				routerConfig.MaxBodySize)
+		}
+	}
+
+	if tracing := routerConfig.Tracing; tracing != nil && tracing.SamplingRatio != "" {
+		if ratio, err := strconv.ParseFloat(tracing.SamplingRatio, 64); err != nil || ratio < 0 || ratio > 1 {
+			return routerConfig, fmt.Errorf("Invalid tracing sampling ratio for router %q, the ratio must be between 0 and 1",
+				tracing.SamplingRatio)
+		}
+	}
+
 	return routerConfig, nil
 }
 
@@ -138,30 +186,13 @@ func (r *InferenceGraphReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	if err != nil {
 		return reconcile.Result{}, err
 	}
-	// resolve service urls
-	for node, router := range graph.Spec.Nodes {
-		for i, route := range router.Steps {
-			isvc := v1beta1.InferenceService{}
-			if route.ServiceName != "" {
-				err := r.Client.Get(ctx, types.NamespacedName{Namespace: graph.Namespace, Name: route.ServiceName}, &isvc)
-				if err == nil {
-					if graph.Spec.Nodes[node].Steps[i].ServiceURL == "" {
-						serviceUrl, err := isvcutils.GetPredictorEndpoint(&isvc)
-						if err == nil {
-							graph.Spec.Nodes[node].Steps[i].ServiceURL = serviceUrl
-						} else {
-							r.Log.Info("inference service is not ready", "name", route.ServiceName)
-							return reconcile.Result{Requeue: true}, errors.Wrapf(err, "service %s is not ready", route.ServiceName)
-						}
-					}
-
-				} else {
-					r.Log.Info("inference service is not found", "name", route.ServiceName)
-					return reconcile.Result{Requeue: true}, errors.Wrapf(err, "Failed to find graph service %s", route.ServiceName)
-				}
-			}
-		}
+	serviceStatuses, err := r.getServiceStatuses(ctx, graph)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "fails to get the status of the graph services")
 	}
+	// steps calling services which do not have an address yet are left unresolved, the router answers them with 503
+	// and the graph is reconciled again when the services change
+	resolveServiceURLs(graph, serviceStatuses)
 	deployConfig, err := v1beta1api.NewDeployConfig(r.Client)
 	if err != nil {
 		return reconcile.Result{}, errors.Wrapf(err, "fails to create DeployConfig")
@@ -169,36 +200,53 @@ func (r *InferenceGraphReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 
 	deploymentMode := isvcutils.GetDeploymentMode(graph.ObjectMeta.Annotations, deployConfig)
 	r.Log.Info("Inference service deployment mode ", "deployment mode ", deploymentMode)
-	if deploymentMode == constants.RawDeployment {
-		err := fmt.Errorf("RawDeployment mode is not supported for InferenceGraph")
-		r.Log.Error(err, "name", graph.GetName())
-		return reconcile.Result{}, err
-	}
-	//@TODO check raw deployment mode
-	desired := createKnativeService(graph.ObjectMeta, graph, routerConfig)
-	err = controllerutil.SetControllerReference(graph, desired, r.Scheme)
-	if err != nil {
-		return reconcile.Result{}, err
-	}
-	knativeReconciler := NewGraphKnativeServiceReconciler(r.Client, r.Scheme, desired)
-	ksvcStatus, err := knativeReconciler.Reconcile()
-	if err != nil {
-		r.Log.Error(err, "failed to reconcile inference graph ksvc", "name", graph.GetName())
-		return reconcile.Result{}, errors.Wrapf(err, "fails to reconcile inference graph ksvc")
+	if routerConfig.HotReload {
+		graphConfigMap, err := createGraphConfigMap(graph)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if err := controllerutil.SetControllerReference(graph, graphConfigMap, r.Scheme); err != nil {
+			return reconcile.Result{}, err
+		}
+		if err := NewGraphConfigMapReconciler(r.Client, r.Scheme, graphConfigMap).Reconcile(); err != nil {
+			r.Log.Error(err, "failed to reconcile inference graph config map", "name", graph.GetName())
+			return reconcile.Result{}, errors.Wrapf(err, "fails to reconcile inference graph config map")
+		}
 	}
+	if deploymentMode == constants.RawDeployment {
+		deployment, url, err := handleInferenceGraphRawDeployment(r.Client, r.Scheme, graph, routerConfig)
+		if err != nil {
+			r.Log.Error(err, "failed to reconcile inference graph raw deployment", "name", graph.GetName())
+			return reconcile.Result{}, err
+		}
+		r.Log.Info("updating inference graph status", "deployment", deployment.Status)
+		propagateRawStatus(graph, deployment, url)
+	} else {
+		desired := createKnativeService(graph.ObjectMeta, graph, routerConfig)
+		err = controllerutil.SetControllerReference(graph, desired, r.Scheme)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		knativeReconciler := NewGraphKnativeServiceReconciler(r.Client, r.Scheme, desired)
+		ksvcStatus, err := knativeReconciler.Reconcile()
+		if err != nil {
+			r.Log.Error(err, "failed to reconcile inference graph ksvc", "name", graph.GetName())
+			return reconcile.Result{}, errors.Wrapf(err, "fails to reconcile inference graph ksvc")
+		}
 
-	r.Log.Info("updating inference graph status", "status", ksvcStatus)
-	graph.Status.Conditions = ksvcStatus.Status.Conditions
-	//@TODO Need to check the status of all the graph components, find the inference services from all the nodes and collect the status
-	for _, con := range ksvcStatus.Status.Conditions {
-		if con.Type == apis.ConditionReady {
-			if con.Status == "True" {
-				graph.Status.URL = ksvcStatus.URL
-			} else {
-				graph.Status.URL = nil
+		r.Log.Info("updating inference graph status", "status", ksvcStatus)
+		graph.Status.Conditions = ksvcStatus.Status.Conditions
+		for _, con := range ksvcStatus.Status.Conditions {
+			if con.Type == apis.ConditionReady {
+				if con.Status == "True" {
+					graph.Status.URL = ksvcStatus.URL
+				} else {
+					graph.Status.URL = nil
+				}
 			}
 		}
 	}
+	propagateServiceStatuses(graph, serviceStatuses)
 	if err := r.updateStatus(graph); err != nil {
 		r.Recorder.Eventf(graph, v1.EventTypeWarning, "InternalError", err.Error())
 		return reconcile.Result{}, err
@@ -257,11 +305,17 @@ func (r *InferenceGraphReconciler) SetupWithManager(mgr ctrl.Manager, deployConf
 		return ctrl.NewControllerManagedBy(mgr).
 			For(&v1alpha1api.InferenceGraph{}).
 			Owns(&appsv1.Deployment{}).
+			Owns(&v1.ConfigMap{}).
+			Watches(&v1beta1api.InferenceService{}, handler.EnqueueRequestsFromMapFunc(r.findGraphsForService)).
 			Complete(r)
 	} else {
+		// graphs can still be deployed in RawDeployment mode with the deployment mode annotation
 		return ctrl.NewControllerManagedBy(mgr).
 			For(&v1alpha1api.InferenceGraph{}).
 			Owns(&knservingv1.Service{}).
+			Owns(&appsv1.Deployment{}).
+			Owns(&v1.ConfigMap{}).
+			Watches(&v1beta1api.InferenceService{}, handler.EnqueueRequestsFromMapFunc(r.findGraphsForService)).
 			Complete(r)
 	}
 }
@@ -22,10 +22,16 @@ import (
 	"github.com/kserve/kserve/pkg/constants"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/kmp"
 	"knative.dev/pkg/ptr"
 	knservingdefaults "knative.dev/serving/pkg/apis/config"
@@ -133,6 +139,8 @@ var _ = Describe("Inference Graph controller test", func() {
 									"autoscaling.knative.dev/min-scale": "1",
 									"autoscaling.knative.dev/class":     "kpa.autoscaling.knative.dev",
 									"serving.kserve.io/deploymentMode":  "Serverless",
+									"prometheus.io/port":                "8080",
+									"prometheus.io/path":                "/metrics",
 								},
 							},
 							Spec: knservingv1.RevisionSpec{
@@ -257,6 +265,8 @@ var _ = Describe("Inference Graph controller test", func() {
 									"autoscaling.knative.dev/min-scale": "1",
 									"autoscaling.knative.dev/class":     "kpa.autoscaling.knative.dev",
 									"serving.kserve.io/deploymentMode":  "Serverless",
+									"prometheus.io/port":                "8080",
+									"prometheus.io/path":                "/metrics",
 								},
 							},
 							Spec: knservingv1.RevisionSpec{
@@ -395,6 +405,8 @@ var _ = Describe("Inference Graph controller test", func() {
 									"autoscaling.knative.dev/min-scale": "1",
 									"autoscaling.knative.dev/class":     "kpa.autoscaling.knative.dev",
 									"serving.kserve.io/deploymentMode":  "Serverless",
+									"prometheus.io/port":                "8080",
+									"prometheus.io/path":                "/metrics",
 								},
 							},
 							Spec: knservingv1.RevisionSpec{
@@ -461,4 +473,533 @@ var _ = Describe("Inference Graph controller test", func() {
 		})
 	})
 
+	Context("When the router config has tracing", func() {
+		It("Should configure the router tracing through env vars", func() {
+			configMap := &v1.ConfigMap{
+				Data: map[string]string{
+					"router": `{
+					  "image": "kserve/router:v0.10.0",
+					  "memoryRequest": "100Mi",
+					  "memoryLimit": "500Mi",
+					  "cpuRequest": "100m",
+					  "cpuLimit": "100m",
+					  "tracing": {
+						"exporter": "otlp",
+						"endpoint": "http://otel-collector.observability:4317",
+						"insecure": true,
+						"samplingRatio": "0.1"
+					  }
+				}`,
+				},
+			}
+			routerConfig, err := getRouterConfigs(configMap)
+			Expect(err).NotTo(HaveOccurred())
+			ig := &v1alpha1.InferenceGraph{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "tracing",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.InferenceGraphSpec{
+					Nodes: map[string]v1alpha1.InferenceRouter{
+						v1alpha1.GraphRootNodeName: {
+							RouterType: v1alpha1.Sequence,
+							Steps: []v1alpha1.InferenceStep{
+								{
+									InferenceTarget: v1alpha1.InferenceTarget{
+										ServiceURL: "http://someservice.exmaple.com",
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			service := createKnativeService(ig.ObjectMeta, ig, routerConfig)
+			Expect(service.Spec.Template.Spec.Containers[0].Env).To(Equal([]v1.EnvVar{
+				{Name: "OTEL_TRACES_EXPORTER", Value: "otlp"},
+				{Name: "OTEL_SERVICE_NAME", Value: "tracing"},
+				{Name: "OTEL_EXPORTER_OTLP_ENDPOINT", Value: "http://otel-collector.observability:4317"},
+				{Name: "OTEL_EXPORTER_OTLP_INSECURE", Value: "true"},
+				{Name: "OTEL_TRACES_SAMPLER", Value: "parentbased_traceidratio"},
+				{Name: "OTEL_TRACES_SAMPLER_ARG", Value: "0.1"},
+			}))
+
+			configMap.Data["router"] = `{"image": "kserve/router:v0.10.0", "memoryRequest": "100Mi", "memoryLimit": "500Mi",
+				"cpuRequest": "100m", "cpuLimit": "100m", "tracing": {"exporter": "stdout", "samplingRatio": "2"}}`
+			_, err = getRouterConfigs(configMap)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("When the graph sets both the scaling fields and the autoscaling annotations", func() {
+		It("Should keep the autoscaling annotations of the graph", func() {
+			configMap := &v1.ConfigMap{Data: configs}
+			routerConfig, err := getRouterConfigs(configMap)
+			Expect(err).NotTo(HaveOccurred())
+			minReplicas := 0
+			scaleTarget := 10
+			scaleMetric := v1alpha1.MetricRPS
+			ig := &v1alpha1.InferenceGraph{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "scaling",
+					Namespace: "default",
+					Annotations: map[string]string{
+						"autoscaling.knative.dev/min-scale": "2",
+						"autoscaling.knative.dev/max-scale": "4",
+					},
+				},
+				Spec: v1alpha1.InferenceGraphSpec{
+					Nodes: map[string]v1alpha1.InferenceRouter{
+						v1alpha1.GraphRootNodeName: {
+							RouterType: v1alpha1.Sequence,
+							Steps: []v1alpha1.InferenceStep{
+								{
+									InferenceTarget: v1alpha1.InferenceTarget{
+										ServiceURL: "http://someservice.exmaple.com",
+									},
+								},
+							},
+						},
+					},
+					MinReplicas: &minReplicas,
+					MaxReplicas: 5,
+					ScaleTarget: &scaleTarget,
+					ScaleMetric: &scaleMetric,
+				},
+			}
+			service := createKnativeService(ig.ObjectMeta, ig, routerConfig)
+			annotations := service.Spec.Template.Annotations
+			Expect(annotations).To(HaveKeyWithValue("autoscaling.knative.dev/min-scale", "2"))
+			Expect(annotations).To(HaveKeyWithValue("autoscaling.knative.dev/max-scale", "4"))
+			Expect(annotations).To(HaveKeyWithValue("autoscaling.knative.dev/target", "10"))
+			Expect(annotations).To(HaveKeyWithValue("autoscaling.knative.dev/metric", "rps"))
+		})
+	})
+
+	Context("When the router config has hot reload", func() {
+		It("Should mount the graph spec from a config map", func() {
+			configMap := &v1.ConfigMap{
+				Data: map[string]string{
+					"router": `{"image": "kserve/router:v0.10.0", "memoryRequest": "100Mi", "memoryLimit": "500Mi",
+						"cpuRequest": "100m", "cpuLimit": "100m", "hotReload": true}`,
+				},
+			}
+			routerConfig, err := getRouterConfigs(configMap)
+			Expect(err).NotTo(HaveOccurred())
+			ig := &v1alpha1.InferenceGraph{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "hotreload",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.InferenceGraphSpec{
+					Nodes: map[string]v1alpha1.InferenceRouter{
+						v1alpha1.GraphRootNodeName: {
+							RouterType: v1alpha1.Sequence,
+							Steps: []v1alpha1.InferenceStep{
+								{
+									InferenceTarget: v1alpha1.InferenceTarget{
+										ServiceURL: "http://someservice.exmaple.com",
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			service := createKnativeService(ig.ObjectMeta, ig, routerConfig)
+			podSpec := service.Spec.Template.Spec.PodSpec
+			Expect(podSpec.Containers[0].Args).To(Equal([]string{"--graph-config-dir", "/mnt/graph"}))
+			Expect(podSpec.Containers[0].VolumeMounts).To(Equal([]v1.VolumeMount{
+				{Name: "graph-config", MountPath: "/mnt/graph", ReadOnly: true},
+			}))
+			Expect(podSpec.Volumes).To(HaveLen(1))
+			Expect(podSpec.Volumes[0].ConfigMap.Name).To(Equal("graphconfig-hotreload"))
+
+			graphConfigMap, err := createGraphConfigMap(ig)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(graphConfigMap.Name).To(Equal("graphconfig-hotreload"))
+			Expect(graphConfigMap.Data["graph.json"]).To(MatchJSON(
+				`{"nodes": {"root": {"routerType": "Sequence", "steps": [{"serviceUrl": "http://someservice.exmaple.com"}]}}, "resources": {}}`))
+			// the manager only caches the graph config maps
+			Expect(GraphConfigMapSelector().Matches(labels.Set(graphConfigMap.Labels))).To(BeTrue())
+			Expect(GraphConfigMapSelector().Matches(labels.Set{"app": "other"})).To(BeFalse())
+		})
+	})
+
+	Context("When the router config has a max body size", func() {
+		It("Should pass the max body size in bytes to the router", func() {
+			configMap := &v1.ConfigMap{
+				Data: map[string]string{
+					"router": `{"image": "kserve/router:v0.10.0", "memoryRequest": "100Mi", "memoryLimit": "500Mi",
+						"cpuRequest": "100m", "cpuLimit": "100m", "maxBodySize": "16Mi"}`,
+				},
+			}
+			routerConfig, err := getRouterConfigs(configMap)
+			Expect(err).NotTo(HaveOccurred())
+			ig := &v1alpha1.InferenceGraph{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "maxbodysize",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.InferenceGraphSpec{
+					Nodes: map[string]v1alpha1.InferenceRouter{
+						v1alpha1.GraphRootNodeName: {
+							RouterType: v1alpha1.Sequence,
+							Steps: []v1alpha1.InferenceStep{
+								{
+									InferenceTarget: v1alpha1.InferenceTarget{
+										ServiceURL: "http://someservice.exmaple.com",
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			service := createKnativeService(ig.ObjectMeta, ig, routerConfig)
+			Expect(service.Spec.Template.Spec.Containers[0].Args[2:]).To(Equal([]string{"--max-body-size", "16777216"}))
+
+			routerConfig.EnableTrace = true
+			service = createKnativeService(ig.ObjectMeta, ig, routerConfig)
+			Expect(service.Spec.Template.Spec.Containers[0].Args[2:]).To(Equal([]string{"--max-body-size", "16777216", "--enable-trace"}))
+
+			configMap.Data["router"] = `{"image": "kserve/router:v0.10.0", "memoryRequest": "100Mi", "memoryLimit": "500Mi",
+				"cpuRequest": "100m", "cpuLimit": "100m", "maxBodySize": "-1Mi"}`
+			_, err = getRouterConfigs(configMap)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("When the root node speaks the v2 protocol", func() {
+		It("Should name the router port h2c so that gRPC is proxied over http2", func() {
+			configMap := &v1.ConfigMap{
+				Data: map[string]string{
+					"router": `{"image": "kserve/router:v0.10.0", "memoryRequest": "100Mi", "memoryLimit": "500Mi",
+						"cpuRequest": "100m", "cpuLimit": "100m"}`,
+				},
+			}
+			routerConfig, err := getRouterConfigs(configMap)
+			Expect(err).NotTo(HaveOccurred())
+			ig := &v1alpha1.InferenceGraph{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "grpcgraph",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.InferenceGraphSpec{
+					Nodes: map[string]v1alpha1.InferenceRouter{
+						v1alpha1.GraphRootNodeName: {
+							RouterType: v1alpha1.Sequence,
+							Steps: []v1alpha1.InferenceStep{
+								{
+									InferenceTarget: v1alpha1.InferenceTarget{
+										ServiceURL: "http://someservice.exmaple.com",
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			service := createKnativeService(ig.ObjectMeta, ig, routerConfig)
+			Expect(service.Spec.Template.Spec.Containers[0].Ports).To(BeEmpty())
+
+			protocolV2 := constants.ProtocolV2
+			root := ig.Spec.Nodes[v1alpha1.GraphRootNodeName]
+			root.ProtocolVersion = &protocolV2
+			ig.Spec.Nodes[v1alpha1.GraphRootNodeName] = root
+			expectedPorts := []v1.ContainerPort{
+				{
+					Name:          "h2c",
+					ContainerPort: 8080,
+					Protocol:      v1.ProtocolTCP,
+				},
+			}
+			service = createKnativeService(ig.ObjectMeta, ig, routerConfig)
+			Expect(service.Spec.Template.Spec.Containers[0].Ports).To(Equal(expectedPorts))
+
+			podSpec, err := createInferenceGraphRawPodSpec(ig, routerConfig)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(podSpec.Containers[0].Ports).To(Equal(expectedPorts))
+		})
+	})
+
+	Context("When the graph steps have auth", func() {
+		It("Should mount the service account tokens and secrets of the steps and their mirrors", func() {
+			configMap := &v1.ConfigMap{
+				Data: map[string]string{
+					"router": `{"image": "kserve/router:v0.10.0", "memoryRequest": "100Mi", "memoryLimit": "500Mi",
+						"cpuRequest": "100m", "cpuLimit": "100m"}`,
+				},
+			}
+			routerConfig, err := getRouterConfigs(configMap)
+			Expect(err).NotTo(HaveOccurred())
+			secretHeader := func(key string) *v1alpha1.StepAuth {
+				return &v1alpha1.StepAuth{
+					Type:   v1alpha1.SecretHeader,
+					Header: "X-Api-Key",
+					SecretKeyRef: &v1.SecretKeySelector{
+						LocalObjectReference: v1.LocalObjectReference{Name: "model-credentials"},
+						Key:                  key,
+					},
+				}
+			}
+			ig := &v1alpha1.InferenceGraph{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "auth",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.InferenceGraphSpec{
+					Nodes: map[string]v1alpha1.InferenceRouter{
+						v1alpha1.GraphRootNodeName: {
+							RouterType: v1alpha1.Sequence,
+							Steps: []v1alpha1.InferenceStep{
+								{
+									InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: "http://model1.example.com"},
+									Auth:            &v1alpha1.StepAuth{Type: v1alpha1.ForwardToken},
+								},
+								{
+									InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: "http://model2.example.com"},
+									Auth:            &v1alpha1.StepAuth{Type: v1alpha1.ServiceAccountToken, Audience: "models"},
+								},
+								{
+									InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: "http://model3.example.com"},
+									Auth:            secretHeader("apiKey"),
+								},
+								{
+									InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: "http://model4.example.com"},
+									Mirror: &v1alpha1.StepMirror{
+										InferenceTarget: v1alpha1.InferenceTarget{ServiceURL: "http://candidate.example.com"},
+										Auth:            secretHeader("otherKey"),
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			service := createKnativeService(ig.ObjectMeta, ig, routerConfig)
+			podSpec := service.Spec.Template.Spec.PodSpec
+			Expect(podSpec.Containers[0].VolumeMounts).To(Equal([]v1.VolumeMount{
+				{Name: "graph-auth-tokens", MountPath: "/var/run/secrets/kserve/graph/tokens", ReadOnly: true},
+				{Name: "graph-auth-secret-0", MountPath: "/var/run/secrets/kserve/graph/secrets/model-credentials", ReadOnly: true},
+			}))
+			Expect(podSpec.Volumes).To(HaveLen(2))
+			Expect(podSpec.Volumes[0].Projected.Sources).To(Equal([]v1.VolumeProjection{
+				{
+					ServiceAccountToken: &v1.ServiceAccountTokenProjection{
+						Audience:          "models",
+						ExpirationSeconds: ptr.Int64(3600),
+						Path:              constants.InferenceGraphAuthTokenFileName("models"),
+					},
+				},
+			}))
+			Expect(podSpec.Volumes[1].Secret.SecretName).To(Equal("model-credentials"))
+			Expect(podSpec.Volumes[1].Secret.Items).To(Equal([]v1.KeyToPath{
+				{Key: "apiKey", Path: "apiKey"},
+				{Key: "otherKey", Path: "otherKey"},
+			}))
+		})
+	})
+
+	Context("When creating an IG in RawDeployment mode", func() {
+		It("Should create a deployment, service, HPA and ingress for the router", func() {
+			By("By creating a new InferenceGraph")
+			var configMap = &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      constants.InferenceServiceConfigMapName,
+					Namespace: constants.KServeNamespace,
+				},
+				Data: configs,
+			}
+			Expect(k8sClient.Create(context.TODO(), configMap)).NotTo(HaveOccurred())
+			defer k8sClient.Delete(context.TODO(), configMap)
+			graphName := "rawgraph"
+			var serviceKey = types.NamespacedName{Name: graphName, Namespace: "default"}
+			ctx := context.Background()
+			maxReplicas := 3
+			ig := &v1alpha1.InferenceGraph{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      serviceKey.Name,
+					Namespace: serviceKey.Namespace,
+					Annotations: map[string]string{
+						"serving.kserve.io/deploymentMode": string(constants.RawDeployment),
+					},
+				},
+				Spec: v1alpha1.InferenceGraphSpec{
+					Nodes: map[string]v1alpha1.InferenceRouter{
+						v1alpha1.GraphRootNodeName: {
+							RouterType: v1alpha1.Sequence,
+							Steps: []v1alpha1.InferenceStep{
+								{
+									InferenceTarget: v1alpha1.InferenceTarget{
+										ServiceURL: "http://someservice.exmaple.com",
+									},
+								},
+							},
+						},
+					},
+					MaxReplicas: maxReplicas,
+				},
+			}
+			Expect(k8sClient.Create(ctx, ig)).Should(Succeed())
+			defer k8sClient.Delete(ctx, ig)
+
+			actualDeployment := &appsv1.Deployment{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, serviceKey, actualDeployment)
+			}, timeout, interval).Should(Succeed())
+			container := actualDeployment.Spec.Template.Spec.Containers[0]
+			Expect(container.Name).To(Equal(constants.InferenceServiceContainerName))
+			Expect(container.Image).To(Equal("kserve/router:v0.10.0"))
+			Expect(container.Args).To(Equal([]string{
+				"--graph-json",
+				"{\"nodes\":{\"root\":{\"routerType\":\"Sequence\",\"steps\":[{\"serviceUrl\":\"http://someservice.exmaple.com\"}]}},\"resources\":{},\"maxReplicas\":3}",
+			}))
+			Expect(container.Env).To(Equal([]v1.EnvVar{
+				{Name: "PROPAGATE_HEADERS", Value: "Authorization,Intuit_tid"},
+				{Name: "K_SERVICE", Value: graphName},
+			}))
+			Expect(actualDeployment.Spec.Template.Labels).To(HaveKeyWithValue("serving.kserve.io/inferencegraph", graphName))
+			Expect(actualDeployment.Spec.Template.Annotations).To(HaveKeyWithValue("prometheus.io/port", "8080"))
+			Expect(actualDeployment.OwnerReferences).To(HaveLen(1))
+			Expect(actualDeployment.OwnerReferences[0].Name).To(Equal(graphName))
+
+			actualService := &v1.Service{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, serviceKey, actualService)
+			}, timeout, interval).Should(Succeed())
+			Expect(actualService.Spec.Selector).To(Equal(map[string]string{"app": "isvc." + graphName}))
+			Expect(actualService.Spec.Ports[0].Port).To(Equal(int32(constants.CommonDefaultHttpPort)))
+			Expect(actualService.Spec.Ports[0].TargetPort.IntValue()).To(Equal(8080))
+
+			actualHPA := &autoscalingv2.HorizontalPodAutoscaler{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, serviceKey, actualHPA)
+			}, timeout, interval).Should(Succeed())
+			Expect(*actualHPA.Spec.MinReplicas).To(Equal(int32(1)))
+			Expect(actualHPA.Spec.MaxReplicas).To(Equal(int32(maxReplicas)))
+			Expect(actualHPA.Spec.ScaleTargetRef.Name).To(Equal(graphName))
+
+			actualIngress := &netv1.Ingress{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, serviceKey, actualIngress)
+			}, timeout, interval).Should(Succeed())
+			Expect(actualIngress.Spec.Rules).To(HaveLen(1))
+			Expect(actualIngress.Spec.Rules[0].Host).To(Equal(graphName + "-default." + domain))
+			Expect(actualIngress.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Name).To(Equal(graphName))
+
+			By("By marking the router deployment available")
+			actualDeployment.Status.Conditions = []appsv1.DeploymentCondition{
+				{
+					Type:   appsv1.DeploymentAvailable,
+					Status: v1.ConditionTrue,
+				},
+			}
+			Expect(k8sClient.Status().Update(ctx, actualDeployment)).To(Succeed())
+			Eventually(func() string {
+				graph := &v1alpha1.InferenceGraph{}
+				if err := k8sClient.Get(ctx, serviceKey, graph); err != nil || graph.Status.URL == nil {
+					return ""
+				}
+				return graph.Status.URL.String()
+			}, timeout, interval).Should(Equal("http://" + graphName + "-default." + domain))
+		})
+	})
+
+	Context("When the graph calls InferenceServices", func() {
+		It("Should not be ready while a Hard dependency is not ready", func() {
+			ig := &v1alpha1.InferenceGraph{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "services",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.InferenceGraphSpec{
+					Nodes: map[string]v1alpha1.InferenceRouter{
+						v1alpha1.GraphRootNodeName: {
+							RouterType: v1alpha1.Sequence,
+							Steps: []v1alpha1.InferenceStep{
+								{InferenceTarget: v1alpha1.InferenceTarget{ServiceName: "model1"}},
+								{InferenceTarget: v1alpha1.InferenceTarget{ServiceName: "model2"}, Dependency: v1alpha1.Hard},
+								{InferenceTarget: v1alpha1.InferenceTarget{NodeName: "ensemble"}},
+							},
+						},
+						"ensemble": {
+							RouterType: v1alpha1.Ensemble,
+							Steps: []v1alpha1.InferenceStep{
+								{InferenceTarget: v1alpha1.InferenceTarget{ServiceName: "model1"}, Dependency: v1alpha1.Hard},
+								{InferenceTarget: v1alpha1.InferenceTarget{ServiceName: "model3"}},
+							},
+						},
+					},
+				},
+			}
+			Expect(graphServiceNames(ig)).To(Equal(map[string]bool{"model1": true, "model2": true, "model3": false}))
+
+			ig.Status.Conditions = duckv1.Conditions{{Type: apis.ConditionReady, Status: v1.ConditionTrue}}
+			readyTime := apis.VolatileTime{Inner: metav1.NewTime(time.Unix(1000, 0))}
+			notReadyTime := apis.VolatileTime{Inner: metav1.NewTime(time.Unix(2000, 0))}
+			statuses := []v1alpha1.InferenceGraphServiceStatus{
+				{ServiceName: "model1", Ready: true, Hard: true, LastTransitionTime: readyTime},
+				{ServiceName: "model2", Ready: false, Hard: true, LastTransitionTime: notReadyTime},
+				{ServiceName: "model3", Ready: false, LastTransitionTime: readyTime},
+			}
+			propagateServiceStatuses(ig, statuses)
+			Expect(ig.Status.Services).To(Equal(statuses))
+			Expect(ig.Status.Conditions).To(Equal(duckv1.Conditions{
+				{
+					Type:               apis.ConditionReady,
+					Status:             v1.ConditionFalse,
+					Reason:             ServicesNotReadyReason,
+					Message:            "InferenceServices model2 are not ready",
+					LastTransitionTime: notReadyTime,
+				},
+			}))
+
+			// Soft dependencies do not change the readiness of the graph
+			ig.Status.Conditions = duckv1.Conditions{{Type: apis.ConditionReady, Status: v1.ConditionTrue}}
+			statuses[1].Ready = true
+			propagateServiceStatuses(ig, statuses)
+			Expect(inferenceGraphReadiness(ig.Status)).To(BeTrue())
+		})
+	})
+
+	Context("When the graph calls InferenceServices which are not ready", func() {
+		It("Should resolve the urls of the InferenceServices whether or not they are ready", func() {
+			ig := &v1alpha1.InferenceGraph{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "resolve",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.InferenceGraphSpec{
+					Nodes: map[string]v1alpha1.InferenceRouter{
+						v1alpha1.GraphRootNodeName: {
+							RouterType: v1alpha1.Sequence,
+							Steps: []v1alpha1.InferenceStep{
+								{
+									InferenceTarget: v1alpha1.InferenceTarget{ServiceName: "model1"},
+									Mirror:          &v1alpha1.StepMirror{InferenceTarget: v1alpha1.InferenceTarget{ServiceName: "model3"}},
+								},
+								{InferenceTarget: v1alpha1.InferenceTarget{ServiceName: "model2"}},
+								{InferenceTarget: v1alpha1.InferenceTarget{ServiceName: "model4"}},
+							},
+						},
+					},
+				},
+			}
+			Expect(graphServiceNames(ig)).To(Equal(map[string]bool{"model1": false, "model2": false, "model3": false, "model4": false}))
+
+			resolveServiceURLs(ig, []v1alpha1.InferenceGraphServiceStatus{
+				{ServiceName: "model1", Ready: true, URL: apis.HTTP("model1-transformer.default.svc.cluster.local")},
+				{ServiceName: "model2", Ready: false, URL: apis.HTTP("model2.default.svc.cluster.local")},
+				{ServiceName: "model3", Ready: true, URL: apis.HTTP("model3.default.svc.cluster.local")},
+				{ServiceName: "model4", Ready: false},
+			})
+			steps := ig.Spec.Nodes[v1alpha1.GraphRootNodeName].Steps
+			Expect(steps[0].ServiceURL).To(Equal("http://model1-transformer.default.svc.cluster.local"))
+			Expect(steps[0].Mirror.ServiceURL).To(Equal("http://model3.default.svc.cluster.local"))
+			Expect(steps[1].ServiceURL).To(Equal("http://model2.default.svc.cluster.local"))
+			// InferenceServices without an address are left unresolved
+			Expect(steps[2].ServiceURL).To(BeEmpty())
+		})
+	})
 })
@@ -32,13 +32,17 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/util/retry"
 	"knative.dev/pkg/kmp"
 	"knative.dev/serving/pkg/apis/autoscaling"
 	knservingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	"path/filepath"
 	"reflect"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -120,7 +124,7 @@ func semanticEquals(desiredService, service *knservingv1.Service) bool {
 }
 
 func createKnativeService(componentMeta metav1.ObjectMeta, graph *v1alpha1api.InferenceGraph, config *RouterConfig) *knservingv1.Service {
-	bytes, err := json.Marshal(graph.Spec)
+	podSpec, err := createInferenceGraphPodSpec(graph, config)
 	if err != nil {
 		return nil
 	}
@@ -137,10 +141,29 @@ func createKnativeService(componentMeta metav1.ObjectMeta, graph *v1alpha1api.In
 		annotations[autoscaling.ClassAnnotationKey] = autoscaling.KPA
 	}
 
+	// The autoscaling annotations set by the user take precedence over the scaling fields of the graph spec
 	if _, ok := annotations[autoscaling.MinScaleAnnotationKey]; !ok {
-		annotations[autoscaling.MinScaleAnnotationKey] = fmt.Sprint(constants.DefaultMinReplicas)
+		if graph.Spec.MinReplicas != nil {
+			annotations[autoscaling.MinScaleAnnotationKey] = fmt.Sprint(*graph.Spec.MinReplicas)
+		} else {
+			annotations[autoscaling.MinScaleAnnotationKey] = fmt.Sprint(constants.DefaultMinReplicas)
+		}
+	}
+
+	if _, ok := annotations[autoscaling.MaxScaleAnnotationKey]; !ok && graph.Spec.MaxReplicas != 0 {
+		annotations[autoscaling.MaxScaleAnnotationKey] = fmt.Sprint(graph.Spec.MaxReplicas)
+	}
+
+	if _, ok := annotations[autoscaling.TargetAnnotationKey]; !ok && graph.Spec.ScaleTarget != nil {
+		annotations[autoscaling.TargetAnnotationKey] = fmt.Sprint(*graph.Spec.ScaleTarget)
+	}
+
+	if _, ok := annotations[autoscaling.MetricAnnotationKey]; !ok && graph.Spec.ScaleMetric != nil {
+		annotations[autoscaling.MetricAnnotationKey] = fmt.Sprint(*graph.Spec.ScaleMetric)
 	}
 
+	setRouterPrometheusAnnotations(annotations)
+
 	// ksvc metadata.annotations
 	ksvcAnnotations := make(map[string]string)
 
@@ -168,40 +191,246 @@ func createKnativeService(componentMeta metav1.ObjectMeta, graph *v1alpha1api.In
 						Annotations: annotations,
 					},
 					Spec: knservingv1.RevisionSpec{
-						PodSpec: v1.PodSpec{
-							Containers: []v1.Container{
-								{
-									Image: config.Image,
-									Args: []string{
-										"--graph-json",
-										string(bytes),
-									},
-									Resources: constructResourceRequirements(*graph, *config),
-								},
-							},
-							Affinity: graph.Spec.Affinity,
-						},
+						PodSpec: *podSpec,
 					},
 				},
 			},
 		},
 	}
 
+	//Call setDefaults on desired knative service here to avoid diffs generated because knative defaulter webhook is
+	//called when creating or updating the knative service
+	service.SetDefaults(context.TODO())
+	return service
+}
+
+// setRouterPrometheusAnnotations points prometheus at the metrics the router serves next to the graph, the pod is
+// scraped directly on the router port since the graph pods are not mutated by the kserve pod webhook
+func setRouterPrometheusAnnotations(annotations map[string]string) {
+	if _, ok := annotations[constants.PrometheusPortAnnotationKey]; !ok {
+		annotations[constants.PrometheusPortAnnotationKey] = constants.InferenceServiceDefaultHttpPort
+	}
+	if _, ok := annotations[constants.PrometheusPathAnnotationKey]; !ok {
+		annotations[constants.PrometheusPathAnnotationKey] = constants.DefaultPrometheusPath
+	}
+}
+
+// createInferenceGraphPodSpec creates the pod spec of the router which is shared by the knative service and the raw
+// deployment of the graph
+func createInferenceGraphPodSpec(graph *v1alpha1api.InferenceGraph, config *RouterConfig) (*v1.PodSpec, error) {
+	bytes, err := json.Marshal(graph.Spec)
+	if err != nil {
+		return nil, err
+	}
+	podSpec := &v1.PodSpec{
+		Containers: []v1.Container{
+			{
+				Image: config.Image,
+				Args: []string{
+					"--graph-json",
+					string(bytes),
+				},
+				Resources: constructResourceRequirements(*graph, *config),
+			},
+		},
+		Affinity: graph.Spec.Affinity,
+	}
+
 	// Only adding this env variable "PROPAGATE_HEADERS" if router's headers config has the key "propagate"
 	value, exists := config.Headers["propagate"]
 	if exists {
-		service.Spec.ConfigurationSpec.Template.Spec.PodSpec.Containers[0].Env = []v1.EnvVar{
+		podSpec.Containers[0].Env = []v1.EnvVar{
 			{
 				Name:  constants.RouterHeadersPropagateEnvVar,
 				Value: strings.Join(value, ","),
 			},
 		}
 	}
+	if config.Tracing != nil {
+		container := &podSpec.Containers[0]
+		container.Env = append(container.Env, tracingEnvVars(graph.Name, config.Tracing)...)
+	}
 
-	//Call setDefaults on desired knative service here to avoid diffs generated because knative defaulter webhook is
-	//called when creating or updating the knative service
-	service.SetDefaults(context.TODO())
-	return service
+	if config.HotReload {
+		// the router loads the graph from the mounted config map so that spec changes do not change the revision
+		podSpec.Containers[0].Args = []string{
+			"--graph-config-dir",
+			constants.InferenceGraphConfigDir,
+		}
+		podSpec.Containers[0].VolumeMounts = []v1.VolumeMount{
+			{
+				Name:      constants.InferenceGraphConfigVolumeName,
+				MountPath: constants.InferenceGraphConfigDir,
+				ReadOnly:  true,
+			},
+		}
+		podSpec.Volumes = []v1.Volume{
+			{
+				Name: constants.InferenceGraphConfigVolumeName,
+				VolumeSource: v1.VolumeSource{
+					ConfigMap: &v1.ConfigMapVolumeSource{
+						LocalObjectReference: v1.LocalObjectReference{
+							Name: constants.InferenceGraphConfigName(graph.Name),
+						},
+					},
+				},
+			},
+		}
+	}
+
+	volumes, volumeMounts := stepAuthVolumes(graph)
+	if len(volumes) > 0 {
+		podSpec.Volumes = append(podSpec.Volumes, volumes...)
+		podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, volumeMounts...)
+	}
+
+	if config.MaxBodySize != "" {
+		container := &podSpec.Containers[0]
+		maxBodySize := resource.MustParse(config.MaxBodySize)
+		container.Args = append(container.Args, "--max-body-size", strconv.FormatInt(maxBodySize.Value(), 10))
+	}
+	if config.EnableTrace {
+		podSpec.Containers[0].Args = append(podSpec.Containers[0].Args, "--enable-trace")
+	}
+	if servesGRPC(graph) {
+		// the router serves gRPC next to http on the same port, http2 has to be proxied to it end to end
+		podSpec.Containers[0].Ports = []v1.ContainerPort{
+			{
+				Name:          constants.RouterH2CPortName,
+				ContainerPort: constants.RouterPort,
+				Protocol:      v1.ProtocolTCP,
+			},
+		}
+	}
+	return podSpec, nil
+}
+
+// servesGRPC returns true when the graph is reachable over the v2 gRPC inference protocol, gRPC requests are routed
+// through the root node as json v2 requests so the root node has to speak v2
+func servesGRPC(graph *v1alpha1api.InferenceGraph) bool {
+	root, ok := graph.Spec.Nodes[v1alpha1api.GraphRootNodeName]
+	return ok && root.ProtocolVersion != nil && *root.ProtocolVersion == constants.ProtocolV2
+}
+
+// stepAuthVolumes mounts the credentials of the steps and their mirrors in the router, the projected ServiceAccount tokens of all the
+// audiences share a volume and every referenced secret is mounted with the referenced keys only
+func stepAuthVolumes(graph *v1alpha1api.InferenceGraph) ([]v1.Volume, []v1.VolumeMount) {
+	audiences := sets.NewString()
+	secretKeys := map[string]sets.String{}
+	for _, node := range graph.Spec.Nodes {
+		for _, step := range node.Steps {
+			auths := []*v1alpha1api.StepAuth{step.Auth}
+			if step.Mirror != nil {
+				auths = append(auths, step.Mirror.Auth)
+			}
+			for _, auth := range auths {
+				if auth == nil {
+					continue
+				}
+				switch auth.Type {
+				case v1alpha1api.ServiceAccountToken:
+					audiences.Insert(auth.Audience)
+				case v1alpha1api.SecretHeader:
+					if ref := auth.SecretKeyRef; ref != nil {
+						if _, ok := secretKeys[ref.Name]; !ok {
+							secretKeys[ref.Name] = sets.NewString()
+						}
+						secretKeys[ref.Name].Insert(ref.Key)
+					}
+				}
+			}
+		}
+	}
+
+	var volumes []v1.Volume
+	var volumeMounts []v1.VolumeMount
+	if audiences.Len() > 0 {
+		var sources []v1.VolumeProjection
+		for _, audience := range audiences.List() {
+			sources = append(sources, v1.VolumeProjection{
+				ServiceAccountToken: &v1.ServiceAccountTokenProjection{
+					Audience:          audience,
+					ExpirationSeconds: proto.Int64(constants.InferenceGraphAuthTokenExpirationSeconds),
+					Path:              constants.InferenceGraphAuthTokenFileName(audience),
+				},
+			})
+		}
+		volumes = append(volumes, v1.Volume{
+			Name: constants.InferenceGraphAuthTokensVolumeName,
+			VolumeSource: v1.VolumeSource{
+				Projected: &v1.ProjectedVolumeSource{Sources: sources},
+			},
+		})
+		volumeMounts = append(volumeMounts, v1.VolumeMount{
+			Name:      constants.InferenceGraphAuthTokensVolumeName,
+			MountPath: constants.InferenceGraphAuthTokensDir,
+			ReadOnly:  true,
+		})
+	}
+	secretNames := make([]string, 0, len(secretKeys))
+	for name := range secretKeys {
+		secretNames = append(secretNames, name)
+	}
+	sort.Strings(secretNames)
+	for i, name := range secretNames {
+		// secret names can be longer than volume names
+		volumeName := fmt.Sprintf("%s%d", constants.InferenceGraphAuthSecretVolumePrefix, i)
+		var items []v1.KeyToPath
+		for _, key := range secretKeys[name].List() {
+			items = append(items, v1.KeyToPath{Key: key, Path: key})
+		}
+		volumes = append(volumes, v1.Volume{
+			Name: volumeName,
+			VolumeSource: v1.VolumeSource{
+				Secret: &v1.SecretVolumeSource{SecretName: name, Items: items},
+			},
+		})
+		volumeMounts = append(volumeMounts, v1.VolumeMount{
+			Name:      volumeName,
+			MountPath: filepath.Join(constants.InferenceGraphAuthSecretsDir, name),
+			ReadOnly:  true,
+		})
+	}
+	return volumes, volumeMounts
+}
+
+// tracingEnvVars configures the OpenTelemetry sdk of the router through its standard env variables
+func tracingEnvVars(graphName string, tracing *RouterTracingConfig) []v1.EnvVar {
+	if tracing.Exporter == "" {
+		return nil
+	}
+	envVars := []v1.EnvVar{
+		{
+			Name:  constants.RouterTracesExporterEnvVar,
+			Value: tracing.Exporter,
+		},
+		{
+			Name:  constants.RouterTracesServiceNameEnvVar,
+			Value: graphName,
+		},
+	}
+	if tracing.Endpoint != "" {
+		envVars = append(envVars, v1.EnvVar{
+			Name:  constants.RouterTracesEndpointEnvVar,
+			Value: tracing.Endpoint,
+		})
+	}
+	if tracing.Insecure {
+		envVars = append(envVars, v1.EnvVar{
+			Name:  constants.RouterTracesInsecureEnvVar,
+			Value: "true",
+		})
+	}
+	if tracing.SamplingRatio != "" {
+		envVars = append(envVars, v1.EnvVar{
+			Name:  constants.RouterTracesSamplerEnvVar,
+			Value: constants.RouterTracesRatioSamplerPolicy,
+		}, v1.EnvVar{
+			Name:  constants.RouterTracesSamplerArgEnvVar,
+			Value: tracing.SamplingRatio,
+		})
+	}
+	return envVars
 }
 
 func constructResourceRequirements(graph v1alpha1api.InferenceGraph, config RouterConfig) v1.ResourceRequirements {
@@ -0,0 +1,149 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inferencegraph
+
+import (
+	v1alpha1api "github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	v1beta1api "github.com/kserve/kserve/pkg/apis/serving/v1beta1"
+	"github.com/kserve/kserve/pkg/constants"
+	"github.com/kserve/kserve/pkg/controller/v1beta1/inferenceservice/reconcilers/ingress"
+	"github.com/kserve/kserve/pkg/controller/v1beta1/inferenceservice/reconcilers/raw"
+	"github.com/kserve/kserve/pkg/utils"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// createInferenceGraphRawPodSpec creates the pod spec of the router deployment. The container is named so that the
+// deployment reconciler adds the readiness probe, and the graph name is set in the env var knative would have set.
+func createInferenceGraphRawPodSpec(graph *v1alpha1api.InferenceGraph, config *RouterConfig) (*v1.PodSpec, error) {
+	podSpec, err := createInferenceGraphPodSpec(graph, config)
+	if err != nil {
+		return nil, err
+	}
+	container := &podSpec.Containers[0]
+	container.Name = constants.InferenceServiceContainerName
+	container.Env = append(container.Env, v1.EnvVar{
+		Name:  constants.RouterGraphNameEnvVar,
+		Value: graph.Name,
+	})
+	return podSpec, nil
+}
+
+func constructForRawDeployment(graph *v1alpha1api.InferenceGraph) (metav1.ObjectMeta, v1beta1api.ComponentExtensionSpec) {
+	annotations := utils.Filter(graph.Annotations, func(key string) bool {
+		return !utils.Includes(constants.ServiceAnnotationDisallowedList, key)
+	})
+	setRouterPrometheusAnnotations(annotations)
+	labels := utils.Union(graph.Labels, map[string]string{
+		constants.InferenceGraphLabel: graph.Name,
+	})
+	objectMeta := metav1.ObjectMeta{
+		Name:        graph.Name,
+		Namespace:   graph.Namespace,
+		Labels:      labels,
+		Annotations: annotations,
+	}
+	componentExtensionSpec := v1beta1api.ComponentExtensionSpec{
+		MinReplicas: graph.Spec.MinReplicas,
+		MaxReplicas: graph.Spec.MaxReplicas,
+		ScaleTarget: graph.Spec.ScaleTarget,
+	}
+	if graph.Spec.ScaleMetric != nil {
+		scaleMetric := v1beta1api.ScaleMetric(*graph.Spec.ScaleMetric)
+		componentExtensionSpec.ScaleMetric = &scaleMetric
+	}
+	return objectMeta, componentExtensionSpec
+}
+
+// handleInferenceGraphRawDeployment reconciles the deployment, service and autoscaler of the router and the ingress
+// which exposes it, it returns the router deployment and the url of the graph
+func handleInferenceGraphRawDeployment(cl client.Client, scheme *runtime.Scheme, graph *v1alpha1api.InferenceGraph,
+	routerConfig *RouterConfig) (*appsv1.Deployment, *apis.URL, error) {
+	podSpec, err := createInferenceGraphRawPodSpec(graph, routerConfig)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "fails to create the router pod spec")
+	}
+	objectMeta, componentExtSpec := constructForRawDeployment(graph)
+
+	reconciler, err := raw.NewRawKubeReconciler(cl, scheme, objectMeta, &componentExtSpec, podSpec)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "fails to create NewRawKubeReconciler for inference graph")
+	}
+	//set Deployment Controller
+	if err := controllerutil.SetControllerReference(graph, reconciler.Deployment.Deployment, scheme); err != nil {
+		return nil, nil, errors.Wrapf(err, "fails to set deployment owner reference for inference graph")
+	}
+	//set Service Controller
+	if err := controllerutil.SetControllerReference(graph, reconciler.Service.Service, scheme); err != nil {
+		return nil, nil, errors.Wrapf(err, "fails to set service owner reference for inference graph")
+	}
+	//set autoscaler Controller
+	if reconciler.Scaler.Autoscaler.AutoscalerClass == constants.AutoscalerClassHPA {
+		if err := controllerutil.SetControllerReference(graph, reconciler.Scaler.Autoscaler.HPA.HPA, scheme); err != nil {
+			return nil, nil, errors.Wrapf(err, "fails to set HPA owner reference for inference graph")
+		}
+	}
+	deployment, err := reconciler.Reconcile()
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "fails to reconcile inference graph raw deployment")
+	}
+
+	ingressConfig, err := v1beta1api.NewIngressConfig(cl)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "fails to create IngressConfig")
+	}
+	ingressReconciler, err := ingress.NewRawIngressReconciler(cl, scheme, ingressConfig)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "fails to create NewRawIngressReconciler for inference graph")
+	}
+	url, err := ingressReconciler.ReconcileInferenceGraph(graph)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "fails to reconcile inference graph ingress")
+	}
+	return deployment, url, nil
+}
+
+// propagateRawStatus sets the readiness of the graph from the availability of the router deployment
+func propagateRawStatus(graph *v1alpha1api.InferenceGraph, deployment *appsv1.Deployment, url *apis.URL) {
+	condition := &apis.Condition{
+		Type:   apis.ConditionReady,
+		Status: v1.ConditionUnknown,
+		Reason: "DeploymentNotAvailable",
+	}
+	for _, con := range deployment.Status.Conditions {
+		if con.Type == appsv1.DeploymentAvailable {
+			condition.Status = con.Status
+			condition.Reason = con.Reason
+			condition.Message = con.Message
+			condition.LastTransitionTime = apis.VolatileTime{Inner: con.LastTransitionTime}
+			break
+		}
+	}
+	graph.Status.Conditions = duckv1.Conditions{*condition}
+	if condition.Status == v1.ConditionTrue {
+		graph.Status.URL = url
+	} else {
+		graph.Status.URL = nil
+	}
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inferencegraph
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	v1alpha1api "github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	v1beta1api "github.com/kserve/kserve/pkg/apis/serving/v1beta1"
+	isvcutils "github.com/kserve/kserve/pkg/controller/v1beta1/inferenceservice/utils"
+	v1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/apis"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ServicesNotReadyReason is the reason of the graph Ready condition while a Hard dependency is not ready
+const ServicesNotReadyReason = "ServicesNotReady"
+
+// graphServiceNames returns the names of the InferenceServices called by the graph steps and their mirrors, and
+// whether a step with a Hard dependency calls them
+func graphServiceNames(graph *v1alpha1api.InferenceGraph) map[string]bool {
+	services := map[string]bool{}
+	for _, node := range graph.Spec.Nodes {
+		for _, step := range node.Steps {
+			if step.ServiceName != "" {
+				services[step.ServiceName] = services[step.ServiceName] || step.Dependency == v1alpha1api.Hard
+			}
+			if mirror := step.Mirror; mirror != nil && mirror.ServiceName != "" {
+				if _, ok := services[mirror.ServiceName]; !ok {
+					services[mirror.ServiceName] = false
+				}
+			}
+		}
+	}
+	return services
+}
+
+// resolveServiceURLs sets the url of the InferenceServices on the steps and mirrors calling them whether or not
+// they are ready, the readiness only sets the graph status. The url points to the transformer of the
+// InferenceService when it has one.
+func resolveServiceURLs(graph *v1alpha1api.InferenceGraph, statuses []v1alpha1api.InferenceGraphServiceStatus) {
+	urls := map[string]string{}
+	for _, status := range statuses {
+		if status.URL != nil {
+			urls[status.ServiceName] = status.URL.String()
+		}
+	}
+	for _, node := range graph.Spec.Nodes {
+		for i := range node.Steps {
+			step := &node.Steps[i]
+			if step.ServiceName != "" && step.ServiceURL == "" {
+				step.ServiceURL = urls[step.ServiceName]
+			}
+			if mirror := step.Mirror; mirror != nil && mirror.ServiceName != "" && mirror.ServiceURL == "" {
+				mirror.ServiceURL = urls[mirror.ServiceName]
+			}
+		}
+	}
+}
+
+// getServiceStatuses returns the status of the InferenceServices called by the graph sorted by name, the
+// transition time of a service which is not found is kept from the previous status of the graph
+func (r *InferenceGraphReconciler) getServiceStatuses(ctx context.Context, graph *v1alpha1api.InferenceGraph) ([]v1alpha1api.InferenceGraphServiceStatus, error) {
+	previous := map[string]v1alpha1api.InferenceGraphServiceStatus{}
+	for _, status := range graph.Status.Services {
+		previous[status.ServiceName] = status
+	}
+	services := graphServiceNames(graph)
+	statuses := make([]v1alpha1api.InferenceGraphServiceStatus, 0, len(services))
+	for name, hard := range services {
+		status := v1alpha1api.InferenceGraphServiceStatus{
+			ServiceName: name,
+			Hard:        hard,
+		}
+		isvc := &v1beta1api.InferenceService{}
+		err := r.Client.Get(ctx, types.NamespacedName{Namespace: graph.Namespace, Name: name}, isvc)
+		if err != nil && !apierr.IsNotFound(err) {
+			return nil, err
+		}
+		if err == nil {
+			if condition := isvc.Status.GetCondition(apis.ConditionReady); condition != nil {
+				status.Ready = condition.Status == v1.ConditionTrue
+				status.LastTransitionTime = condition.LastTransitionTime
+			}
+			if endpoint, err := isvcutils.GetPredictorEndpoint(isvc); err == nil {
+				status.URL, _ = apis.ParseURL(endpoint)
+			}
+		}
+		if status.LastTransitionTime.Inner.IsZero() {
+			if prev, ok := previous[name]; ok && prev.Ready == status.Ready {
+				status.LastTransitionTime = prev.LastTransitionTime
+			} else {
+				status.LastTransitionTime = apis.VolatileTime{Inner: metav1.Now()}
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].ServiceName < statuses[j].ServiceName
+	})
+	return statuses, nil
+}
+
+// propagateServiceStatuses sets the status of the graph services and marks the graph not ready while an
+// InferenceService with a Hard dependency is not ready
+func propagateServiceStatuses(graph *v1alpha1api.InferenceGraph, statuses []v1alpha1api.InferenceGraphServiceStatus) {
+	graph.Status.Services = statuses
+	var notReady []string
+	var lastTransitionTime apis.VolatileTime
+	for _, status := range statuses {
+		if status.Hard && !status.Ready {
+			notReady = append(notReady, status.ServiceName)
+			if lastTransitionTime.Inner.Before(&status.LastTransitionTime.Inner) {
+				lastTransitionTime = status.LastTransitionTime
+			}
+		}
+	}
+	if len(notReady) == 0 {
+		return
+	}
+	condition := apis.Condition{
+		Type:               apis.ConditionReady,
+		Status:             v1.ConditionFalse,
+		Reason:             ServicesNotReadyReason,
+		Message:            fmt.Sprintf("InferenceServices %s are not ready", strings.Join(notReady, ", ")),
+		LastTransitionTime: lastTransitionTime,
+	}
+	for i, con := range graph.Status.Conditions {
+		if con.Type == apis.ConditionReady {
+			graph.Status.Conditions[i] = condition
+			return
+		}
+	}
+	graph.Status.Conditions = append(graph.Status.Conditions, condition)
+}
+
+// findGraphsForService enqueues the graphs in the namespace of the InferenceService which call it
+func (r *InferenceGraphReconciler) findGraphsForService(ctx context.Context, obj client.Object) []reconcile.Request {
+	graphs := &v1alpha1api.InferenceGraphList{}
+	if err := r.Client.List(ctx, graphs, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.Log.Error(err, "failed to list inference graphs", "namespace", obj.GetNamespace())
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range graphs.Items {
+		if _, ok := graphServiceNames(&graphs.Items[i])[obj.GetName()]; ok {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+				Namespace: graphs.Items[i].Namespace,
+				Name:      graphs.Items[i].Name,
+			}})
+		}
+	}
+	return requests
+}
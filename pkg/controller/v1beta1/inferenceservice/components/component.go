@@ -84,6 +84,10 @@ func addBatcherAnnotations(batcher *v1beta1.Batcher, annotations map[string]stri
 			s := strconv.Itoa(*batcher.Timeout)
 			annotations[constants.BatcherTimeoutInternalAnnotationKey] = s
 		}
+		if batcher.TargetLatency != nil {
+			s := strconv.Itoa(*batcher.TargetLatency)
+			annotations[constants.BatcherTargetLatencyInternalAnnotationKey] = s
+		}
 		return true
 	}
 	return false
@@ -0,0 +1,89 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	"github.com/kserve/kserve/pkg/apis/serving/v1beta1"
+	"github.com/kserve/kserve/pkg/constants"
+	"github.com/kserve/kserve/pkg/utils"
+	netv1 "k8s.io/api/networking/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	knapis "knative.dev/pkg/apis"
+	"knative.dev/pkg/network"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func createRawGraphIngress(graph *v1alpha1.InferenceGraph, host string, ingressConfig *v1beta1.IngressConfig) *netv1.Ingress {
+	return &netv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      graph.Name,
+			Namespace: graph.Namespace,
+			Annotations: utils.Filter(graph.Annotations, func(key string) bool {
+				return !utils.Includes(constants.ServiceAnnotationDisallowedList, key)
+			}),
+		},
+		Spec: netv1.IngressSpec{
+			IngressClassName: ingressConfig.IngressClassName,
+			Rules:            []netv1.IngressRule{generateRule(host, graph.Name, "/", constants.CommonDefaultHttpPort)},
+		},
+	}
+}
+
+// ReconcileInferenceGraph reconciles the ingress of the router service of an InferenceGraph deployed in RawDeployment
+// mode and returns the url of the graph. Cluster local graphs are not exposed and are addressed by the router service.
+func (r *RawIngressReconciler) ReconcileInferenceGraph(graph *v1alpha1.InferenceGraph) (*knapis.URL, error) {
+	if graph.Labels[constants.NetworkVisibility] == constants.ClusterLocalVisibility ||
+		r.ingressConfig.IngressDomain == constants.ClusterLocalDomain {
+		return &knapis.URL{
+			Scheme: r.ingressConfig.UrlScheme,
+			Host:   network.GetServiceHostname(graph.Name, graph.Namespace),
+		}, nil
+	}
+	host, err := GenerateDomainName(graph.Name, graph.ObjectMeta, r.ingressConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating inference graph ingress host: %v", err)
+	}
+	ingress := createRawGraphIngress(graph, host, r.ingressConfig)
+	if err := controllerutil.SetControllerReference(graph, ingress, r.scheme); err != nil {
+		return nil, err
+	}
+	existingIngress := &netv1.Ingress{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: graph.Namespace, Name: graph.Name}, existingIngress)
+	if err != nil {
+		if !apierr.IsNotFound(err) {
+			return nil, err
+		}
+		err = r.client.Create(context.TODO(), ingress)
+		log.Info("creating inference graph ingress", "ingressName", graph.Name, "err", err)
+	} else if !semanticIngressEquals(ingress, existingIngress) {
+		err = r.client.Update(context.TODO(), ingress)
+		log.Info("updating inference graph ingress", "ingressName", graph.Name, "err", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &knapis.URL{
+		Scheme: r.ingressConfig.UrlScheme,
+		Host:   host,
+	}, nil
+}
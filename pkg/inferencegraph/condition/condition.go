@@ -0,0 +1,120 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package condition compiles and evaluates the conditions of InferenceGraph steps.
+//
+// A condition is a CEL expression evaluating to a bool, e.g
+//
+//	body.instances[0].userId == 1 && headers["x-tenant"] == "gold"
+//
+// where `body` is the json payload the condition is evaluated against and `headers` the request headers keyed
+// by lower case name. For backward compatibility conditions which are not CEL expressions are evaluated as gjson
+// paths which match when the path exists in the payload, e.g `predictions.#(label=="dog")`.
+package condition
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/tidwall/gjson"
+)
+
+var (
+	env = mustNewEnv()
+	// gjson paths which are also valid CEL syntax, e.g `predictions` or `outputs.label`
+	plainPathRegexp = regexp.MustCompile(`^[A-Za-z_][\w-]*(\.[\w-]+)*$`)
+)
+
+func mustNewEnv() *cel.Env {
+	e, err := cel.NewEnv(
+		cel.Variable("body", cel.DynType),
+		cel.Variable("headers", cel.MapType(cel.StringType, cel.StringType)),
+	)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// Condition is a compiled step condition
+type Condition struct {
+	expression string
+	// program is nil for gjson path conditions
+	program cel.Program
+}
+
+// Compile compiles the condition, it returns an error for invalid CEL expressions
+// and for expressions which do not evaluate to a bool.
+func Compile(expression string) (*Condition, error) {
+	ast, issues := env.Compile(expression)
+	if issues == nil || issues.Err() == nil {
+		if !cel.BoolType.IsAssignableType(ast.OutputType()) {
+			return nil, fmt.Errorf("condition %q must evaluate to a bool but evaluates to %s", expression, ast.OutputType())
+		}
+		program, err := env.Program(ast)
+		if err != nil {
+			return nil, err
+		}
+		return &Condition{expression: expression, program: program}, nil
+	}
+	if _, parseIssues := env.Parse(expression); (parseIssues == nil || parseIssues.Err() == nil) &&
+		!plainPathRegexp.MatchString(expression) {
+		// valid CEL syntax which does not type check, e.g a comparison on an undeclared variable
+		return nil, fmt.Errorf("invalid condition %q: %w", expression, issues.Err())
+	}
+	return &Condition{expression: expression}, nil
+}
+
+// IsExpression reports whether the condition is a CEL expression rather than a gjson path
+func (c *Condition) IsExpression() bool {
+	return c.program != nil
+}
+
+// String returns the source of the condition
+func (c *Condition) String() string {
+	return c.expression
+}
+
+// Matches evaluates the condition against the json payload and the request headers
+func (c *Condition) Matches(payload []byte, headers http.Header) (bool, error) {
+	if c.program == nil {
+		return gjson.GetBytes(payload, c.expression).Exists(), nil
+	}
+	var body interface{}
+	if err := json.Unmarshal(payload, &body); err != nil {
+		return false, fmt.Errorf("payload is not valid json: %w", err)
+	}
+	headerValues := make(map[string]string, len(headers))
+	for name, values := range headers {
+		headerValues[strings.ToLower(name)] = strings.Join(values, ",")
+	}
+	out, _, err := c.program.Eval(map[string]interface{}{
+		"body":    body,
+		"headers": headerValues,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate condition %q: %w", c.expression, err)
+	}
+	matches, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("condition %q evaluated to %v instead of a bool", c.expression, out.Value())
+	}
+	return matches, nil
+}
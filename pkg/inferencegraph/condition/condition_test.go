@@ -0,0 +1,121 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package condition
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestCompile(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	scenarios := map[string]struct {
+		expression   string
+		isExpression bool
+		hasError     bool
+	}{
+		"numeric comparison": {
+			expression:   `body.instances[0].userId == 1`,
+			isExpression: true,
+		},
+		"boolean logic with headers": {
+			expression:   `headers["x-tenant"] == "gold" || (has(body.score) && body.score > 0.5)`,
+			isExpression: true,
+		},
+		"gjson query": {
+			expression: `predictions.#(label=="dog")`,
+		},
+		"gjson plain path": {
+			expression: `outputs.label`,
+		},
+		"undeclared variable": {
+			expression: `input.userId == 1`,
+			hasError:   true,
+		},
+		"not a bool": {
+			expression: `headers["x-tenant"]`,
+			hasError:   true,
+		},
+	}
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			c, err := Compile(scenario.expression)
+			if scenario.hasError {
+				g.Expect(err).To(gomega.HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(c.IsExpression()).To(gomega.Equal(scenario.isExpression))
+			g.Expect(c.String()).To(gomega.Equal(scenario.expression))
+		})
+	}
+}
+
+func TestMatches(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	payload := []byte(`{"instances":[{"userId":1,"country":"NL"}],"predictions":[{"label":"dog","score":0.8}]}`)
+	headers := http.Header{"X-Tenant": {"gold"}}
+	scenarios := map[string]struct {
+		expression string
+		matches    bool
+		hasError   bool
+	}{
+		"numeric equality": {
+			expression: `body.instances[0].userId == 1`,
+			matches:    true,
+		},
+		"numeric comparison": {
+			expression: `body.predictions[0].score < 0.5`,
+			matches:    false,
+		},
+		"string comparison and header": {
+			expression: `body.instances[0].country == "NL" && headers["x-tenant"] == "gold"`,
+			matches:    true,
+		},
+		"negation": {
+			expression: `!(body.predictions[0].label in ["cat", "bird"])`,
+			matches:    true,
+		},
+		"missing field": {
+			expression: `body.instances[0].age > 18`,
+			hasError:   true,
+		},
+		"gjson path": {
+			expression: `predictions.#(label=="dog")`,
+			matches:    true,
+		},
+		"gjson path without match": {
+			expression: `predictions.#(label=="cat")`,
+			matches:    false,
+		},
+	}
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			c, err := Compile(scenario.expression)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			matches, err := c.Matches(payload, headers)
+			if scenario.hasError {
+				g.Expect(err).To(gomega.HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(matches).To(gomega.Equal(scenario.matches))
+		})
+	}
+}
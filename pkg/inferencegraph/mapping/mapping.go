@@ -0,0 +1,262 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package mapping compiles and applies the data mappings which build the request of an InferenceGraph step.
+//
+// The data of a step is either a single reference or a json template. References are
+//
+//	$request[.<path>]            the request of the node
+//	$response[.<path>]           the response of the previous step
+//	$steps.<stepName>[.<path>]   the response of an earlier step of the node
+//
+// where the optional path is a gjson path into the referenced json. A json template is any json value in which
+// strings starting with `$` are replaced by the referenced json and all other values are constants, e.g
+//
+//	{"instances": "$steps.preprocess.predictions", "parameters": {"threshold": 0.5}}
+//
+// Strings starting with `$$` are constants with the first `$` removed.
+package mapping
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+const (
+	// RequestReference refers to the request of the node
+	RequestReference = "$request"
+	// ResponseReference refers to the response of the previous step
+	ResponseReference = "$response"
+	// StepsReference refers to the response of an earlier step by name
+	StepsReference = "$steps"
+)
+
+// Scope holds the payloads the references of a mapping are resolved against
+type Scope struct {
+	// Request is the request of the node
+	Request []byte
+	// Response is the response of the previous step
+	Response []byte
+	// Steps are the responses of the earlier steps keyed by step name
+	Steps map[string][]byte
+}
+
+type reference struct {
+	source string
+	// step is the step name of a $steps reference
+	step string
+	path string
+}
+
+func (r *reference) String() string {
+	s := r.source
+	if r.step != "" {
+		s += "." + r.step
+	}
+	if r.path != "" {
+		s += "." + r.path
+	}
+	return s
+}
+
+func (r *reference) resolve(scope *Scope) ([]byte, error) {
+	var payload []byte
+	switch r.source {
+	case RequestReference:
+		payload = scope.Request
+	case ResponseReference:
+		payload = scope.Response
+	case StepsReference:
+		response, ok := scope.Steps[r.step]
+		if !ok {
+			return nil, fmt.Errorf("reference %s: step %s has no response", r, r.step)
+		}
+		payload = response
+	}
+	if r.path == "" {
+		return payload, nil
+	}
+	value := gjson.GetBytes(payload, r.path)
+	if !value.Exists() {
+		return nil, fmt.Errorf("reference %s not found", r)
+	}
+	return []byte(value.Raw), nil
+}
+
+func parseReference(s string) (*reference, error) {
+	for _, source := range []string{RequestReference, ResponseReference, StepsReference} {
+		if s != source && !strings.HasPrefix(s, source+".") {
+			continue
+		}
+		r := &reference{source: source, path: strings.TrimPrefix(strings.TrimPrefix(s, source), ".")}
+		if source == StepsReference {
+			parts := strings.SplitN(r.path, ".", 2)
+			if parts[0] == "" {
+				return nil, fmt.Errorf("reference %s must name a step e.g $steps.<stepName>", s)
+			}
+			r.step = parts[0]
+			r.path = ""
+			if len(parts) == 2 {
+				r.path = parts[1]
+			}
+		}
+		return r, nil
+	}
+	return nil, fmt.Errorf("unknown reference %s, references start with $request, $response or $steps", s)
+}
+
+// Mapping is a compiled step data mapping
+type Mapping struct {
+	data string
+	// reference is set when the whole data is a single reference
+	reference *reference
+	// template is the parsed json template with references in place of the strings starting with `$`
+	template   interface{}
+	references []*reference
+}
+
+// Compile compiles the data of a step
+func Compile(data string) (*Mapping, error) {
+	m := &Mapping{data: data}
+	trimmed := strings.TrimSpace(data)
+	if strings.HasPrefix(trimmed, "$") {
+		r, err := parseReference(trimmed)
+		if err != nil {
+			return nil, err
+		}
+		m.reference = r
+		m.references = []*reference{r}
+		return m, nil
+	}
+	decoder := json.NewDecoder(strings.NewReader(trimmed))
+	decoder.UseNumber()
+	var template interface{}
+	if err := decoder.Decode(&template); err != nil || decoder.More() {
+		return nil, fmt.Errorf("data %q must be a reference e.g $response.predictions or a json template", data)
+	}
+	var err error
+	if m.template, err = m.compileTemplate(template); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *Mapping) compileTemplate(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		if strings.HasPrefix(v, "$$") {
+			return v[1:], nil
+		}
+		if strings.HasPrefix(v, "$") {
+			r, err := parseReference(v)
+			if err != nil {
+				return nil, err
+			}
+			m.references = append(m.references, r)
+			return r, nil
+		}
+	case map[string]interface{}:
+		for key, element := range v {
+			compiled, err := m.compileTemplate(element)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = compiled
+		}
+	case []interface{}:
+		for i, element := range v {
+			compiled, err := m.compileTemplate(element)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = compiled
+		}
+	}
+	return value, nil
+}
+
+// String returns the source of the mapping
+func (m *Mapping) String() string {
+	return m.data
+}
+
+// StepReferences returns the names of the steps referenced with $steps
+func (m *Mapping) StepReferences() []string {
+	var steps []string
+	for _, r := range m.references {
+		if r.step != "" {
+			steps = append(steps, r.step)
+		}
+	}
+	return steps
+}
+
+// Apply builds the step request from the payloads of the scope
+func (m *Mapping) Apply(scope *Scope) ([]byte, error) {
+	if m.reference != nil {
+		return m.reference.resolve(scope)
+	}
+	resolved, err := resolveTemplate(m.template, scope)
+	if err != nil {
+		return nil, err
+	}
+	buffer := &bytes.Buffer{}
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(resolved); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buffer.Bytes(), "\n"), nil
+}
+
+func resolveTemplate(value interface{}, scope *Scope) (interface{}, error) {
+	switch v := value.(type) {
+	case *reference:
+		resolved, err := v.resolve(scope)
+		if err != nil {
+			return nil, err
+		}
+		if !json.Valid(resolved) {
+			return nil, fmt.Errorf("reference %s is not json", v)
+		}
+		return json.RawMessage(resolved), nil
+	case map[string]interface{}:
+		object := make(map[string]interface{}, len(v))
+		for key, element := range v {
+			resolved, err := resolveTemplate(element, scope)
+			if err != nil {
+				return nil, err
+			}
+			object[key] = resolved
+		}
+		return object, nil
+	case []interface{}:
+		array := make([]interface{}, len(v))
+		for i, element := range v {
+			resolved, err := resolveTemplate(element, scope)
+			if err != nil {
+				return nil, err
+			}
+			array[i] = resolved
+		}
+		return array, nil
+	}
+	return value, nil
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2023 The KServe Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mapping
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestCompile(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	scenarios := map[string]struct {
+		data           string
+		stepReferences []string
+		hasError       bool
+	}{
+		"request": {
+			data: "$request",
+		},
+		"response path": {
+			data: "$response.predictions",
+		},
+		"template": {
+			data:           `{"instances": "$steps.preprocess.predictions", "id": "$request.id", "model": ["$steps.model"]}`,
+			stepReferences: []string{"model", "preprocess"},
+		},
+		"unknown reference": {
+			data:     "$input.instances",
+			hasError: true,
+		},
+		"steps reference without step": {
+			data:     `{"instances": "$steps"}`,
+			hasError: true,
+		},
+		"invalid template": {
+			data:     `{"instances": `,
+			hasError: true,
+		},
+	}
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			m, err := Compile(scenario.data)
+			if scenario.hasError {
+				g.Expect(err).To(gomega.HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(m.String()).To(gomega.Equal(scenario.data))
+			g.Expect(m.StepReferences()).To(gomega.ConsistOf(scenario.stepReferences))
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	scope := &Scope{
+		Request:  []byte(`{"id": "42", "instances": [[1, 2]]}`),
+		Response: []byte(`{"predictions": [[0.1, 0.9]]}`),
+		Steps: map[string][]byte{
+			"preprocess": []byte(`{"predictions": [[3, 4]]}`),
+			"text":       []byte(`plain text`),
+		},
+	}
+	scenarios := map[string]struct {
+		data     string
+		expected string
+		hasError bool
+	}{
+		"whole request": {
+			data:     "$request",
+			expected: `{"id": "42", "instances": [[1, 2]]}`,
+		},
+		"response path": {
+			data:     "$response.predictions",
+			expected: `[[0.1, 0.9]]`,
+		},
+		"wrap predictions as instances": {
+			data:     `{"instances": "$steps.preprocess.predictions"}`,
+			expected: `{"instances": [[3, 4]]}`,
+		},
+		"combination with constants": {
+			data: `{"id": "$request.id", "instances": "$response.predictions", "original": "$request.instances",` +
+				` "parameters": {"threshold": 0.25, "currency": "$$USD"}}`,
+			expected: `{"id": "42", "instances": [[0.1, 0.9]], "original": [[1, 2]],` +
+				` "parameters": {"threshold": 0.25, "currency": "$USD"}}`,
+		},
+		"missing path": {
+			data:     `{"instances": "$response.outputs"}`,
+			hasError: true,
+		},
+		"missing step": {
+			data:     "$steps.postprocess",
+			hasError: true,
+		},
+		"non json step response in template": {
+			data:     `{"text": "$steps.text"}`,
+			hasError: true,
+		},
+	}
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			m, err := Compile(scenario.data)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			request, err := m.Apply(scope)
+			if scenario.hasError {
+				g.Expect(err).To(gomega.HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(request).To(gomega.MatchJSON(scenario.expected))
+		})
+	}
+}
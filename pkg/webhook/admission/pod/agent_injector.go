@@ -162,6 +162,12 @@ func (ag *AgentInjector) InjectAgent(pod *v1.Pod) error {
 			args = append(args, BatcherArgumentMaxLatency)
 			args = append(args, maxLatency)
 		}
+
+		targetLatency, ok := pod.ObjectMeta.Annotations[constants.BatcherTargetLatencyInternalAnnotationKey]
+		if ok {
+			args = append(args, BatcherArgumentTargetLatency)
+			args = append(args, targetLatency)
+		}
 	}
 	// Only inject if the logger required annotations are set
 	if injectLogger {
@@ -27,12 +27,13 @@ import (
 )
 
 const (
-	BatcherContainerName        = "batcher"
-	BatcherConfigMapKeyName     = "batcher"
-	BatcherEnableFlag           = "--enable-batcher"
-	BatcherArgumentMaxBatchSize = "--max-batchsize"
-	BatcherArgumentMaxLatency   = "--max-latency"
-	BatcherArgumentTimeout      = "--timeout"
+	BatcherContainerName         = "batcher"
+	BatcherConfigMapKeyName      = "batcher"
+	BatcherEnableFlag            = "--enable-batcher"
+	BatcherArgumentMaxBatchSize  = "--max-batchsize"
+	BatcherArgumentMaxLatency    = "--max-latency"
+	BatcherArgumentTimeout       = "--timeout"
+	BatcherArgumentTargetLatency = "--target-latency"
 )
 
 type BatcherConfig struct {
@@ -100,6 +101,12 @@ func (il *BatcherInjector) InjectBatcher(pod *v1.Pod) error {
 		args = append(args, timeout)
 	}
 
+	targetLatency, ok := pod.ObjectMeta.Annotations[constants.BatcherTargetLatencyInternalAnnotationKey]
+	if ok {
+		args = append(args, BatcherArgumentTargetLatency)
+		args = append(args, targetLatency)
+	}
+
 	// Don't inject if Container already injected
 	for _, container := range pod.Spec.Containers {
 		if strings.Compare(container.Name, BatcherContainerName) == 0 {
@@ -118,6 +118,62 @@ func TestBatcherInjector(t *testing.T) {
 				},
 			},
 		},
+		"AddBatcherWithTargetLatency": {
+			original: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "deployment",
+					Namespace: "default",
+					Annotations: map[string]string{
+						constants.BatcherInternalAnnotationKey:              "true",
+						constants.BatcherMaxBatchSizeInternalAnnotationKey:  "32",
+						constants.BatcherMaxLatencyInternalAnnotationKey:    "5000",
+						constants.BatcherTargetLatencyInternalAnnotationKey: "200",
+					},
+					Labels: map[string]string{
+						"serving.kserve.io/inferenceservice": "sklearn",
+						constants.KServiceModelLabel:         "sklearn",
+						constants.KServiceEndpointLabel:      "default",
+						constants.KServiceComponentLabel:     "predictor",
+					},
+				},
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{{
+						Name: "sklearn",
+					}},
+				},
+			},
+			expected: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "deployment",
+					Annotations: map[string]string{
+						constants.BatcherInternalAnnotationKey:              "true",
+						constants.BatcherMaxBatchSizeInternalAnnotationKey:  "32",
+						constants.BatcherMaxLatencyInternalAnnotationKey:    "5000",
+						constants.BatcherTargetLatencyInternalAnnotationKey: "200",
+					},
+				},
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{
+						{
+							Name: "sklearn",
+						},
+						{
+							Name:  BatcherContainerName,
+							Image: batcherConfig.Image,
+							Args: []string{
+								BatcherArgumentMaxBatchSize,
+								"32",
+								BatcherArgumentMaxLatency,
+								"5000",
+								BatcherArgumentTargetLatency,
+								"200",
+							},
+							Resources: batcherResourceRequirement,
+						},
+					},
+				},
+			},
+		},
 		"DoNotAddBatcher": {
 			original: &v1.Pod{
 				ObjectMeta: metav1.ObjectMeta{
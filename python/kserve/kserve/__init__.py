@@ -40,8 +40,12 @@ from .models.v1alpha1_built_in_adapter import V1alpha1BuiltInAdapter
 from .models.v1alpha1_cluster_serving_runtime import V1alpha1ClusterServingRuntime
 from .models.v1alpha1_cluster_serving_runtime_list import V1alpha1ClusterServingRuntimeList
 from .models.v1alpha1_container import V1alpha1Container
+from .models.v1alpha1_ensemble_combiner import V1alpha1EnsembleCombiner
+from .models.v1alpha1_ensemble_failure_policy import V1alpha1EnsembleFailurePolicy
+from .models.v1alpha1_for_each_options import V1alpha1ForEachOptions
 from .models.v1alpha1_inference_graph import V1alpha1InferenceGraph
 from .models.v1alpha1_inference_graph_list import V1alpha1InferenceGraphList
+from .models.v1alpha1_inference_graph_service_status import V1alpha1InferenceGraphServiceStatus
 from .models.v1alpha1_inference_graph_spec import V1alpha1InferenceGraphSpec
 from .models.v1alpha1_inference_graph_status import V1alpha1InferenceGraphStatus
 from .models.v1alpha1_inference_router import V1alpha1InferenceRouter
@@ -52,6 +56,12 @@ from .models.v1alpha1_serving_runtime import V1alpha1ServingRuntime
 from .models.v1alpha1_serving_runtime_list import V1alpha1ServingRuntimeList
 from .models.v1alpha1_serving_runtime_pod_spec import V1alpha1ServingRuntimePodSpec
 from .models.v1alpha1_serving_runtime_spec import V1alpha1ServingRuntimeSpec
+from .models.v1alpha1_splitter_hash_key import V1alpha1SplitterHashKey
+from .models.v1alpha1_step_auth import V1alpha1StepAuth
+from .models.v1alpha1_step_cache import V1alpha1StepCache
+from .models.v1alpha1_step_circuit_breaker import V1alpha1StepCircuitBreaker
+from .models.v1alpha1_step_mirror import V1alpha1StepMirror
+from .models.v1alpha1_step_retry_policy import V1alpha1StepRetryPolicy
 from .models.v1alpha1_storage_helper import V1alpha1StorageHelper
 from .models.v1alpha1_supported_model_format import V1alpha1SupportedModelFormat
 from .models.v1alpha1_trained_model import V1alpha1TrainedModel
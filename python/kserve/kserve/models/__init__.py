@@ -31,8 +31,12 @@ from __future__ import absolute_import
 from kserve.models.v1alpha1_built_in_adapter import V1alpha1BuiltInAdapter
 from kserve.models.v1alpha1_cluster_serving_runtime import V1alpha1ClusterServingRuntime
 from kserve.models.v1alpha1_cluster_serving_runtime_list import V1alpha1ClusterServingRuntimeList
+from kserve.models.v1alpha1_ensemble_combiner import V1alpha1EnsembleCombiner
+from kserve.models.v1alpha1_ensemble_failure_policy import V1alpha1EnsembleFailurePolicy
+from kserve.models.v1alpha1_for_each_options import V1alpha1ForEachOptions
 from kserve.models.v1alpha1_inference_graph import V1alpha1InferenceGraph
 from kserve.models.v1alpha1_inference_graph_list import V1alpha1InferenceGraphList
+from kserve.models.v1alpha1_inference_graph_service_status import V1alpha1InferenceGraphServiceStatus
 from kserve.models.v1alpha1_inference_graph_spec import V1alpha1InferenceGraphSpec
 from kserve.models.v1alpha1_inference_graph_status import V1alpha1InferenceGraphStatus
 from kserve.models.v1alpha1_inference_router import V1alpha1InferenceRouter
@@ -43,6 +47,12 @@ from kserve.models.v1alpha1_serving_runtime import V1alpha1ServingRuntime
 from kserve.models.v1alpha1_serving_runtime_list import V1alpha1ServingRuntimeList
 from kserve.models.v1alpha1_serving_runtime_pod_spec import V1alpha1ServingRuntimePodSpec
 from kserve.models.v1alpha1_serving_runtime_spec import V1alpha1ServingRuntimeSpec
+from kserve.models.v1alpha1_splitter_hash_key import V1alpha1SplitterHashKey
+from kserve.models.v1alpha1_step_auth import V1alpha1StepAuth
+from kserve.models.v1alpha1_step_cache import V1alpha1StepCache
+from kserve.models.v1alpha1_step_circuit_breaker import V1alpha1StepCircuitBreaker
+from kserve.models.v1alpha1_step_mirror import V1alpha1StepMirror
+from kserve.models.v1alpha1_step_retry_policy import V1alpha1StepRetryPolicy
 from kserve.models.v1alpha1_storage_helper import V1alpha1StorageHelper
 from kserve.models.v1alpha1_supported_model_format import V1alpha1SupportedModelFormat
 from kserve.models.v1alpha1_trained_model import V1alpha1TrainedModel
@@ -0,0 +1,193 @@
+# Copyright 2023 The KServe Authors.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#    http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# coding: utf-8
+
+"""
+    KServe
+
+    Python SDK for KServe  # noqa: E501
+
+    The version of the OpenAPI document: v0.1
+    Generated by: https://openapi-generator.tech
+"""
+
+
+import pprint
+import re  # noqa: F401
+
+import six
+
+from kserve.configuration import Configuration
+
+
+class V1alpha1ForEachOptions(object):
+    """NOTE: This class is auto generated by OpenAPI Generator.
+    Ref: https://openapi-generator.tech
+
+    Do not edit the class manually.
+    """
+
+    """
+    Attributes:
+      openapi_types (dict): The key is attribute name
+                            and the value is attribute type.
+      attribute_map (dict): The key is attribute name
+                            and the value is json key in definition.
+    """
+    openapi_types = {
+        'parallelism': 'int',
+        'path': 'str',
+        'response_path': 'str'
+    }
+
+    attribute_map = {
+        'parallelism': 'parallelism',
+        'path': 'path',
+        'response_path': 'responsePath'
+    }
+
+    def __init__(self, parallelism=None, path='', response_path=None, local_vars_configuration=None):  # noqa: E501
+        """V1alpha1ForEachOptions - a model defined in OpenAPI"""  # noqa: E501
+        if local_vars_configuration is None:
+            local_vars_configuration = Configuration()
+        self.local_vars_configuration = local_vars_configuration
+
+        self._parallelism = None
+        self._path = None
+        self._response_path = None
+        self.discriminator = None
+
+        if parallelism is not None:
+            self.parallelism = parallelism
+        self.path = path
+        if response_path is not None:
+            self.response_path = response_path
+
+    @property
+    def parallelism(self):
+        """Gets the parallelism of this V1alpha1ForEachOptions.  # noqa: E501
+
+        Maximum number of elements processed in parallel, defaults to 10  # noqa: E501
+
+        :return: The parallelism of this V1alpha1ForEachOptions.  # noqa: E501
+        :rtype: int
+        """
+        return self._parallelism
+
+    @parallelism.setter
+    def parallelism(self, parallelism):
+        """Sets the parallelism of this V1alpha1ForEachOptions.
+
+        Maximum number of elements processed in parallel, defaults to 10  # noqa: E501
+
+        :param parallelism: The parallelism of this V1alpha1ForEachOptions.  # noqa: E501
+        :type: int
+        """
+
+        self._parallelism = parallelism
+
+    @property
+    def path(self):
+        """Gets the path of this V1alpha1ForEachOptions.  # noqa: E501
+
+        Path of the json array in the request, e.g `instances`  # noqa: E501
+
+        :return: The path of this V1alpha1ForEachOptions.  # noqa: E501
+        :rtype: str
+        """
+        return self._path
+
+    @path.setter
+    def path(self, path):
+        """Sets the path of this V1alpha1ForEachOptions.
+
+        Path of the json array in the request, e.g `instances`  # noqa: E501
+
+        :param path: The path of this V1alpha1ForEachOptions.  # noqa: E501
+        :type: str
+        """
+        if self.local_vars_configuration.client_side_validation and path is None:  # noqa: E501
+            raise ValueError("Invalid value for `path`, must not be `None`")  # noqa: E501
+
+        self._path = path
+
+    @property
+    def response_path(self):
+        """Gets the response_path of this V1alpha1ForEachOptions.  # noqa: E501
+
+        Path of the result in every step response e.g `predictions.0`, defaults to the whole response  # noqa: E501
+
+        :return: The response_path of this V1alpha1ForEachOptions.  # noqa: E501
+        :rtype: str
+        """
+        return self._response_path
+
+    @response_path.setter
+    def response_path(self, response_path):
+        """Sets the response_path of this V1alpha1ForEachOptions.
+
+        Path of the result in every step response e.g `predictions.0`, defaults to the whole response  # noqa: E501
+
+        :param response_path: The response_path of this V1alpha1ForEachOptions.  # noqa: E501
+        :type: str
+        """
+
+        self._response_path = response_path
+
+    def to_dict(self):
+        """Returns the model properties as a dict"""
+        result = {}
+
+        for attr, _ in six.iteritems(self.openapi_types):
+            value = getattr(self, attr)
+            if isinstance(value, list):
+                result[attr] = list(map(
+                    lambda x: x.to_dict() if hasattr(x, "to_dict") else x,
+                    value
+                ))
+            elif hasattr(value, "to_dict"):
+                result[attr] = value.to_dict()
+            elif isinstance(value, dict):
+                result[attr] = dict(map(
+                    lambda item: (item[0], item[1].to_dict())
+                    if hasattr(item[1], "to_dict") else item,
+                    value.items()
+                ))
+            else:
+                result[attr] = value
+
+        return result
+
+    def to_str(self):
+        """Returns the string representation of the model"""
+        return pprint.pformat(self.to_dict())
+
+    def __repr__(self):
+        """For `print` and `pprint`"""
+        return self.to_str()
+
+    def __eq__(self, other):
+        """Returns true if both objects are equal"""
+        if not isinstance(other, V1alpha1ForEachOptions):
+            return False
+
+        return self.to_dict() == other.to_dict()
+
+    def __ne__(self, other):
+        """Returns true if both objects are not equal"""
+        if not isinstance(other, V1alpha1ForEachOptions):
+            return True
+
+        return self.to_dict() != other.to_dict()
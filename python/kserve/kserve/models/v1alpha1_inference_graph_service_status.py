@@ -0,0 +1,250 @@
+# Copyright 2023 The KServe Authors.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#    http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# coding: utf-8
+
+"""
+    KServe
+
+    Python SDK for KServe  # noqa: E501
+
+    The version of the OpenAPI document: v0.1
+    Generated by: https://openapi-generator.tech
+"""
+
+
+import pprint
+import re  # noqa: F401
+
+import six
+
+from kserve.configuration import Configuration
+
+
+class V1alpha1InferenceGraphServiceStatus(object):
+    """NOTE: This class is auto generated by OpenAPI Generator.
+    Ref: https://openapi-generator.tech
+
+    Do not edit the class manually.
+    """
+
+    """
+    Attributes:
+      openapi_types (dict): The key is attribute name
+                            and the value is attribute type.
+      attribute_map (dict): The key is attribute name
+                            and the value is json key in definition.
+    """
+    openapi_types = {
+        'hard': 'bool',
+        'last_transition_time': 'KnativeVolatileTime',
+        'ready': 'bool',
+        'service_name': 'str',
+        'url': 'KnativeURL'
+    }
+
+    attribute_map = {
+        'hard': 'hard',
+        'last_transition_time': 'lastTransitionTime',
+        'ready': 'ready',
+        'service_name': 'serviceName',
+        'url': 'url'
+    }
+
+    def __init__(self, hard=None, last_transition_time=None, ready=False, service_name='', url=None, local_vars_configuration=None):  # noqa: E501
+        """V1alpha1InferenceGraphServiceStatus - a model defined in OpenAPI"""  # noqa: E501
+        if local_vars_configuration is None:
+            local_vars_configuration = Configuration()
+        self.local_vars_configuration = local_vars_configuration
+
+        self._hard = None
+        self._last_transition_time = None
+        self._ready = None
+        self._service_name = None
+        self._url = None
+        self.discriminator = None
+
+        if hard is not None:
+            self.hard = hard
+        if last_transition_time is not None:
+            self.last_transition_time = last_transition_time
+        self.ready = ready
+        self.service_name = service_name
+        if url is not None:
+            self.url = url
+
+    @property
+    def hard(self):
+        """Gets the hard of this V1alpha1InferenceGraphServiceStatus.  # noqa: E501
+
+        Hard is true when a step with a Hard dependency calls the InferenceService, the graph is not ready until the InferenceService is ready  # noqa: E501
+
+        :return: The hard of this V1alpha1InferenceGraphServiceStatus.  # noqa: E501
+        :rtype: bool
+        """
+        return self._hard
+
+    @hard.setter
+    def hard(self, hard):
+        """Sets the hard of this V1alpha1InferenceGraphServiceStatus.
+
+        Hard is true when a step with a Hard dependency calls the InferenceService, the graph is not ready until the InferenceService is ready  # noqa: E501
+
+        :param hard: The hard of this V1alpha1InferenceGraphServiceStatus.  # noqa: E501
+        :type: bool
+        """
+
+        self._hard = hard
+
+    @property
+    def last_transition_time(self):
+        """Gets the last_transition_time of this V1alpha1InferenceGraphServiceStatus.  # noqa: E501
+
+        Last time the readiness of the InferenceService changed  # noqa: E501
+
+        :return: The last_transition_time of this V1alpha1InferenceGraphServiceStatus.  # noqa: E501
+        :rtype: KnativeVolatileTime
+        """
+        return self._last_transition_time
+
+    @last_transition_time.setter
+    def last_transition_time(self, last_transition_time):
+        """Sets the last_transition_time of this V1alpha1InferenceGraphServiceStatus.
+
+        Last time the readiness of the InferenceService changed  # noqa: E501
+
+        :param last_transition_time: The last_transition_time of this V1alpha1InferenceGraphServiceStatus.  # noqa: E501
+        :type: KnativeVolatileTime
+        """
+
+        self._last_transition_time = last_transition_time
+
+    @property
+    def ready(self):
+        """Gets the ready of this V1alpha1InferenceGraphServiceStatus.  # noqa: E501
+
+        Ready is true when the InferenceService is ready  # noqa: E501
+
+        :return: The ready of this V1alpha1InferenceGraphServiceStatus.  # noqa: E501
+        :rtype: bool
+        """
+        return self._ready
+
+    @ready.setter
+    def ready(self, ready):
+        """Sets the ready of this V1alpha1InferenceGraphServiceStatus.
+
+        Ready is true when the InferenceService is ready  # noqa: E501
+
+        :param ready: The ready of this V1alpha1InferenceGraphServiceStatus.  # noqa: E501
+        :type: bool
+        """
+        if self.local_vars_configuration.client_side_validation and ready is None:  # noqa: E501
+            raise ValueError("Invalid value for `ready`, must not be `None`")  # noqa: E501
+
+        self._ready = ready
+
+    @property
+    def service_name(self):
+        """Gets the service_name of this V1alpha1InferenceGraphServiceStatus.  # noqa: E501
+
+        Name of the InferenceService  # noqa: E501
+
+        :return: The service_name of this V1alpha1InferenceGraphServiceStatus.  # noqa: E501
+        :rtype: str
+        """
+        return self._service_name
+
+    @service_name.setter
+    def service_name(self, service_name):
+        """Sets the service_name of this V1alpha1InferenceGraphServiceStatus.
+
+        Name of the InferenceService  # noqa: E501
+
+        :param service_name: The service_name of this V1alpha1InferenceGraphServiceStatus.  # noqa: E501
+        :type: str
+        """
+        if self.local_vars_configuration.client_side_validation and service_name is None:  # noqa: E501
+            raise ValueError("Invalid value for `service_name`, must not be `None`")  # noqa: E501
+
+        self._service_name = service_name
+
+    @property
+    def url(self):
+        """Gets the url of this V1alpha1InferenceGraphServiceStatus.  # noqa: E501
+
+        URL of the InferenceService called by the router  # noqa: E501
+
+        :return: The url of this V1alpha1InferenceGraphServiceStatus.  # noqa: E501
+        :rtype: KnativeURL
+        """
+        return self._url
+
+    @url.setter
+    def url(self, url):
+        """Sets the url of this V1alpha1InferenceGraphServiceStatus.
+
+        URL of the InferenceService called by the router  # noqa: E501
+
+        :param url: The url of this V1alpha1InferenceGraphServiceStatus.  # noqa: E501
+        :type: KnativeURL
+        """
+
+        self._url = url
+
+    def to_dict(self):
+        """Returns the model properties as a dict"""
+        result = {}
+
+        for attr, _ in six.iteritems(self.openapi_types):
+            value = getattr(self, attr)
+            if isinstance(value, list):
+                result[attr] = list(map(
+                    lambda x: x.to_dict() if hasattr(x, "to_dict") else x,
+                    value
+                ))
+            elif hasattr(value, "to_dict"):
+                result[attr] = value.to_dict()
+            elif isinstance(value, dict):
+                result[attr] = dict(map(
+                    lambda item: (item[0], item[1].to_dict())
+                    if hasattr(item[1], "to_dict") else item,
+                    value.items()
+                ))
+            else:
+                result[attr] = value
+
+        return result
+
+    def to_str(self):
+        """Returns the string representation of the model"""
+        return pprint.pformat(self.to_dict())
+
+    def __repr__(self):
+        """For `print` and `pprint`"""
+        return self.to_str()
+
+    def __eq__(self, other):
+        """Returns true if both objects are equal"""
+        if not isinstance(other, V1alpha1InferenceGraphServiceStatus):
+            return False
+
+        return self.to_dict() == other.to_dict()
+
+    def __ne__(self, other):
+        """Returns true if both objects are not equal"""
+        if not isinstance(other, V1alpha1InferenceGraphServiceStatus):
+            return True
+
+        return self.to_dict() != other.to_dict()
@@ -50,6 +50,7 @@ class V1alpha1InferenceGraphStatus(object):
         'annotations': 'dict(str, str)',
         'conditions': 'list[KnativeCondition]',
         'observed_generation': 'int',
+        'services': 'list[V1alpha1InferenceGraphServiceStatus]',
         'url': 'KnativeURL'
     }
 
@@ -57,10 +58,11 @@ class V1alpha1InferenceGraphStatus(object):
         'annotations': 'annotations',
         'conditions': 'conditions',
         'observed_generation': 'observedGeneration',
+        'services': 'services',
         'url': 'url'
     }
 
-    def __init__(self, annotations=None, conditions=None, observed_generation=None, url=None, local_vars_configuration=None):  # noqa: E501
+    def __init__(self, annotations=None, conditions=None, observed_generation=None, services=None, url=None, local_vars_configuration=None):  # noqa: E501
         """V1alpha1InferenceGraphStatus - a model defined in OpenAPI"""  # noqa: E501
         if local_vars_configuration is None:
             local_vars_configuration = Configuration()
@@ -69,6 +71,7 @@ class V1alpha1InferenceGraphStatus(object):
         self._annotations = None
         self._conditions = None
         self._observed_generation = None
+        self._services = None
         self._url = None
         self.discriminator = None
 
@@ -78,6 +81,8 @@ class V1alpha1InferenceGraphStatus(object):
             self.conditions = conditions
         if observed_generation is not None:
             self.observed_generation = observed_generation
+        if services is not None:
+            self.services = services
         if url is not None:
             self.url = url
 
@@ -150,10 +155,34 @@ class V1alpha1InferenceGraphStatus(object):
 
         self._observed_generation = observed_generation
 
+    @property
+    def services(self):
+        """Gets the services of this V1alpha1InferenceGraphStatus.  # noqa: E501
+
+        Status of the InferenceServices referenced by the steps of the graph  # noqa: E501
+
+        :return: The services of this V1alpha1InferenceGraphStatus.  # noqa: E501
+        :rtype: list[V1alpha1InferenceGraphServiceStatus]
+        """
+        return self._services
+
+    @services.setter
+    def services(self, services):
+        """Sets the services of this V1alpha1InferenceGraphStatus.
+
+        Status of the InferenceServices referenced by the steps of the graph  # noqa: E501
+
+        :param services: The services of this V1alpha1InferenceGraphStatus.  # noqa: E501
+        :type: list[V1alpha1InferenceGraphServiceStatus]
+        """
+
+        self._services = services
+
     @property
     def url(self):
         """Gets the url of this V1alpha1InferenceGraphStatus.  # noqa: E501
 
+        Url for the InferenceGraph  # noqa: E501
 
         :return: The url of this V1alpha1InferenceGraphStatus.  # noqa: E501
         :rtype: KnativeURL
@@ -164,6 +193,7 @@ class V1alpha1InferenceGraphStatus(object):
     def url(self, url):
         """Sets the url of this V1alpha1InferenceGraphStatus.
 
+        Url for the InferenceGraph  # noqa: E501
 
         :param url: The url of this V1alpha1InferenceGraphStatus.  # noqa: E501
         :type: KnativeURL
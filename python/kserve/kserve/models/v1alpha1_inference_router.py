@@ -47,34 +47,179 @@ class V1alpha1InferenceRouter(object):
                             and the value is json key in definition.
     """
     openapi_types = {
+        'combiner': 'V1alpha1EnsembleCombiner',
+        'failure_policy': 'V1alpha1EnsembleFailurePolicy',
+        'for_each': 'V1alpha1ForEachOptions',
+        'hash_key': 'V1alpha1SplitterHashKey',
+        'protocol_version': 'str',
         'router_type': 'str',
+        'seed': 'int',
         'steps': 'list[V1alpha1InferenceStep]'
     }
 
     attribute_map = {
+        'combiner': 'combiner',
+        'failure_policy': 'failurePolicy',
+        'for_each': 'forEach',
+        'hash_key': 'hashKey',
+        'protocol_version': 'protocolVersion',
         'router_type': 'routerType',
+        'seed': 'seed',
         'steps': 'steps'
     }
 
-    def __init__(self, router_type='', steps=None, local_vars_configuration=None):  # noqa: E501
+    def __init__(self, combiner=None, failure_policy=None, for_each=None, hash_key=None, protocol_version=None, router_type='', seed=None, steps=None, local_vars_configuration=None):  # noqa: E501
         """V1alpha1InferenceRouter - a model defined in OpenAPI"""  # noqa: E501
         if local_vars_configuration is None:
             local_vars_configuration = Configuration()
         self.local_vars_configuration = local_vars_configuration
 
+        self._combiner = None
+        self._failure_policy = None
+        self._for_each = None
+        self._hash_key = None
+        self._protocol_version = None
         self._router_type = None
+        self._seed = None
         self._steps = None
         self.discriminator = None
 
+        if combiner is not None:
+            self.combiner = combiner
+        if failure_policy is not None:
+            self.failure_policy = failure_policy
+        if for_each is not None:
+            self.for_each = for_each
+        if hash_key is not None:
+            self.hash_key = hash_key
+        if protocol_version is not None:
+            self.protocol_version = protocol_version
         self.router_type = router_type
+        if seed is not None:
+            self.seed = seed
         if steps is not None:
             self.steps = steps
 
+    @property
+    def combiner(self):
+        """Gets the combiner of this V1alpha1InferenceRouter.  # noqa: E501
+
+        Combiner combines the step responses of an Ensemble node into a single prediction returned as `{\"predictions\": <combined>}`. Without a combiner the step responses are returned keyed by step name.  # noqa: E501
+
+        :return: The combiner of this V1alpha1InferenceRouter.  # noqa: E501
+        :rtype: V1alpha1EnsembleCombiner
+        """
+        return self._combiner
+
+    @combiner.setter
+    def combiner(self, combiner):
+        """Sets the combiner of this V1alpha1InferenceRouter.
+
+        Combiner combines the step responses of an Ensemble node into a single prediction returned as `{\"predictions\": <combined>}`. Without a combiner the step responses are returned keyed by step name.  # noqa: E501
+
+        :param combiner: The combiner of this V1alpha1InferenceRouter.  # noqa: E501
+        :type: V1alpha1EnsembleCombiner
+        """
+
+        self._combiner = combiner
+
+    @property
+    def failure_policy(self):
+        """Gets the failure_policy of this V1alpha1InferenceRouter.  # noqa: E501
+
+        FailurePolicy decides whether an Ensemble node succeeds when some of its steps fail, by default at least one step must succeed. Failed steps are reported in the `errors` section of the node response and a failed Hard step always fails the node.  # noqa: E501
+
+        :return: The failure_policy of this V1alpha1InferenceRouter.  # noqa: E501
+        :rtype: V1alpha1EnsembleFailurePolicy
+        """
+        return self._failure_policy
+
+    @failure_policy.setter
+    def failure_policy(self, failure_policy):
+        """Sets the failure_policy of this V1alpha1InferenceRouter.
+
+        FailurePolicy decides whether an Ensemble node succeeds when some of its steps fail, by default at least one step must succeed. Failed steps are reported in the `errors` section of the node response and a failed Hard step always fails the node.  # noqa: E501
+
+        :param failure_policy: The failure_policy of this V1alpha1InferenceRouter.  # noqa: E501
+        :type: V1alpha1EnsembleFailurePolicy
+        """
+
+        self._failure_policy = failure_policy
+
+    @property
+    def for_each(self):
+        """Gets the for_each of this V1alpha1InferenceRouter.  # noqa: E501
+
+        ForEach selects the array a ForEach node iterates over, required by ForEach nodes. The responses of the elements are returned in order as `{\"predictions\": [<response>, ...]}`, failed elements are null and reported by index in the `errors` section of the response, a failed Hard step fails the node.  # noqa: E501
+
+        :return: The for_each of this V1alpha1InferenceRouter.  # noqa: E501
+        :rtype: V1alpha1ForEachOptions
+        """
+        return self._for_each
+
+    @for_each.setter
+    def for_each(self, for_each):
+        """Sets the for_each of this V1alpha1InferenceRouter.
+
+        ForEach selects the array a ForEach node iterates over, required by ForEach nodes. The responses of the elements are returned in order as `{\"predictions\": [<response>, ...]}`, failed elements are null and reported by index in the `errors` section of the response, a failed Hard step fails the node.  # noqa: E501
+
+        :param for_each: The for_each of this V1alpha1InferenceRouter.  # noqa: E501
+        :type: V1alpha1ForEachOptions
+        """
+
+        self._for_each = for_each
+
+    @property
+    def hash_key(self):
+        """Gets the hash_key of this V1alpha1InferenceRouter.  # noqa: E501
+
+        HashKey makes the routing of a Splitter node sticky, requests with the same key value are always routed to the same step. Requests without the key are routed randomly.  # noqa: E501
+
+        :return: The hash_key of this V1alpha1InferenceRouter.  # noqa: E501
+        :rtype: V1alpha1SplitterHashKey
+        """
+        return self._hash_key
+
+    @hash_key.setter
+    def hash_key(self, hash_key):
+        """Sets the hash_key of this V1alpha1InferenceRouter.
+
+        HashKey makes the routing of a Splitter node sticky, requests with the same key value are always routed to the same step. Requests without the key are routed randomly.  # noqa: E501
+
+        :param hash_key: The hash_key of this V1alpha1InferenceRouter.  # noqa: E501
+        :type: V1alpha1SplitterHashKey
+        """
+
+        self._hash_key = hash_key
+
+    @property
+    def protocol_version(self):
+        """Gets the protocol_version of this V1alpha1InferenceRouter.  # noqa: E501
+
+        ProtocolVersion is the inference protocol spoken by the steps of this node, `v1` or `v2`, defaults to `v1`. For `v2` nodes conditions are evaluated against the tensors of the request or response, addressed by name e.g `outputs.label.data.#(==\"dog\")`, and `$response` passes the output tensors of the previous step as inputs.  # noqa: E501
+
+        :return: The protocol_version of this V1alpha1InferenceRouter.  # noqa: E501
+        :rtype: str
+        """
+        return self._protocol_version
+
+    @protocol_version.setter
+    def protocol_version(self, protocol_version):
+        """Sets the protocol_version of this V1alpha1InferenceRouter.
+
+        ProtocolVersion is the inference protocol spoken by the steps of this node, `v1` or `v2`, defaults to `v1`. For `v2` nodes conditions are evaluated against the tensors of the request or response, addressed by name e.g `outputs.label.data.#(==\"dog\")`, and `$response` passes the output tensors of the previous step as inputs.  # noqa: E501
+
+        :param protocol_version: The protocol_version of this V1alpha1InferenceRouter.  # noqa: E501
+        :type: str
+        """
+
+        self._protocol_version = protocol_version
+
     @property
     def router_type(self):
         """Gets the router_type of this V1alpha1InferenceRouter.  # noqa: E501
 
-        RouterType  - `Sequence:` chain multiple inference steps with input/output from previous step  - `Splitter:` randomly routes to the target service according to the weight  - `Ensemble:` routes the request to multiple models and then merge the responses  - `Switch:` routes the request to one of the steps based on condition  # noqa: E501
+        RouterType  - `Sequence:` chain multiple inference steps with input/output from previous step  - `Splitter:` randomly routes to the target service according to the weight  - `Ensemble:` routes the request to multiple models and then merge the responses  - `Switch:` routes the request to one of the steps based on condition  - `ForEach:` routes every element of an array in the request to the step and gathers the responses in order  # noqa: E501
 
         :return: The router_type of this V1alpha1InferenceRouter.  # noqa: E501
         :rtype: str
@@ -85,7 +230,7 @@ class V1alpha1InferenceRouter(object):
     def router_type(self, router_type):
         """Sets the router_type of this V1alpha1InferenceRouter.
 
-        RouterType  - `Sequence:` chain multiple inference steps with input/output from previous step  - `Splitter:` randomly routes to the target service according to the weight  - `Ensemble:` routes the request to multiple models and then merge the responses  - `Switch:` routes the request to one of the steps based on condition  # noqa: E501
+        RouterType  - `Sequence:` chain multiple inference steps with input/output from previous step  - `Splitter:` randomly routes to the target service according to the weight  - `Ensemble:` routes the request to multiple models and then merge the responses  - `Switch:` routes the request to one of the steps based on condition  - `ForEach:` routes every element of an array in the request to the step and gathers the responses in order  # noqa: E501
 
         :param router_type: The router_type of this V1alpha1InferenceRouter.  # noqa: E501
         :type: str
@@ -95,6 +240,29 @@ class V1alpha1InferenceRouter(object):
 
         self._router_type = router_type
 
+    @property
+    def seed(self):
+        """Gets the seed of this V1alpha1InferenceRouter.  # noqa: E501
+
+        Seed of the random routing of a Splitter node, a fixed seed makes the sequence of routes reproducible  # noqa: E501
+
+        :return: The seed of this V1alpha1InferenceRouter.  # noqa: E501
+        :rtype: int
+        """
+        return self._seed
+
+    @seed.setter
+    def seed(self, seed):
+        """Sets the seed of this V1alpha1InferenceRouter.
+
+        Seed of the random routing of a Splitter node, a fixed seed makes the sequence of routes reproducible  # noqa: E501
+
+        :param seed: The seed of this V1alpha1InferenceRouter.  # noqa: E501
+        :type: int
+        """
+
+        self._seed = seed
+
     @property
     def steps(self):
         """Gets the steps of this V1alpha1InferenceRouter.  # noqa: E501
@@ -47,60 +47,169 @@ class V1alpha1InferenceStep(object):
                             and the value is json key in definition.
     """
     openapi_types = {
+        'auth': 'V1alpha1StepAuth',
+        'cache': 'V1alpha1StepCache',
+        'circuit_breaker': 'V1alpha1StepCircuitBreaker',
         'condition': 'str',
         'data': 'str',
+        'dependency': 'str',
+        'mirror': 'V1alpha1StepMirror',
         'name': 'str',
         'node_name': 'str',
+        'protocol_version': 'str',
+        'retry': 'V1alpha1StepRetryPolicy',
         'service_name': 'str',
         'service_url': 'str',
+        'timeout': 'int',
         'weight': 'int'
     }
 
     attribute_map = {
+        'auth': 'auth',
+        'cache': 'cache',
+        'circuit_breaker': 'circuitBreaker',
         'condition': 'condition',
         'data': 'data',
+        'dependency': 'dependency',
+        'mirror': 'mirror',
         'name': 'name',
         'node_name': 'nodeName',
+        'protocol_version': 'protocolVersion',
+        'retry': 'retry',
         'service_name': 'serviceName',
         'service_url': 'serviceUrl',
+        'timeout': 'timeout',
         'weight': 'weight'
     }
 
-    def __init__(self, condition=None, data=None, name=None, node_name=None, service_name=None, service_url=None, weight=None, local_vars_configuration=None):  # noqa: E501
+    def __init__(self, auth=None, cache=None, circuit_breaker=None, condition=None, data=None, dependency=None, mirror=None, name=None, node_name=None, protocol_version=None, retry=None, service_name=None, service_url=None, timeout=None, weight=None, local_vars_configuration=None):  # noqa: E501
         """V1alpha1InferenceStep - a model defined in OpenAPI"""  # noqa: E501
         if local_vars_configuration is None:
             local_vars_configuration = Configuration()
         self.local_vars_configuration = local_vars_configuration
 
+        self._auth = None
+        self._cache = None
+        self._circuit_breaker = None
         self._condition = None
         self._data = None
+        self._dependency = None
+        self._mirror = None
         self._name = None
         self._node_name = None
+        self._protocol_version = None
+        self._retry = None
         self._service_name = None
         self._service_url = None
+        self._timeout = None
         self._weight = None
         self.discriminator = None
 
+        if auth is not None:
+            self.auth = auth
+        if cache is not None:
+            self.cache = cache
+        if circuit_breaker is not None:
+            self.circuit_breaker = circuit_breaker
         if condition is not None:
             self.condition = condition
         if data is not None:
             self.data = data
+        if dependency is not None:
+            self.dependency = dependency
+        if mirror is not None:
+            self.mirror = mirror
         if name is not None:
             self.name = name
         if node_name is not None:
             self.node_name = node_name
+        if protocol_version is not None:
+            self.protocol_version = protocol_version
+        if retry is not None:
+            self.retry = retry
         if service_name is not None:
             self.service_name = service_name
         if service_url is not None:
             self.service_url = service_url
+        if timeout is not None:
+            self.timeout = timeout
         if weight is not None:
             self.weight = weight
 
+    @property
+    def auth(self):
+        """Gets the auth of this V1alpha1InferenceStep.  # noqa: E501
+
+        Auth sets the credentials the router sends to the step service, the reconciler mounts the ServiceAccount tokens and secrets the router needs in the router pod  # noqa: E501
+
+        :return: The auth of this V1alpha1InferenceStep.  # noqa: E501
+        :rtype: V1alpha1StepAuth
+        """
+        return self._auth
+
+    @auth.setter
+    def auth(self, auth):
+        """Sets the auth of this V1alpha1InferenceStep.
+
+        Auth sets the credentials the router sends to the step service, the reconciler mounts the ServiceAccount tokens and secrets the router needs in the router pod  # noqa: E501
+
+        :param auth: The auth of this V1alpha1InferenceStep.  # noqa: E501
+        :type: V1alpha1StepAuth
+        """
+
+        self._auth = auth
+
+    @property
+    def cache(self):
+        """Gets the cache of this V1alpha1InferenceStep.  # noqa: E501
+
+        Cache serves the responses of the step from an in memory cache of the router, requests with the same body and cache headers as an earlier request are not sent to the step target again until the response expires  # noqa: E501
+
+        :return: The cache of this V1alpha1InferenceStep.  # noqa: E501
+        :rtype: V1alpha1StepCache
+        """
+        return self._cache
+
+    @cache.setter
+    def cache(self, cache):
+        """Sets the cache of this V1alpha1InferenceStep.
+
+        Cache serves the responses of the step from an in memory cache of the router, requests with the same body and cache headers as an earlier request are not sent to the step target again until the response expires  # noqa: E501
+
+        :param cache: The cache of this V1alpha1InferenceStep.  # noqa: E501
+        :type: V1alpha1StepCache
+        """
+
+        self._cache = cache
+
+    @property
+    def circuit_breaker(self):
+        """Gets the circuit_breaker of this V1alpha1InferenceStep.  # noqa: E501
+
+        CircuitBreaker stops the router from calling the step service for a while after consecutive failures  # noqa: E501
+
+        :return: The circuit_breaker of this V1alpha1InferenceStep.  # noqa: E501
+        :rtype: V1alpha1StepCircuitBreaker
+        """
+        return self._circuit_breaker
+
+    @circuit_breaker.setter
+    def circuit_breaker(self, circuit_breaker):
+        """Sets the circuit_breaker of this V1alpha1InferenceStep.
+
+        CircuitBreaker stops the router from calling the step service for a while after consecutive failures  # noqa: E501
+
+        :param circuit_breaker: The circuit_breaker of this V1alpha1InferenceStep.  # noqa: E501
+        :type: V1alpha1StepCircuitBreaker
+        """
+
+        self._circuit_breaker = circuit_breaker
+
     @property
     def condition(self):
         """Gets the condition of this V1alpha1InferenceStep.  # noqa: E501
 
-        routing based on the condition  # noqa: E501
+        routing based on the condition, a CEL expression evaluating to a bool over the json `body` of the request (Switch) or previous step response (Sequence) and the request `headers` keyed by lower case name, e.g `body.instances[0].userId == 1 && headers[\"x-tenant\"] == \"gold\"`. Conditions which are not CEL expressions are gjson paths which match when the path exists.  # noqa: E501
 
         :return: The condition of this V1alpha1InferenceStep.  # noqa: E501
         :rtype: str
@@ -111,7 +220,7 @@ class V1alpha1InferenceStep(object):
     def condition(self, condition):
         """Sets the condition of this V1alpha1InferenceStep.
 
-        routing based on the condition  # noqa: E501
+        routing based on the condition, a CEL expression evaluating to a bool over the json `body` of the request (Switch) or previous step response (Sequence) and the request `headers` keyed by lower case name, e.g `body.instances[0].userId == 1 && headers[\"x-tenant\"] == \"gold\"`. Conditions which are not CEL expressions are gjson paths which match when the path exists.  # noqa: E501
 
         :param condition: The condition of this V1alpha1InferenceStep.  # noqa: E501
         :type: str
@@ -123,7 +232,7 @@ class V1alpha1InferenceStep(object):
     def data(self):
         """Gets the data of this V1alpha1InferenceStep.  # noqa: E501
 
-        request data sent to the next route with input/output from the previous step $request $response.predictions  # noqa: E501
+        request data sent to the next route with input/output from the previous step, only the steps of Sequence and ForEach nodes can have data. Data is either a single reference or a json template in which strings starting with `$` are references and all other values are constants. `$request` refers to the request of the node, `$response` to the response of the previous step and `$steps.<name>` to the response of an earlier step in a Sequence node, each optionally followed by a json path, e.g `{\"instances\": \"$steps.preprocess.predictions\", \"threshold\": 0.5}`. Strings starting with `$$` are constants starting with `$`. For v2 nodes `$response.<name>` sends only the named output tensor of the previous step as input  # noqa: E501
 
         :return: The data of this V1alpha1InferenceStep.  # noqa: E501
         :rtype: str
@@ -134,7 +243,7 @@ class V1alpha1InferenceStep(object):
     def data(self, data):
         """Sets the data of this V1alpha1InferenceStep.
 
-        request data sent to the next route with input/output from the previous step $request $response.predictions  # noqa: E501
+        request data sent to the next route with input/output from the previous step, only the steps of Sequence and ForEach nodes can have data. Data is either a single reference or a json template in which strings starting with `$` are references and all other values are constants. `$request` refers to the request of the node, `$response` to the response of the previous step and `$steps.<name>` to the response of an earlier step in a Sequence node, each optionally followed by a json path, e.g `{\"instances\": \"$steps.preprocess.predictions\", \"threshold\": 0.5}`. Strings starting with `$$` are constants starting with `$`. For v2 nodes `$response.<name>` sends only the named output tensor of the previous step as input  # noqa: E501
 
         :param data: The data of this V1alpha1InferenceStep.  # noqa: E501
         :type: str
@@ -142,6 +251,52 @@ class V1alpha1InferenceStep(object):
 
         self._data = data
 
+    @property
+    def dependency(self):
+        """Gets the dependency of this V1alpha1InferenceStep.  # noqa: E501
+
+        to decide whether a step is a hard or a soft dependency in the Inference Graph  # noqa: E501
+
+        :return: The dependency of this V1alpha1InferenceStep.  # noqa: E501
+        :rtype: str
+        """
+        return self._dependency
+
+    @dependency.setter
+    def dependency(self, dependency):
+        """Sets the dependency of this V1alpha1InferenceStep.
+
+        to decide whether a step is a hard or a soft dependency in the Inference Graph  # noqa: E501
+
+        :param dependency: The dependency of this V1alpha1InferenceStep.  # noqa: E501
+        :type: str
+        """
+
+        self._dependency = dependency
+
+    @property
+    def mirror(self):
+        """Gets the mirror of this V1alpha1InferenceStep.  # noqa: E501
+
+        Mirror sends a copy of the step request to a shadow target, the shadow response is only logged and reported in the router metrics and never affects the response of the step  # noqa: E501
+
+        :return: The mirror of this V1alpha1InferenceStep.  # noqa: E501
+        :rtype: V1alpha1StepMirror
+        """
+        return self._mirror
+
+    @mirror.setter
+    def mirror(self, mirror):
+        """Sets the mirror of this V1alpha1InferenceStep.
+
+        Mirror sends a copy of the step request to a shadow target, the shadow response is only logged and reported in the router metrics and never affects the response of the step  # noqa: E501
+
+        :param mirror: The mirror of this V1alpha1InferenceStep.  # noqa: E501
+        :type: V1alpha1StepMirror
+        """
+
+        self._mirror = mirror
+
     @property
     def name(self):
         """Gets the name of this V1alpha1InferenceStep.  # noqa: E501
@@ -188,6 +343,52 @@ class V1alpha1InferenceStep(object):
 
         self._node_name = node_name
 
+    @property
+    def protocol_version(self):
+        """Gets the protocol_version of this V1alpha1InferenceStep.  # noqa: E501
+
+        ProtocolVersion of the step service, `grpc-v2` calls the service over the v2 gRPC inference protocol instead of http. A `grpc://` or `grpcs://` serviceUrl also selects gRPC. gRPC steps require a `v2` node.  # noqa: E501
+
+        :return: The protocol_version of this V1alpha1InferenceStep.  # noqa: E501
+        :rtype: str
+        """
+        return self._protocol_version
+
+    @protocol_version.setter
+    def protocol_version(self, protocol_version):
+        """Sets the protocol_version of this V1alpha1InferenceStep.
+
+        ProtocolVersion of the step service, `grpc-v2` calls the service over the v2 gRPC inference protocol instead of http. A `grpc://` or `grpcs://` serviceUrl also selects gRPC. gRPC steps require a `v2` node.  # noqa: E501
+
+        :param protocol_version: The protocol_version of this V1alpha1InferenceStep.  # noqa: E501
+        :type: str
+        """
+
+        self._protocol_version = protocol_version
+
+    @property
+    def retry(self):
+        """Gets the retry of this V1alpha1InferenceStep.  # noqa: E501
+
+        RetryPolicy specifies how the router retries a failed call to the step service  # noqa: E501
+
+        :return: The retry of this V1alpha1InferenceStep.  # noqa: E501
+        :rtype: V1alpha1StepRetryPolicy
+        """
+        return self._retry
+
+    @retry.setter
+    def retry(self, retry):
+        """Sets the retry of this V1alpha1InferenceStep.
+
+        RetryPolicy specifies how the router retries a failed call to the step service  # noqa: E501
+
+        :param retry: The retry of this V1alpha1InferenceStep.  # noqa: E501
+        :type: V1alpha1StepRetryPolicy
+        """
+
+        self._retry = retry
+
     @property
     def service_name(self):
         """Gets the service_name of this V1alpha1InferenceStep.  # noqa: E501
@@ -234,11 +435,34 @@ class V1alpha1InferenceStep(object):
 
         self._service_url = service_url
 
+    @property
+    def timeout(self):
+        """Gets the timeout of this V1alpha1InferenceStep.  # noqa: E501
+
+        TimeoutSeconds specifies the number of seconds to wait for the step service to respond before cancelling the call. When a retry policy is set the timeout applies to every attempt. On a step targeting a node the timeout bounds the routing of the whole node. A response streamed back to the client is not bounded by the timeout once the step service has started to respond.  # noqa: E501
+
+        :return: The timeout of this V1alpha1InferenceStep.  # noqa: E501
+        :rtype: int
+        """
+        return self._timeout
+
+    @timeout.setter
+    def timeout(self, timeout):
+        """Sets the timeout of this V1alpha1InferenceStep.
+
+        TimeoutSeconds specifies the number of seconds to wait for the step service to respond before cancelling the call. When a retry policy is set the timeout applies to every attempt. On a step targeting a node the timeout bounds the routing of the whole node. A response streamed back to the client is not bounded by the timeout once the step service has started to respond.  # noqa: E501
+
+        :param timeout: The timeout of this V1alpha1InferenceStep.  # noqa: E501
+        :type: int
+        """
+
+        self._timeout = timeout
+
     @property
     def weight(self):
         """Gets the weight of this V1alpha1InferenceStep.  # noqa: E501
 
-        the weight for split of the traffic, only used for Split Router when weight is specified all the routing targets should be sum to 100  # noqa: E501
+        the weight for split of the traffic, only used for Split Router when weight is specified all the routing targets should be sum to 100 For Ensemble nodes with a WeightedMean combiner it is the weight of the step prediction  # noqa: E501
 
         :return: The weight of this V1alpha1InferenceStep.  # noqa: E501
         :rtype: int
@@ -249,7 +473,7 @@ class V1alpha1InferenceStep(object):
     def weight(self, weight):
         """Sets the weight of this V1alpha1InferenceStep.
 
-        the weight for split of the traffic, only used for Split Router when weight is specified all the routing targets should be sum to 100  # noqa: E501
+        the weight for split of the traffic, only used for Split Router when weight is specified all the routing targets should be sum to 100 For Ensemble nodes with a WeightedMean combiner it is the weight of the step prediction  # noqa: E501
 
         :param weight: The weight of this V1alpha1InferenceStep.  # noqa: E501
         :type: int
@@ -0,0 +1,221 @@
+# Copyright 2023 The KServe Authors.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#    http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# coding: utf-8
+
+"""
+    KServe
+
+    Python SDK for KServe  # noqa: E501
+
+    The version of the OpenAPI document: v0.1
+    Generated by: https://openapi-generator.tech
+"""
+
+
+import pprint
+import re  # noqa: F401
+
+import six
+
+from kserve.configuration import Configuration
+
+
+class V1alpha1StepAuth(object):
+    """NOTE: This class is auto generated by OpenAPI Generator.
+    Ref: https://openapi-generator.tech
+
+    Do not edit the class manually.
+    """
+
+    """
+    Attributes:
+      openapi_types (dict): The key is attribute name
+                            and the value is attribute type.
+      attribute_map (dict): The key is attribute name
+                            and the value is json key in definition.
+    """
+    openapi_types = {
+        'audience': 'str',
+        'header': 'str',
+        'secret_key_ref': 'V1SecretKeySelector',
+        'type': 'str'
+    }
+
+    attribute_map = {
+        'audience': 'audience',
+        'header': 'header',
+        'secret_key_ref': 'secretKeyRef',
+        'type': 'type'
+    }
+
+    def __init__(self, audience=None, header=None, secret_key_ref=None, type='', local_vars_configuration=None):  # noqa: E501
+        """V1alpha1StepAuth - a model defined in OpenAPI"""  # noqa: E501
+        if local_vars_configuration is None:
+            local_vars_configuration = Configuration()
+        self.local_vars_configuration = local_vars_configuration
+
+        self._audience = None
+        self._header = None
+        self._secret_key_ref = None
+        self._type = None
+        self.discriminator = None
+
+        if audience is not None:
+            self.audience = audience
+        if header is not None:
+            self.header = header
+        if secret_key_ref is not None:
+            self.secret_key_ref = secret_key_ref
+        self.type = type
+
+    @property
+    def audience(self):
+        """Gets the audience of this V1alpha1StepAuth.  # noqa: E501
+
+        Audience of the projected ServiceAccount token, defaults to the audience of the API server. Only for the ServiceAccountToken type  # noqa: E501
+
+        :return: The audience of this V1alpha1StepAuth.  # noqa: E501
+        :rtype: str
+        """
+        return self._audience
+
+    @audience.setter
+    def audience(self, audience):
+        """Sets the audience of this V1alpha1StepAuth.
+
+        Audience of the projected ServiceAccount token, defaults to the audience of the API server. Only for the ServiceAccountToken type  # noqa: E501
+
+        :param audience: The audience of this V1alpha1StepAuth.  # noqa: E501
+        :type: str
+        """
+
+        self._audience = audience
+
+    @property
+    def header(self):
+        """Gets the header of this V1alpha1StepAuth.  # noqa: E501
+
+        Name of the header set from the Secret, defaults to Authorization. Only for the SecretHeader type  # noqa: E501
+
+        :return: The header of this V1alpha1StepAuth.  # noqa: E501
+        :rtype: str
+        """
+        return self._header
+
+    @header.setter
+    def header(self, header):
+        """Sets the header of this V1alpha1StepAuth.
+
+        Name of the header set from the Secret, defaults to Authorization. Only for the SecretHeader type  # noqa: E501
+
+        :param header: The header of this V1alpha1StepAuth.  # noqa: E501
+        :type: str
+        """
+
+        self._header = header
+
+    @property
+    def secret_key_ref(self):
+        """Gets the secret_key_ref of this V1alpha1StepAuth.  # noqa: E501
+
+        Key of the Secret in the namespace of the graph holding the header value. Only for the SecretHeader type  # noqa: E501
+
+        :return: The secret_key_ref of this V1alpha1StepAuth.  # noqa: E501
+        :rtype: V1SecretKeySelector
+        """
+        return self._secret_key_ref
+
+    @secret_key_ref.setter
+    def secret_key_ref(self, secret_key_ref):
+        """Sets the secret_key_ref of this V1alpha1StepAuth.
+
+        Key of the Secret in the namespace of the graph holding the header value. Only for the SecretHeader type  # noqa: E501
+
+        :param secret_key_ref: The secret_key_ref of this V1alpha1StepAuth.  # noqa: E501
+        :type: V1SecretKeySelector
+        """
+
+        self._secret_key_ref = secret_key_ref
+
+    @property
+    def type(self):
+        """Gets the type of this V1alpha1StepAuth.  # noqa: E501
+
+        Type of the credentials  # noqa: E501
+
+        :return: The type of this V1alpha1StepAuth.  # noqa: E501
+        :rtype: str
+        """
+        return self._type
+
+    @type.setter
+    def type(self, type):
+        """Sets the type of this V1alpha1StepAuth.
+
+        Type of the credentials  # noqa: E501
+
+        :param type: The type of this V1alpha1StepAuth.  # noqa: E501
+        :type: str
+        """
+        if self.local_vars_configuration.client_side_validation and type is None:  # noqa: E501
+            raise ValueError("Invalid value for `type`, must not be `None`")  # noqa: E501
+
+        self._type = type
+
+    def to_dict(self):
+        """Returns the model properties as a dict"""
+        result = {}
+
+        for attr, _ in six.iteritems(self.openapi_types):
+            value = getattr(self, attr)
+            if isinstance(value, list):
+                result[attr] = list(map(
+                    lambda x: x.to_dict() if hasattr(x, "to_dict") else x,
+                    value
+                ))
+            elif hasattr(value, "to_dict"):
+                result[attr] = value.to_dict()
+            elif isinstance(value, dict):
+                result[attr] = dict(map(
+                    lambda item: (item[0], item[1].to_dict())
+                    if hasattr(item[1], "to_dict") else item,
+                    value.items()
+                ))
+            else:
+                result[attr] = value
+
+        return result
+
+    def to_str(self):
+        """Returns the string representation of the model"""
+        return pprint.pformat(self.to_dict())
+
+    def __repr__(self):
+        """For `print` and `pprint`"""
+        return self.to_str()
+
+    def __eq__(self, other):
+        """Returns true if both objects are equal"""
+        if not isinstance(other, V1alpha1StepAuth):
+            return False
+
+        return self.to_dict() == other.to_dict()
+
+    def __ne__(self, other):
+        """Returns true if both objects are not equal"""
+        if not isinstance(other, V1alpha1StepAuth):
+            return True
+
+        return self.to_dict() != other.to_dict()
@@ -0,0 +1,192 @@
+# Copyright 2023 The KServe Authors.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#    http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# coding: utf-8
+
+"""
+    KServe
+
+    Python SDK for KServe  # noqa: E501
+
+    The version of the OpenAPI document: v0.1
+    Generated by: https://openapi-generator.tech
+"""
+
+
+import pprint
+import re  # noqa: F401
+
+import six
+
+from kserve.configuration import Configuration
+
+
+class V1alpha1StepCache(object):
+    """NOTE: This class is auto generated by OpenAPI Generator.
+    Ref: https://openapi-generator.tech
+
+    Do not edit the class manually.
+    """
+
+    """
+    Attributes:
+      openapi_types (dict): The key is attribute name
+                            and the value is attribute type.
+      attribute_map (dict): The key is attribute name
+                            and the value is json key in definition.
+    """
+    openapi_types = {
+        'headers': 'list[str]',
+        'max_entries': 'int',
+        'ttl': 'int'
+    }
+
+    attribute_map = {
+        'headers': 'headers',
+        'max_entries': 'maxEntries',
+        'ttl': 'ttl'
+    }
+
+    def __init__(self, headers=None, max_entries=None, ttl=None, local_vars_configuration=None):  # noqa: E501
+        """V1alpha1StepCache - a model defined in OpenAPI"""  # noqa: E501
+        if local_vars_configuration is None:
+            local_vars_configuration = Configuration()
+        self.local_vars_configuration = local_vars_configuration
+
+        self._headers = None
+        self._max_entries = None
+        self._ttl = None
+        self.discriminator = None
+
+        if headers is not None:
+            self.headers = headers
+        if max_entries is not None:
+            self.max_entries = max_entries
+        if ttl is not None:
+            self.ttl = ttl
+
+    @property
+    def headers(self):
+        """Gets the headers of this V1alpha1StepCache.  # noqa: E501
+
+        Request headers which are part of the cache key in addition to the request body  # noqa: E501
+
+        :return: The headers of this V1alpha1StepCache.  # noqa: E501
+        :rtype: list[str]
+        """
+        return self._headers
+
+    @headers.setter
+    def headers(self, headers):
+        """Sets the headers of this V1alpha1StepCache.
+
+        Request headers which are part of the cache key in addition to the request body  # noqa: E501
+
+        :param headers: The headers of this V1alpha1StepCache.  # noqa: E501
+        :type: list[str]
+        """
+
+        self._headers = headers
+
+    @property
+    def max_entries(self):
+        """Gets the max_entries of this V1alpha1StepCache.  # noqa: E501
+
+        Maximum number of responses cached for the step, defaults to 1000  # noqa: E501
+
+        :return: The max_entries of this V1alpha1StepCache.  # noqa: E501
+        :rtype: int
+        """
+        return self._max_entries
+
+    @max_entries.setter
+    def max_entries(self, max_entries):
+        """Sets the max_entries of this V1alpha1StepCache.
+
+        Maximum number of responses cached for the step, defaults to 1000  # noqa: E501
+
+        :param max_entries: The max_entries of this V1alpha1StepCache.  # noqa: E501
+        :type: int
+        """
+
+        self._max_entries = max_entries
+
+    @property
+    def ttl(self):
+        """Gets the ttl of this V1alpha1StepCache.  # noqa: E501
+
+        Number of seconds a cached response is served, defaults to 60  # noqa: E501
+
+        :return: The ttl of this V1alpha1StepCache.  # noqa: E501
+        :rtype: int
+        """
+        return self._ttl
+
+    @ttl.setter
+    def ttl(self, ttl):
+        """Sets the ttl of this V1alpha1StepCache.
+
+        Number of seconds a cached response is served, defaults to 60  # noqa: E501
+
+        :param ttl: The ttl of this V1alpha1StepCache.  # noqa: E501
+        :type: int
+        """
+
+        self._ttl = ttl
+
+    def to_dict(self):
+        """Returns the model properties as a dict"""
+        result = {}
+
+        for attr, _ in six.iteritems(self.openapi_types):
+            value = getattr(self, attr)
+            if isinstance(value, list):
+                result[attr] = list(map(
+                    lambda x: x.to_dict() if hasattr(x, "to_dict") else x,
+                    value
+                ))
+            elif hasattr(value, "to_dict"):
+                result[attr] = value.to_dict()
+            elif isinstance(value, dict):
+                result[attr] = dict(map(
+                    lambda item: (item[0], item[1].to_dict())
+                    if hasattr(item[1], "to_dict") else item,
+                    value.items()
+                ))
+            else:
+                result[attr] = value
+
+        return result
+
+    def to_str(self):
+        """Returns the string representation of the model"""
+        return pprint.pformat(self.to_dict())
+
+    def __repr__(self):
+        """For `print` and `pprint`"""
+        return self.to_str()
+
+    def __eq__(self, other):
+        """Returns true if both objects are equal"""
+        if not isinstance(other, V1alpha1StepCache):
+            return False
+
+        return self.to_dict() == other.to_dict()
+
+    def __ne__(self, other):
+        """Returns true if both objects are not equal"""
+        if not isinstance(other, V1alpha1StepCache):
+            return True
+
+        return self.to_dict() != other.to_dict()
@@ -0,0 +1,164 @@
+# Copyright 2023 The KServe Authors.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#    http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# coding: utf-8
+
+"""
+    KServe
+
+    Python SDK for KServe  # noqa: E501
+
+    The version of the OpenAPI document: v0.1
+    Generated by: https://openapi-generator.tech
+"""
+
+
+import pprint
+import re  # noqa: F401
+
+import six
+
+from kserve.configuration import Configuration
+
+
+class V1alpha1StepCircuitBreaker(object):
+    """NOTE: This class is auto generated by OpenAPI Generator.
+    Ref: https://openapi-generator.tech
+
+    Do not edit the class manually.
+    """
+
+    """
+    Attributes:
+      openapi_types (dict): The key is attribute name
+                            and the value is attribute type.
+      attribute_map (dict): The key is attribute name
+                            and the value is json key in definition.
+    """
+    openapi_types = {
+        'failure_threshold': 'int',
+        'reset_timeout': 'int'
+    }
+
+    attribute_map = {
+        'failure_threshold': 'failureThreshold',
+        'reset_timeout': 'resetTimeout'
+    }
+
+    def __init__(self, failure_threshold=None, reset_timeout=None, local_vars_configuration=None):  # noqa: E501
+        """V1alpha1StepCircuitBreaker - a model defined in OpenAPI"""  # noqa: E501
+        if local_vars_configuration is None:
+            local_vars_configuration = Configuration()
+        self.local_vars_configuration = local_vars_configuration
+
+        self._failure_threshold = None
+        self._reset_timeout = None
+        self.discriminator = None
+
+        if failure_threshold is not None:
+            self.failure_threshold = failure_threshold
+        if reset_timeout is not None:
+            self.reset_timeout = reset_timeout
+
+    @property
+    def failure_threshold(self):
+        """Gets the failure_threshold of this V1alpha1StepCircuitBreaker.  # noqa: E501
+
+        Number of consecutive failed calls that opens the circuit, defaults to 5  # noqa: E501
+
+        :return: The failure_threshold of this V1alpha1StepCircuitBreaker.  # noqa: E501
+        :rtype: int
+        """
+        return self._failure_threshold
+
+    @failure_threshold.setter
+    def failure_threshold(self, failure_threshold):
+        """Sets the failure_threshold of this V1alpha1StepCircuitBreaker.
+
+        Number of consecutive failed calls that opens the circuit, defaults to 5  # noqa: E501
+
+        :param failure_threshold: The failure_threshold of this V1alpha1StepCircuitBreaker.  # noqa: E501
+        :type: int
+        """
+
+        self._failure_threshold = failure_threshold
+
+    @property
+    def reset_timeout(self):
+        """Gets the reset_timeout of this V1alpha1StepCircuitBreaker.  # noqa: E501
+
+        Number of seconds the circuit stays open before a single trial call is let through, defaults to 30  # noqa: E501
+
+        :return: The reset_timeout of this V1alpha1StepCircuitBreaker.  # noqa: E501
+        :rtype: int
+        """
+        return self._reset_timeout
+
+    @reset_timeout.setter
+    def reset_timeout(self, reset_timeout):
+        """Sets the reset_timeout of this V1alpha1StepCircuitBreaker.
+
+        Number of seconds the circuit stays open before a single trial call is let through, defaults to 30  # noqa: E501
+
+        :param reset_timeout: The reset_timeout of this V1alpha1StepCircuitBreaker.  # noqa: E501
+        :type: int
+        """
+
+        self._reset_timeout = reset_timeout
+
+    def to_dict(self):
+        """Returns the model properties as a dict"""
+        result = {}
+
+        for attr, _ in six.iteritems(self.openapi_types):
+            value = getattr(self, attr)
+            if isinstance(value, list):
+                result[attr] = list(map(
+                    lambda x: x.to_dict() if hasattr(x, "to_dict") else x,
+                    value
+                ))
+            elif hasattr(value, "to_dict"):
+                result[attr] = value.to_dict()
+            elif isinstance(value, dict):
+                result[attr] = dict(map(
+                    lambda item: (item[0], item[1].to_dict())
+                    if hasattr(item[1], "to_dict") else item,
+                    value.items()
+                ))
+            else:
+                result[attr] = value
+
+        return result
+
+    def to_str(self):
+        """Returns the string representation of the model"""
+        return pprint.pformat(self.to_dict())
+
+    def __repr__(self):
+        """For `print` and `pprint`"""
+        return self.to_str()
+
+    def __eq__(self, other):
+        """Returns true if both objects are equal"""
+        if not isinstance(other, V1alpha1StepCircuitBreaker):
+            return False
+
+        return self.to_dict() == other.to_dict()
+
+    def __ne__(self, other):
+        """Returns true if both objects are not equal"""
+        if not isinstance(other, V1alpha1StepCircuitBreaker):
+            return True
+
+        return self.to_dict() != other.to_dict()
@@ -0,0 +1,192 @@
+# Copyright 2023 The KServe Authors.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#    http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# coding: utf-8
+
+"""
+    KServe
+
+    Python SDK for KServe  # noqa: E501
+
+    The version of the OpenAPI document: v0.1
+    Generated by: https://openapi-generator.tech
+"""
+
+
+import pprint
+import re  # noqa: F401
+
+import six
+
+from kserve.configuration import Configuration
+
+
+class V1alpha1StepRetryPolicy(object):
+    """NOTE: This class is auto generated by OpenAPI Generator.
+    Ref: https://openapi-generator.tech
+
+    Do not edit the class manually.
+    """
+
+    """
+    Attributes:
+      openapi_types (dict): The key is attribute name
+                            and the value is attribute type.
+      attribute_map (dict): The key is attribute name
+                            and the value is json key in definition.
+    """
+    openapi_types = {
+        'backoff': 'int',
+        'max_attempts': 'int',
+        'retryable_status_codes': 'list[int]'
+    }
+
+    attribute_map = {
+        'backoff': 'backoff',
+        'max_attempts': 'maxAttempts',
+        'retryable_status_codes': 'retryableStatusCodes'
+    }
+
+    def __init__(self, backoff=None, max_attempts=None, retryable_status_codes=None, local_vars_configuration=None):  # noqa: E501
+        """V1alpha1StepRetryPolicy - a model defined in OpenAPI"""  # noqa: E501
+        if local_vars_configuration is None:
+            local_vars_configuration = Configuration()
+        self.local_vars_configuration = local_vars_configuration
+
+        self._backoff = None
+        self._max_attempts = None
+        self._retryable_status_codes = None
+        self.discriminator = None
+
+        if backoff is not None:
+            self.backoff = backoff
+        if max_attempts is not None:
+            self.max_attempts = max_attempts
+        if retryable_status_codes is not None:
+            self.retryable_status_codes = retryable_status_codes
+
+    @property
+    def backoff(self):
+        """Gets the backoff of this V1alpha1StepRetryPolicy.  # noqa: E501
+
+        Backoff in milliseconds before the first retry, doubled for every further retry up to 60 seconds. Defaults to 100 and at most 60000  # noqa: E501
+
+        :return: The backoff of this V1alpha1StepRetryPolicy.  # noqa: E501
+        :rtype: int
+        """
+        return self._backoff
+
+    @backoff.setter
+    def backoff(self, backoff):
+        """Sets the backoff of this V1alpha1StepRetryPolicy.
+
+        Backoff in milliseconds before the first retry, doubled for every further retry up to 60 seconds. Defaults to 100 and at most 60000  # noqa: E501
+
+        :param backoff: The backoff of this V1alpha1StepRetryPolicy.  # noqa: E501
+        :type: int
+        """
+
+        self._backoff = backoff
+
+    @property
+    def max_attempts(self):
+        """Gets the max_attempts of this V1alpha1StepRetryPolicy.  # noqa: E501
+
+        Maximum number of attempts including the first call, defaults to 1 and at most 10  # noqa: E501
+
+        :return: The max_attempts of this V1alpha1StepRetryPolicy.  # noqa: E501
+        :rtype: int
+        """
+        return self._max_attempts
+
+    @max_attempts.setter
+    def max_attempts(self, max_attempts):
+        """Sets the max_attempts of this V1alpha1StepRetryPolicy.
+
+        Maximum number of attempts including the first call, defaults to 1 and at most 10  # noqa: E501
+
+        :param max_attempts: The max_attempts of this V1alpha1StepRetryPolicy.  # noqa: E501
+        :type: int
+        """
+
+        self._max_attempts = max_attempts
+
+    @property
+    def retryable_status_codes(self):
+        """Gets the retryable_status_codes of this V1alpha1StepRetryPolicy.  # noqa: E501
+
+        Response status codes which are retried, defaults to 502, 503 and 504  # noqa: E501
+
+        :return: The retryable_status_codes of this V1alpha1StepRetryPolicy.  # noqa: E501
+        :rtype: list[int]
+        """
+        return self._retryable_status_codes
+
+    @retryable_status_codes.setter
+    def retryable_status_codes(self, retryable_status_codes):
+        """Sets the retryable_status_codes of this V1alpha1StepRetryPolicy.
+
+        Response status codes which are retried, defaults to 502, 503 and 504  # noqa: E501
+
+        :param retryable_status_codes: The retryable_status_codes of this V1alpha1StepRetryPolicy.  # noqa: E501
+        :type: list[int]
+        """
+
+        self._retryable_status_codes = retryable_status_codes
+
+    def to_dict(self):
+        """Returns the model properties as a dict"""
+        result = {}
+
+        for attr, _ in six.iteritems(self.openapi_types):
+            value = getattr(self, attr)
+            if isinstance(value, list):
+                result[attr] = list(map(
+                    lambda x: x.to_dict() if hasattr(x, "to_dict") else x,
+                    value
+                ))
+            elif hasattr(value, "to_dict"):
+                result[attr] = value.to_dict()
+            elif isinstance(value, dict):
+                result[attr] = dict(map(
+                    lambda item: (item[0], item[1].to_dict())
+                    if hasattr(item[1], "to_dict") else item,
+                    value.items()
+                ))
+            else:
+                result[attr] = value
+
+        return result
+
+    def to_str(self):
+        """Returns the string representation of the model"""
+        return pprint.pformat(self.to_dict())
+
+    def __repr__(self):
+        """For `print` and `pprint`"""
+        return self.to_str()
+
+    def __eq__(self, other):
+        """Returns true if both objects are equal"""
+        if not isinstance(other, V1alpha1StepRetryPolicy):
+            return False
+
+        return self.to_dict() == other.to_dict()
+
+    def __ne__(self, other):
+        """Returns true if both objects are not equal"""
+        if not isinstance(other, V1alpha1StepRetryPolicy):
+            return True
+
+        return self.to_dict() != other.to_dict()
@@ -49,16 +49,18 @@ class V1beta1Batcher(object):
     openapi_types = {
         'max_batch_size': 'int',
         'max_latency': 'int',
+        'target_latency': 'int',
         'timeout': 'int'
     }
 
     attribute_map = {
         'max_batch_size': 'maxBatchSize',
         'max_latency': 'maxLatency',
+        'target_latency': 'targetLatency',
         'timeout': 'timeout'
     }
 
-    def __init__(self, max_batch_size=None, max_latency=None, timeout=None, local_vars_configuration=None):  # noqa: E501
+    def __init__(self, max_batch_size=None, max_latency=None, target_latency=None, timeout=None, local_vars_configuration=None):  # noqa: E501
         """V1beta1Batcher - a model defined in OpenAPI"""  # noqa: E501
         if local_vars_configuration is None:
             local_vars_configuration = Configuration()
@@ -66,6 +68,7 @@ class V1beta1Batcher(object):
 
         self._max_batch_size = None
         self._max_latency = None
+        self._target_latency = None
         self._timeout = None
         self.discriminator = None
 
@@ -73,6 +76,8 @@ class V1beta1Batcher(object):
             self.max_batch_size = max_batch_size
         if max_latency is not None:
             self.max_latency = max_latency
+        if target_latency is not None:
+            self.target_latency = target_latency
         if timeout is not None:
             self.timeout = timeout
 
@@ -122,6 +127,29 @@ class V1beta1Batcher(object):
 
         self._max_latency = max_latency
 
+    @property
+    def target_latency(self):
+        """Gets the target_latency of this V1beta1Batcher.  # noqa: E501
+
+        Specifies the target p99 latency in milliseconds of the batched requests, the batch window adapts to the observed predictor latency and queue depth to meet it. MaxBatchSize and MaxLatency still bound the batch.  # noqa: E501
+
+        :return: The target_latency of this V1beta1Batcher.  # noqa: E501
+        :rtype: int
+        """
+        return self._target_latency
+
+    @target_latency.setter
+    def target_latency(self, target_latency):
+        """Sets the target_latency of this V1beta1Batcher.
+
+        Specifies the target p99 latency in milliseconds of the batched requests, the batch window adapts to the observed predictor latency and queue depth to meet it. MaxBatchSize and MaxLatency still bound the batch.  # noqa: E501
+
+        :param target_latency: The target_latency of this V1beta1Batcher.  # noqa: E501
+        :type: int
+        """
+
+        self._target_latency = target_latency
+
     @property
     def timeout(self):
         """Gets the timeout of this V1beta1Batcher.  # noqa: E501
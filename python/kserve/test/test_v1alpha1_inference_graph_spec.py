@@ -50,9 +50,15 @@ class TestV1alpha1InferenceGraphSpec(unittest.TestCase):
         # model = kserve.models.v1alpha1_inference_graph_spec.V1alpha1InferenceGraphSpec()  # noqa: E501
         if include_optional :
             return V1alpha1InferenceGraphSpec(
+                affinity = None, 
+                max_replicas = 56, 
+                min_replicas = 56, 
                 nodes = {
                     'key' : None
-                    }
+                    }, 
+                resources = None, 
+                scale_metric = '0', 
+                scale_target = 56
             )
         else :
             return V1alpha1InferenceGraphSpec(
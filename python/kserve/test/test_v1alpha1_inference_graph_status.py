@@ -57,6 +57,14 @@ class TestV1alpha1InferenceGraphStatus(unittest.TestCase):
                     None
                     ], 
                 observed_generation = 56, 
+                services = [
+                    kserve.models.v1alpha1_inference_graph_service_status.V1alpha1InferenceGraphServiceStatus(
+                        hard = True, 
+                        last_transition_time = None, 
+                        ready = True, 
+                        service_name = '0', 
+                        url = None, )
+                    ], 
                 url = None
             )
         else :
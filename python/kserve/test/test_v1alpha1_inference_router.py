@@ -50,15 +50,29 @@ class TestV1alpha1InferenceRouter(unittest.TestCase):
         # model = kserve.models.v1alpha1_inference_router.V1alpha1InferenceRouter()  # noqa: E501
         if include_optional :
             return V1alpha1InferenceRouter(
+                combiner = None, 
+                failure_policy = None, 
+                for_each = None, 
+                hash_key = None, 
+                protocol_version = '0', 
                 router_type = '0', 
+                seed = 56, 
                 steps = [
                     kserve.models.v1alpha1_inference_step.V1alpha1InferenceStep(
+                        auth = None, 
+                        cache = None, 
+                        circuit_breaker = None, 
                         condition = '0', 
                         data = '0', 
+                        dependency = '0', 
+                        mirror = None, 
                         name = '0', 
                         node_name = '0', 
+                        protocol_version = '0', 
+                        retry = None, 
                         service_name = '0', 
                         service_url = '0', 
+                        timeout = 56, 
                         weight = 56, )
                     ]
             )
@@ -52,6 +52,7 @@ class TestV1beta1Batcher(unittest.TestCase):
             return V1beta1Batcher(
                 max_batch_size = 56, 
                 max_latency = 56, 
+                target_latency = 56, 
                 timeout = 56
             )
         else :